@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newActorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actors <owner>/<repo>",
+		Short: "Break down CI time, runs and failures by triggering actor and event",
+		Long: heredoc.Doc(`
+			Attribute workflow runs to the account that triggered them and report, per actor, the
+			runner time consumed, the number of runs and the failure rate. Actors are also grouped
+			into bots and humans, and runs are grouped by triggering event.
+
+			Accounts of type Bot, logins ending in [bot] and well-known automation accounts
+			(dependabot, renovate, github-actions) count as bots.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			report := metrics.Attribution(collection.records, collection.from, collection.to, policy)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.ByActor) > top {
+				report.ByActor = report.ByActor[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownActors(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredActors(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Int(flagTop, 20, "Show only the N actors with the most CI time (0 shows all; JSON always includes all)")
+
+	return cmd
+}
+
+func actorsOverview(report metrics.AttributionReport) string {
+	bots := output.FormatFailureRate(0)
+	for _, row := range report.ByKind {
+		if row.Name == metrics.ActorKindBot {
+			bots = output.FormatFailureRate(row.Share)
+		}
+	}
+	return fmt.Sprintf("%d runs · %s CI time · %s of it triggered by bots", report.Runs, output.FormatDuration(report.CITime), bots)
+}
+
+// attributionCells returns the Runs, Failed, CI Time and Share cells of a row.
+func attributionCells(row metrics.AttributionRow) []string {
+	return []string{
+		fmt.Sprintf("%d", row.Runs),
+		fmt.Sprintf("%d (%s)", row.Failed, output.FormatFailureRate(row.FailureRate)),
+		output.FormatDuration(row.CITime),
+		output.FormatFailureRate(row.Share),
+	}
+}
+
+func newAttributionTable(w io.Writer, header []string, leading ...tablewriter.Colors) *tablewriter.Table {
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(append(leading,
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+	)...)
+	return table
+}
+
+func renderColoredActors(w io.Writer, report metrics.AttributionReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "👤 Trigger Attribution")
+	fmt.Fprintln(w)
+
+	if report.Runs == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs found in the specified time range")
+		return
+	}
+
+	overviewColor := color.New(color.FgHiBlack)
+	overviewColor.Fprintln(w, actorsOverview(report))
+	fmt.Fprintln(w)
+
+	kinds := newAttributionTable(w, []string{"Actor Type", "Runs", "Failed", "CI Time", "Share"},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor})
+	for _, row := range report.ByKind {
+		kinds.Append(append([]string{row.Name}, attributionCells(row)...))
+	}
+	kinds.Render()
+
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🧑 By Actor")
+	actors := newAttributionTable(w, []string{"Actor", "Type", "Events", "Runs", "Failed", "CI Time", "Share"},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{},
+		tablewriter.Colors{tablewriter.FgHiBlackColor})
+	for _, row := range report.ByActor {
+		kind := row.Kind
+		if kind == metrics.ActorKindBot {
+			kind = color.YellowString(kind)
+		}
+		actors.Append(append([]string{row.Name, kind, strings.Join(row.Events, ", ")}, attributionCells(row)...))
+	}
+	actors.Render()
+
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "⚡ By Event")
+	events := newAttributionTable(w, []string{"Event", "Runs", "Failed", "CI Time", "Share"},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor})
+	for _, row := range report.ByEvent {
+		events.Append(append([]string{row.Name}, attributionCells(row)...))
+	}
+	events.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownActors(w io.Writer, report metrics.AttributionReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Trigger Attribution")
+	fmt.Fprintln(w)
+
+	if report.Runs == 0 {
+		fmt.Fprintln(w, "_No workflow runs found in the specified time range._")
+		return
+	}
+
+	fmt.Fprintln(w, actorsOverview(report))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Actor Type | Runs | Failed | CI Time | Share |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: |")
+	for _, row := range report.ByKind {
+		cells := attributionCells(row)
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", escapeMarkdownCell(row.Name), cells[0], cells[1], cells[2], cells[3])
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## By Actor")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Actor | Type | Events | Runs | Failed | CI Time | Share |")
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | ---: | ---: |")
+	for _, row := range report.ByActor {
+		cells := attributionCells(row)
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(row.Name), row.Kind, strings.Join(row.Events, ", "), cells[0], cells[1], cells[2], cells[3])
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## By Event")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Event | Runs | Failed | CI Time | Share |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: |")
+	for _, row := range report.ByEvent {
+		cells := attributionCells(row)
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", escapeMarkdownCell(row.Name), cells[0], cells[1], cells[2], cells[3])
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/check"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagCheckRule      = "rule"
+	flagCheckRulesFile = "rules-file"
+)
+
+// errThresholdViolated is returned by the check command when at least one rule
+// is violated so that the process exits with a non-zero status.
+var errThresholdViolated = errors.New("threshold check failed")
+
+// errNothingChecked is returned by the check command when a rule was not
+// evaluated against any workflow or job, so that a typo in --workflow or in a
+// rule target cannot make the check pass.
+var errNothingChecked = errors.New("nothing to check")
+
+type checkReport struct {
+	Rules      []check.Rule      `json:"rules"`
+	Workflows  int               `json:"workflows"`
+	Violations []check.Violation `json:"violations"`
+}
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check <owner>/<repo>",
+		Short: "Fail when aggregated metrics violate thresholds",
+		Long: heredoc.Doc(`
+			Evaluate threshold rules against the aggregated summary and exit with a non-zero
+			status when any rule is violated.
+
+			Rules have the form "[workflow[<name>]:|job[<name>]:] <metric> <op> <threshold>",
+			for example:
+
+			  failure_rate < 5%
+			  p95(duration) < 15m
+			  job: queue_p90 < 2m
+			  workflow[Release]: failed == 0
+			  job[test]: p95(duration) < 20m
+
+			Supported metrics are failure_rate, runs, failed, avg(duration), total(duration),
+			p50/p90/p95/p99(duration) and p50/p90/p95/p99(queue). Rules apply to every
+			selected workflow, or to every job when prefixed with "job:"; a bracketed name
+			restricts a rule to the workflow or job with that name. The check fails when no
+			workflow had runs in the window or a rule matched no workflow or job.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			rules, err := loadCheckRules(cmd)
+			if err != nil {
+				return err
+			}
+			if len(rules) == 0 {
+				return fmt.Errorf("at least one --%s or --%s is required", flagCheckRule, flagCheckRulesFile)
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w: no workflows matched", errNothingChecked)
+			}
+
+			summary := metrics.AggregateWithPolicy(collection.records, collection.from, collection.to, policy)
+			if len(summary) == 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w: no workflow runs in the selected window", errNothingChecked)
+			}
+			if unmatched := check.Unmatched(rules, summary); len(unmatched) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w: rule %q matched no %s", errNothingChecked, unmatched[0].Raw, unmatched[0].Scope)
+			}
+			report := checkReport{
+				Rules:      rules,
+				Workflows:  len(summary),
+				Violations: check.Evaluate(rules, summary),
+			}
+			if report.Violations == nil {
+				report.Violations = []check.Violation{}
+			}
+
+			switch {
+			case viper.GetBool(flagJSON):
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			case viper.GetBool(flagMarkdown):
+				renderMarkdownCheck(stdout, report)
+			default:
+				terminal := term.FromEnv()
+				renderColoredCheck(os.Stdout, report, terminal.IsColorEnabled())
+			}
+
+			if len(report.Violations) > 0 {
+				// The violation report is the useful output; usage text would bury it.
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w: %d violation(s)", errThresholdViolated, len(report.Violations))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArray(flagCheckRule, nil, `Threshold rule, e.g. "failure_rate < 5%" (repeatable)`)
+	cmd.Flags().String(flagCheckRulesFile, "", "Read rules from a file (one per line, # for comments)")
+	addRunLimitFlag(cmd)
+
+	return cmd
+}
+
+func loadCheckRules(cmd *cobra.Command) ([]check.Rule, error) {
+	rawRules, err := cmd.Flags().GetStringArray(flagCheckRule)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]check.Rule, 0, len(rawRules))
+	for _, raw := range rawRules {
+		rule, err := check.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	path, err := cmd.Flags().GetString(flagCheckRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	if path = strings.TrimSpace(path); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open rules file: %w", err)
+		}
+		defer file.Close()
+
+		fileRules, err := check.ParseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return rules, nil
+}
+
+func violationTarget(v check.Violation) string {
+	if v.Job != "" {
+		return fmt.Sprintf("%s › %s", v.Workflow, v.Job)
+	}
+	return v.Workflow
+}
+
+func renderColoredCheck(w io.Writer, report checkReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🚦 Threshold Check")
+	fmt.Fprintln(w)
+
+	if len(report.Violations) == 0 {
+		okColor := color.New(color.FgGreen, color.Bold)
+		okColor.Fprintf(w, "✓ All %d rules passed for %d workflows\n", len(report.Rules), report.Workflows)
+		fmt.Fprintln(w)
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Target", "Rule", "Actual"})
+	table.SetBorder(true)
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
+	)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+	)
+
+	for _, v := range report.Violations {
+		table.Append([]string{
+			violationTarget(v),
+			v.Rule.Raw,
+			v.Rule.FormatValue(v.Value),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+
+	failColor := color.New(color.FgRed, color.Bold)
+	failColor.Fprintf(w, "✗ %d violation(s) of %d rules across %d workflows\n", len(report.Violations), len(report.Rules), report.Workflows)
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownCheck(w io.Writer, report checkReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Threshold Check")
+	fmt.Fprintln(w)
+
+	if len(report.Violations) == 0 {
+		fmt.Fprintf(w, "✅ All %d rules passed for %d workflows.\n", len(report.Rules), report.Workflows)
+		return
+	}
+
+	fmt.Fprintf(w, "❌ %d violation(s) of %d rules across %d workflows.\n", len(report.Violations), len(report.Rules), report.Workflows)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Target | Rule | Actual |")
+	fmt.Fprintln(w, "| --- | --- | ---: |")
+	for _, v := range report.Violations {
+		fmt.Fprintf(w, "| %s | `%s` | %s |\n",
+			escapeMarkdownCell(violationTarget(v)),
+			escapeMarkdownCell(v.Rule.Raw),
+			v.Rule.FormatValue(v.Value),
+		)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/briandowns/spinner"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// runCollection holds the workflow runs (and their jobs) fetched for the
+// selected workflows of a repository, along with the effective reporting
+// window.
+type runCollection struct {
+	owner     string
+	repo      string
+	workflows []githubapi.Workflow
+	records   []metrics.RunRecord
+	from      time.Time
+	to        time.Time
+}
+
+// collectOptions narrows which runs collectRuns fetches.
+type collectOptions struct {
+	// runLimit fetches only the most recent runs per workflow when positive.
+	runLimit int
+	// branch overrides the --branch flag when non-empty.
+	branch string
+	// quiet suppresses the progress spinner and selection warnings, for
+	// callers that own the terminal.
+	quiet bool
+}
+
+// newAPIClient creates a GitHub API client honoring the cache flags.
+func newAPIClient() (*githubapi.Client, error) {
+	cacheTTL := viper.GetDuration(flagCacheTTL)
+	enableCache := cacheTTL > 0 && !viper.GetBool(flagNoCache)
+
+	logCacheTTL := viper.GetDuration(flagLogCache)
+	if viper.GetBool(flagNoCache) {
+		logCacheTTL = 0
+	}
+
+	client, err := githubapi.NewClient(githubapi.Options{CacheTTL: cacheTTL, EnableCache: enableCache, LogCacheTTL: logCacheTTL})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return client, nil
+}
+
+// collectRuns resolves the reporting window and workflow selection from the
+// persistent flags and fetches matching runs with their jobs. When
+// opts.runLimit is positive only the most recent runs per workflow are fetched
+// and the window is narrowed to the span of those runs. A nil collection is
+// returned when no workflow matched the selection.
+func collectRuns(ctx context.Context, client *githubapi.Client, owner, repo string, opts collectOptions) (*runCollection, error) {
+	now := time.Now().UTC()
+	from, to, err := resolveTimeRange(now, viper.GetString(flagFrom), viper.GetString(flagTo), viper.GetString(flagLast))
+	if err != nil {
+		return nil, err
+	}
+
+	allWorkflows, err := client.ListWorkflows(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows for %s/%s: %w", owner, repo, err)
+	}
+
+	// Filter out GitHub-hosted workflows
+	workflows := make([]githubapi.Workflow, 0, len(allWorkflows))
+	for _, wf := range allWorkflows {
+		if strings.HasPrefix(wf.Path, "dynamic") {
+			continue
+		}
+		workflows = append(workflows, wf)
+	}
+
+	selected, err := filterWorkflows(workflows, mustGetStringSlice(flagWorkflow))
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		if !opts.quiet {
+			fmt.Fprintf(stderr, "No workflows in %s/%s matched the current selection.\n", owner, repo)
+		}
+		return nil, nil
+	}
+
+	createdFilter := fmt.Sprintf("%s..%s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	runFilter := githubapi.WorkflowRunFilter{
+		Branch:  viper.GetString(flagBranch),
+		Status:  viper.GetString(flagStatus),
+		Created: createdFilter,
+	}
+	if opts.branch != "" {
+		runFilter.Branch = opts.branch
+	}
+	runLimit := opts.runLimit
+	if runLimit > 0 {
+		runFilter.Created = ""
+	}
+
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		records []metrics.RunRecord
+	)
+
+	// Start spinner for fetching workflow runs
+	terminal := term.FromEnv()
+	var s *spinner.Spinner
+	if terminal.IsTerminalOutput() && !opts.quiet {
+		s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Fetching workflow runs for %d workflows...", len(selected))
+		s.Start()
+	}
+
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, wf := range selected {
+		workflow := wf
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			runs, err := client.ListWorkflowRuns(gctx, owner, repo, workflow.ID, runFilter, runLimit)
+			if err != nil {
+				return fmt.Errorf("workflow %s: %w", workflow.Name, err)
+			}
+
+			for _, run := range runs {
+				jobs, jobErr := client.ListJobs(gctx, owner, repo, run.ID)
+				if jobErr != nil {
+					slog.Warn("failed to fetch jobs", slog.String("workflow", workflow.Name), slog.Int64("run", run.ID), slog.String("error", jobErr.Error()))
+				}
+				mu.Lock()
+				records = append(records, metrics.RunRecord{Workflow: workflow, Run: run, Jobs: jobs})
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+
+	err = g.Wait()
+	if s != nil {
+		s.Stop()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.MarkSuperseded(records)
+
+	if runLimit > 0 && len(records) > 0 {
+		var earliest, latest time.Time
+		for _, rec := range records {
+			runTime := rec.Run.RunStartedAt
+			if runTime.IsZero() {
+				runTime = rec.Run.CreatedAt
+			}
+			if runTime.IsZero() {
+				continue
+			}
+			if earliest.IsZero() || runTime.Before(earliest) {
+				earliest = runTime
+			}
+			if latest.IsZero() || runTime.After(latest) {
+				latest = runTime
+			}
+		}
+		if !earliest.IsZero() {
+			from = earliest
+		} else {
+			from = time.Time{}
+		}
+		if !latest.IsZero() {
+			to = latest
+		} else {
+			to = time.Now().UTC()
+		}
+		if to.Before(from) {
+			to = from
+		}
+	}
+
+	return &runCollection{
+		owner:     owner,
+		repo:      repo,
+		workflows: selected,
+		records:   records,
+		from:      from,
+		to:        to,
+	}, nil
+}
+
+// getRunLimit reads and validates the --runs flag of a command.
+// addRunLimitFlag registers the --runs flag read by getRunLimit.
+func addRunLimitFlag(cmd *cobra.Command) {
+	cmd.Flags().Int(flagSummaryRuns, 0, "Fetch only the most recent N runs per workflow (overrides time range filters)")
+}
+
+func getRunLimit(cmd *cobra.Command) (int, error) {
+	runLimit, err := cmd.Flags().GetInt(flagSummaryRuns)
+	if err != nil {
+		return 0, err
+	}
+	if runLimit < 0 {
+		return 0, fmt.Errorf("--%s must be greater than or equal to 0", flagSummaryRuns)
+	}
+	return runLimit, nil
+}
+
+func getTop(cmd *cobra.Command) (int, error) {
+	top, err := cmd.Flags().GetInt(flagTop)
+	if err != nil {
+		return 0, err
+	}
+	if top < 0 {
+		return 0, fmt.Errorf("--%s must be greater than or equal to 0", flagTop)
+	}
+	return top, nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newCommitsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commits <owner>/<repo>",
+		Short: "Roll up runs across workflows per commit",
+		Long: heredoc.Doc(`
+			Group workflow runs by head commit and report, per commit, the wall-clock time from the
+			first run being created until every workflow finished, the combined conclusion, and the
+			workflow that took longest. This is the latency a developer experiences after pushing.
+
+			Each workflow is judged by its latest run on the commit, so a successful re-run replaces
+			the attempt that failed.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			report := metrics.Commits(collection.records, collection.from, collection.to, policy)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Rows) > top {
+				report.Rows = report.Rows[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownCommits(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredCommits(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Int(flagTop, 20, "Show only the N most recent commits (0 shows all; JSON always includes all)")
+
+	return cmd
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func commitsOverview(report metrics.CommitReport) string {
+	overview := fmt.Sprintf("%d commits · median wall clock %s (p90 %s)",
+		report.Commits, output.FormatDuration(report.MedianWallClock), output.FormatDuration(report.P90WallClock))
+	if len(report.Slowest) == 0 {
+		return overview
+	}
+	parts := make([]string, 0, len(report.Slowest))
+	for _, s := range report.Slowest {
+		parts = append(parts, fmt.Sprintf("%s (%d)", s.Workflow, s.Commits))
+	}
+	return overview + " · slowest workflow: " + strings.Join(parts, ", ")
+}
+
+func renderColoredCommits(w io.Writer, report metrics.CommitReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "📌 Commit Rollup")
+	fmt.Fprintln(w)
+
+	if report.Commits == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs found in the specified time range")
+		return
+	}
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprintln(w, commitsOverview(report))
+	fmt.Fprintln(w)
+
+	header := []string{"Commit", "Branch", "Workflows", "Runs", "Conclusion", "Wall Clock", "Slowest Workflow", "Slowest Took"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+	)
+
+	for _, row := range report.Rows {
+		table.Append([]string{
+			shortSHA(row.SHA),
+			row.Branch,
+			fmt.Sprintf("%d", row.Workflows),
+			fmt.Sprintf("%d", row.Runs),
+			row.Conclusion,
+			output.FormatDuration(row.WallClock),
+			row.SlowestWorkflow,
+			output.FormatDuration(row.SlowestDuration),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownCommits(w io.Writer, report metrics.CommitReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Commit Rollup")
+	fmt.Fprintln(w)
+
+	if report.Commits == 0 {
+		fmt.Fprintln(w, "_No workflow runs found in the specified time range._")
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n\n", commitsOverview(report))
+	fmt.Fprintln(w, "| Commit | Branch | Workflows | Runs | Conclusion | Wall Clock | Slowest Workflow | Slowest Took |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | --- | ---: | --- | ---: |")
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "| `%s` | %s | %d | %d | %s | %s | %s | %s |\n",
+			shortSHA(row.SHA),
+			escapeMarkdownCell(row.Branch),
+			row.Workflows,
+			row.Runs,
+			row.Conclusion,
+			output.FormatDuration(row.WallClock),
+			escapeMarkdownCell(row.SlowestWorkflow),
+			output.FormatDuration(row.SlowestDuration),
+		)
+	}
+	fmt.Fprintln(w)
+}
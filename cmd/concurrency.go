@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagConcurrencyBucket = "bucket"
+	flagConcurrencyLimit  = "limit"
+
+	// busiestPeriods is how many buckets of the overall series are listed.
+	busiestPeriods = 5
+)
+
+func newConcurrencyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "concurrency <owner>/<repo>",
+		Short: "Report how many jobs run at once, per runner label, over time",
+		Long: heredoc.Doc(`
+			Rebuild how many jobs were running at the same time from job start and end timestamps,
+			overall and for every runner label the jobs requested. Report the maximum, the
+			time-weighted p95 and the time-weighted average, and list the busiest periods.
+
+			The full time series, bucketed by --bucket, is included in the JSON output and written
+			by --csv. Pass the organization's concurrent job limit with --limit to see how long
+			jobs ran at or above it.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			bucket, err := cmd.Flags().GetDuration(flagConcurrencyBucket)
+			if err != nil {
+				return err
+			}
+			if bucket < time.Minute {
+				return fmt.Errorf("--%s must be at least 1m", flagConcurrencyBucket)
+			}
+
+			limit, err := cmd.Flags().GetInt(flagConcurrencyLimit)
+			if err != nil {
+				return err
+			}
+			if limit < 0 {
+				return fmt.Errorf("--%s must be greater than or equal to 0", flagConcurrencyLimit)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			series := metrics.Concurrency(collection.records, collection.from, collection.to, bucket, limit)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(series)
+			}
+
+			if csvPath := strings.TrimSpace(viper.GetString(flagCSV)); csvPath != "" {
+				return exportConcurrencyCSV(series, csvPath)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownConcurrency(stdout, series, limit)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredConcurrency(os.Stdout, series, limit, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Duration(flagConcurrencyBucket, time.Hour, "Width of each time series bucket")
+	cmd.Flags().Int(flagConcurrencyLimit, 0, "Concurrent job limit to compare against, e.g. your plan's limit (0 disables)")
+
+	return cmd
+}
+
+func exportConcurrencyCSV(series []metrics.ConcurrencySeries, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create csv file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"label", "start", "max", "average"}); err != nil {
+		return err
+	}
+	for _, s := range series {
+		for _, point := range s.Points {
+			record := []string{
+				s.Label,
+				point.Start.Format(time.RFC3339),
+				strconv.Itoa(point.Max),
+				fmt.Sprintf("%.4f", point.Average),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// busiestPoints returns the buckets of the overall series with the highest
+// average concurrency, in time order.
+func busiestPoints(series []metrics.ConcurrencySeries) []metrics.ConcurrencyPoint {
+	if len(series) == 0 || series[0].Label != metrics.AllLabels {
+		return nil
+	}
+	points := append([]metrics.ConcurrencyPoint(nil), series[0].Points...)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Average > points[j].Average })
+	for len(points) > 0 && points[len(points)-1].Max == 0 {
+		points = points[:len(points)-1]
+	}
+	if len(points) > busiestPeriods {
+		points = points[:busiestPeriods]
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Start.Before(points[j].Start) })
+	return points
+}
+
+func formatPeakAt(s metrics.ConcurrencySeries) string {
+	if s.Max == 0 {
+		return "-"
+	}
+	return s.PeakAt.Format("2006-01-02 15:04")
+}
+
+func renderColoredConcurrency(w io.Writer, series []metrics.ConcurrencySeries, limit int, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "📈 Job Concurrency")
+	fmt.Fprintln(w)
+
+	if len(series) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No jobs with timing data found in the specified time range")
+		return
+	}
+
+	header := []string{"Label", "Jobs", "Max", "P95", "Average", "Peak At"}
+	columnColors := []tablewriter.Colors{
+		{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		{tablewriter.FgGreenColor},
+		{tablewriter.FgRedColor},
+		{tablewriter.FgYellowColor},
+		{tablewriter.FgBlueColor},
+		{tablewriter.FgHiBlackColor},
+	}
+	if limit > 0 {
+		header = append(header, fmt.Sprintf("At Limit (%d)", limit))
+		columnColors = append(columnColors, tablewriter.Colors{tablewriter.FgMagentaColor})
+	}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(columnColors...)
+
+	for _, s := range series {
+		cells := []string{
+			s.Label,
+			fmt.Sprintf("%d", s.Jobs),
+			fmt.Sprintf("%d", s.Max),
+			fmt.Sprintf("%d", s.P95),
+			fmt.Sprintf("%.2f", s.Average),
+			formatPeakAt(s),
+		}
+		if limit > 0 {
+			cells = append(cells, output.FormatDuration(s.AtLimit))
+		}
+		table.Append(cells)
+	}
+	table.Render()
+	fmt.Fprintln(w)
+
+	points := busiestPoints(series)
+	if len(points) == 0 {
+		return
+	}
+
+	periodTitle := color.New(color.FgHiWhite, color.Bold)
+	periodTitle.Fprintln(w, "🔥 Busiest Periods")
+	labelColor := color.New(color.FgHiBlack)
+	for _, point := range points {
+		fmt.Fprintf(w, "  • %s ", point.Start.Format("2006-01-02 15:04"))
+		labelColor.Fprintf(w, "average %.2f, max %d\n", point.Average, point.Max)
+	}
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownConcurrency(w io.Writer, series []metrics.ConcurrencySeries, limit int) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Job Concurrency")
+	fmt.Fprintln(w)
+
+	if len(series) == 0 {
+		fmt.Fprintln(w, "_No jobs with timing data found in the specified time range._")
+		return
+	}
+
+	if limit > 0 {
+		fmt.Fprintf(w, "| Label | Jobs | Max | P95 | Average | Peak At | At Limit (%d) |\n", limit)
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | --- | ---: |")
+	} else {
+		fmt.Fprintln(w, "| Label | Jobs | Max | P95 | Average | Peak At |")
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | --- |")
+	}
+	for _, s := range series {
+		fmt.Fprintf(w, "| %s | %d | %d | %d | %.2f | %s |", escapeMarkdownCell(s.Label), s.Jobs, s.Max, s.P95, s.Average, formatPeakAt(s))
+		if limit > 0 {
+			fmt.Fprintf(w, " %s |", output.FormatDuration(s.AtLimit))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+
+	points := busiestPoints(series)
+	if len(points) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## Busiest Periods")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Start | Average | Max |")
+	fmt.Fprintln(w, "| --- | ---: | ---: |")
+	for _, point := range points {
+		fmt.Fprintf(w, "| %s | %.2f | %d |\n", point.Start.Format("2006-01-02 15:04"), point.Average, point.Max)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newCriticalPathCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "critical-path <owner>/<repo>",
+		Short: "Report which jobs determine each workflow's wall-clock time",
+		Long: heredoc.Doc(`
+			Infer the critical path of every run: the chain of jobs, each waiting on the previous one,
+			that ended with the last job to finish. Dependencies are inferred from job timing unless
+			workflow files are read with --workflow-dir or --fetch-workflow-files, in which case the
+			needs of each job are used.
+
+			For each workflow, report how often every job sits on that path and, for the runs where it
+			does not, its median slack: how much longer the job could have taken without delaying the
+			run.
+
+			Speeding up jobs that are usually on the critical path shortens the run; speeding up jobs
+			with a large slack does not.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			files, err := loadWorkflowFiles(ctx, cmd, client, collection)
+			if err != nil {
+				return err
+			}
+
+			rows := metrics.CriticalPaths(collection.records, collection.from, collection.to, files)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(rows)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownCriticalPaths(stdout, rows)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredCriticalPaths(os.Stdout, rows, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	addWorkflowFileFlags(cmd)
+
+	return cmd
+}
+
+func formatSlack(job metrics.CriticalPathJob) string {
+	if job.OnPath == job.Runs {
+		return "-"
+	}
+	return output.FormatDuration(job.MedianSlack)
+}
+
+func renderColoredCriticalPaths(w io.Writer, rows []metrics.CriticalPathRow, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🧭 Critical Path")
+	fmt.Fprintln(w)
+
+	if len(rows) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs with job data found in the specified time range")
+		return
+	}
+
+	workflowTitle := color.New(color.FgHiWhite, color.Bold)
+	labelColor := color.New(color.FgHiBlack)
+	headerColors := make([]tablewriter.Colors, 6)
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	for _, row := range rows {
+		workflowTitle.Fprintf(w, "🔧 %s\n", row.Workflow)
+		labelColor.Fprintf(w, "%d runs · avg path %s · most common path (%d/%d runs): ",
+			row.Runs, output.FormatDuration(row.AvgPathDuration), row.CommonPathRuns, row.Runs)
+		fmt.Fprintln(w, strings.Join(row.CommonPath, " → "))
+
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Job", "Runs", "On Path", "On Path %", "Avg Duration", "Median Slack"})
+		table.SetBorder(true)
+		table.SetHeaderColor(headerColors...)
+		table.SetColumnColor(
+			tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgGreenColor},
+			tablewriter.Colors{tablewriter.FgRedColor},
+			tablewriter.Colors{tablewriter.FgRedColor},
+			tablewriter.Colors{tablewriter.FgYellowColor},
+			tablewriter.Colors{tablewriter.FgMagentaColor},
+		)
+
+		for _, job := range row.Jobs {
+			table.Append([]string{
+				job.Job,
+				fmt.Sprintf("%d", job.Runs),
+				fmt.Sprintf("%d", job.OnPath),
+				output.FormatFailureRate(job.OnPathRate),
+				output.FormatDuration(job.AvgDuration),
+				formatSlack(job),
+			})
+		}
+
+		table.Render()
+		fmt.Fprintln(w)
+	}
+}
+
+func renderMarkdownCriticalPaths(w io.Writer, rows []metrics.CriticalPathRow) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Critical Path")
+	fmt.Fprintln(w)
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "_No workflow runs with job data found in the specified time range._")
+		return
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(w, "## %s\n\n", row.Workflow)
+		fmt.Fprintf(w, "%d runs, average path %s. Most common path (%d/%d runs): %s\n\n",
+			row.Runs, output.FormatDuration(row.AvgPathDuration), row.CommonPathRuns, row.Runs, strings.Join(row.CommonPath, " → "))
+		fmt.Fprintln(w, "| Job | Runs | On Path | On Path % | Avg Duration | Median Slack |")
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: |")
+		for _, job := range row.Jobs {
+			fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s |\n",
+				escapeMarkdownCell(job.Job),
+				job.Runs,
+				job.OnPath,
+				output.FormatFailureRate(job.OnPathRate),
+				output.FormatDuration(job.AvgDuration),
+				formatSlack(job),
+			)
+		}
+		fmt.Fprintln(w)
+	}
+}
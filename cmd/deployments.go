@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const flagEnvironment = "environment"
+
+func newDeploymentsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deployments <owner>/<repo>",
+		Short: "Report deployment frequency, lead time and approval wait per environment",
+		Long: heredoc.Doc(`
+			Match the deployments created in the window to the workflow runs that made them and
+			report, per environment, the DORA metrics deployment frequency, lead time for changes
+			(head commit to successful deployment) and change failure rate.
+
+			Jobs targeting protected environments wait for reviewers, and that wait is part of the
+			run duration. The wait is taken from the deployment's waiting status and reported
+			separately from the remaining run time. Deployments not made by the selected workflows
+			are ignored.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			environment, err := cmd.Flags().GetString(flagEnvironment)
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			histories, err := collectDeployments(ctx, client, collection, environment)
+			if err != nil {
+				return err
+			}
+
+			report := metrics.Deployments(collection.records, histories, collection.from, collection.to)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Deployments) > top {
+				report.Deployments = report.Deployments[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownDeployments(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredDeployments(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().String(flagEnvironment, "", "Only report deployments to this environment")
+	cmd.Flags().Int(flagTop, 20, "Show only the N most recent deployments (0 shows all; JSON always includes all)")
+
+	return cmd
+}
+
+// collectDeployments lists the deployments created since the start of the
+// window and fetches the statuses of those whose commit one of the collected
+// runs built. Deployments whose statuses cannot be fetched are skipped.
+func collectDeployments(ctx context.Context, client *githubapi.Client, collection *runCollection, environment string) ([]metrics.DeploymentHistory, error) {
+	deployments, err := client.ListDeployments(ctx, collection.owner, collection.repo, environment, collection.from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments for %s/%s: %w", collection.owner, collection.repo, err)
+	}
+
+	shas := make(map[string]struct{}, len(collection.records))
+	for _, rec := range collection.records {
+		shas[rec.Run.HeadSHA] = struct{}{}
+	}
+
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		mu        sync.Mutex
+		histories []metrics.DeploymentHistory
+	)
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, d := range deployments {
+		if _, ok := shas[d.SHA]; !ok {
+			continue
+		}
+		deployment := d
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			statuses, err := client.ListDeploymentStatuses(gctx, collection.owner, collection.repo, deployment.ID)
+			if err != nil {
+				slog.Warn("failed to fetch deployment statuses", slog.Int64("deployment", deployment.ID), slog.String("error", err.Error()))
+				return nil
+			}
+			mu.Lock()
+			histories = append(histories, metrics.DeploymentHistory{Deployment: deployment, Statuses: statuses})
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+func formatDeploymentPercentiles(median, p90 time.Duration) string {
+	if median == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s (p90 %s)", output.FormatDuration(median), output.FormatDuration(p90))
+}
+
+func formatDeploymentTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func formatDeploymentState(state string) string {
+	switch state {
+	case metrics.DeploymentSucceeded:
+		return color.GreenString(state)
+	case metrics.DeploymentFailed:
+		return color.RedString(state)
+	default:
+		return color.YellowString(state)
+	}
+}
+
+func environmentCells(row metrics.EnvironmentRow) []string {
+	return []string{
+		row.Environment,
+		fmt.Sprintf("%d", row.Deployments),
+		fmt.Sprintf("%.2f", row.PerDay),
+		fmt.Sprintf("%d (%s)", row.Failed, output.FormatFailureRate(row.ChangeFailureRate)),
+		fmt.Sprintf("%d", row.Approvals),
+		formatDeploymentPercentiles(row.MedianApprovalWait, row.P90ApprovalWait),
+		output.FormatDuration(row.MedianExecution),
+		formatDeploymentPercentiles(row.MedianLeadTime, row.P90LeadTime),
+		formatDeploymentTime(row.LastDeployedAt),
+	}
+}
+
+var environmentHeader = []string{"Environment", "Deploys", "Per Day", "Failed (CFR)", "Approvals", "Approval Wait", "Run Time", "Lead Time", "Last Deploy"}
+
+func renderColoredDeployments(w io.Writer, report metrics.DeploymentReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🚀 Deployments")
+	fmt.Fprintln(w)
+
+	if len(report.Environments) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No deployments made by the selected workflows in the specified time range")
+		return
+	}
+
+	headerColors := make([]tablewriter.Colors, len(environmentHeader))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(environmentHeader)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+	)
+	for _, row := range report.Environments {
+		table.Append(environmentCells(row))
+	}
+	table.Render()
+
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🕒 Recent Deployments")
+
+	header := []string{"Environment", "Workflow", "Ref", "State", "Created", "Approval Wait", "Run Time", "Lead Time"}
+	recentColors := make([]tablewriter.Colors, len(header))
+	for i := range recentColors {
+		recentColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	recent := tablewriter.NewWriter(w)
+	recent.SetHeader(header)
+	recent.SetBorder(true)
+	recent.SetHeaderColor(recentColors...)
+	recent.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+	)
+	for _, d := range report.Deployments {
+		recent.Append([]string{
+			d.Environment,
+			d.Workflow,
+			d.Ref,
+			formatDeploymentState(d.State),
+			formatDeploymentTime(d.CreatedAt),
+			output.FormatDuration(d.ApprovalWait),
+			output.FormatDuration(d.Execution),
+			output.FormatDuration(d.LeadTime),
+		})
+	}
+	recent.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownDeployments(w io.Writer, report metrics.DeploymentReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Deployments")
+	fmt.Fprintln(w)
+
+	if len(report.Environments) == 0 {
+		fmt.Fprintln(w, "_No deployments made by the selected workflows in the specified time range._")
+		return
+	}
+
+	fmt.Fprintln(w, "| Environment | Deploys | Per Day | Failed (CFR) | Approvals | Approval Wait | Run Time | Lead Time | Last Deploy |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |")
+	for _, row := range report.Environments {
+		cells := environmentCells(row)
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(cells[0]), cells[1], cells[2], cells[3], cells[4], cells[5], cells[6], cells[7], cells[8])
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Recent Deployments")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Environment | Workflow | Ref | State | Created | Approval Wait | Run Time | Lead Time |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | ---: | ---: | ---: |")
+	for _, d := range report.Deployments {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(d.Environment),
+			escapeMarkdownCell(d.Workflow),
+			escapeMarkdownCell(d.Ref),
+			d.State,
+			formatDeploymentTime(d.CreatedAt),
+			output.FormatDuration(d.ApprovalWait),
+			output.FormatDuration(d.Execution),
+			output.FormatDuration(d.LeadTime),
+		)
+	}
+	fmt.Fprintln(w)
+}
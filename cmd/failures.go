@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/joblog"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/briandowns/spinner"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	flagFailureLogs     = "logs"
+	flagFailureLogLines = "log-lines"
+
+	failureHeadlineWidth = 100
+)
+
+func newFailuresCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failures <owner>/<repo>",
+		Short: "Cluster the errors behind failed jobs",
+		Long: heredoc.Doc(`
+			Collect the error messages of every failed job in the window and group identical errors,
+			reporting how often each occurred, in which jobs, and when it was first and last seen.
+
+			Messages come from the failure annotations of each job's check run. With --logs the tail
+			of the failing step's log is downloaded as well, which helps when the only annotation is
+			a generic exit code. Timestamps, paths, hashes and numbers are normalized away before
+			messages are compared.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			withLogs, err := cmd.Flags().GetBool(flagFailureLogs)
+			if err != nil {
+				return err
+			}
+			logLines, err := cmd.Flags().GetInt(flagFailureLogLines)
+			if err != nil {
+				return err
+			}
+			if logLines <= 0 {
+				return fmt.Errorf("--%s must be greater than 0", flagFailureLogLines)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			jobs := metrics.FailedJobs(collection.records, collection.from, collection.to, policy)
+			samples, err := collectFailureSamples(ctx, client, owner, repo, jobs, withLogs, logLines)
+			if err != nil {
+				return err
+			}
+			clusters := metrics.ClusterFailures(samples)
+			report := metrics.FailureReport{FailedJobs: len(jobs), Distinct: len(clusters), Clusters: clusters}
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Clusters) > top {
+				report.Clusters = report.Clusters[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownFailures(stdout, report, withLogs)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredFailures(os.Stdout, report, withLogs, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Int(flagTop, 20, "Show only the N largest error clusters (0 shows all; JSON always includes all)")
+	cmd.Flags().Bool(flagFailureLogs, false, "Also download job logs and cluster the tail of each failing step")
+	cmd.Flags().Int(flagFailureLogLines, 10, "Number of log lines kept from the end of the failing step")
+
+	return cmd
+}
+
+// collectFailureSamples fetches the failure annotations, and optionally the
+// failing step's log tail, of each failed job. Jobs whose details cannot be
+// fetched are logged and skipped.
+func collectFailureSamples(ctx context.Context, client *githubapi.Client, owner, repo string, jobs []metrics.FailedJob, withLogs bool, logLines int) ([]metrics.FailureSample, error) {
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []metrics.FailureSample
+	)
+
+	terminal := term.FromEnv()
+	var s *spinner.Spinner
+	if terminal.IsTerminalOutput() && len(jobs) > 0 {
+		s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Fetching errors for %d failed jobs...", len(jobs))
+		s.Start()
+	}
+
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, job := range jobs {
+		failed := job
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			sample := metrics.FailureSample{
+				Workflow: failed.Workflow,
+				Job:      failed.Job.Name,
+				Step:     failed.Step.Name,
+				RunID:    failed.Run.ID,
+				JobID:    failed.Job.ID,
+				At:       failed.At(),
+			}
+			var found []metrics.FailureSample
+
+			annotations, err := client.ListAnnotations(gctx, owner, repo, failed.Job.ID)
+			if err != nil {
+				slog.Warn("failed to fetch annotations", slog.String("job", failed.Job.Name), slog.Int64("run", failed.Run.ID), slog.String("error", err.Error()))
+			}
+			for _, annotation := range annotations {
+				if annotation.Level != "failure" {
+					continue
+				}
+				annotated := sample
+				annotated.Source = metrics.FailureSourceAnnotation
+				annotated.Message = annotationMessage(annotation)
+				found = append(found, annotated)
+			}
+
+			if withLogs {
+				data, err := client.GetJobLogs(gctx, owner, repo, failed.Job.ID)
+				if err != nil {
+					slog.Warn("failed to fetch job log", slog.String("job", failed.Job.Name), slog.Int64("run", failed.Run.ID), slog.String("error", err.Error()))
+				} else if excerpt := failingStepExcerpt(data, failed, logLines); excerpt != "" {
+					logged := sample
+					logged.Source = metrics.FailureSourceLog
+					logged.Message = excerpt
+					found = append(found, logged)
+				}
+			}
+
+			mu.Lock()
+			samples = append(samples, found...)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if s != nil {
+		s.Stop()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func annotationMessage(annotation githubapi.Annotation) string {
+	message := strings.TrimSpace(annotation.Message)
+	title := strings.TrimSpace(annotation.Title)
+	if title == "" || title == message {
+		return message
+	}
+	return title + ": " + message
+}
+
+// failingStepExcerpt returns the last lines the failing step logged, or the
+// end of the whole log when the job did not report a failing step.
+func failingStepExcerpt(data []byte, failed metrics.FailedJob, n int) string {
+	lines := joblog.Parse(data)
+	if failed.Step.Number != 0 {
+		if stepLines := joblog.StepLines(lines, failed.Job.Steps, failed.Step); len(stepLines) > 0 {
+			lines = stepLines
+		}
+	}
+
+	tail := joblog.ErrorTail(lines, n)
+	texts := make([]string, 0, len(tail))
+	for _, line := range tail {
+		texts = append(texts, joblog.Display(line.Text))
+	}
+	return strings.Join(texts, "\n")
+}
+
+// failureHeadline is the one-line summary of a cluster: the first line of an
+// annotation, or the last line of a log excerpt, where the error usually is.
+func failureHeadline(cluster metrics.FailureCluster) string {
+	lines := strings.Split(strings.TrimSpace(cluster.Message), "\n")
+	headline := lines[0]
+	if cluster.Source == metrics.FailureSourceLog {
+		headline = lines[len(lines)-1]
+	}
+	headline = strings.TrimSpace(headline)
+	if runes := []rune(headline); len(runes) > failureHeadlineWidth {
+		headline = string(runes[:failureHeadlineWidth-1]) + "…"
+	}
+	return headline
+}
+
+func formatFailureJobs(jobs []string) string {
+	if len(jobs) <= 1 {
+		return strings.Join(jobs, "")
+	}
+	return fmt.Sprintf("%s +%d more", jobs[0], len(jobs)-1)
+}
+
+func failuresOverview(report metrics.FailureReport) string {
+	return fmt.Sprintf("%d failed jobs · %d distinct errors", report.FailedJobs, report.Distinct)
+}
+
+func renderColoredFailures(w io.Writer, report metrics.FailureReport, withLogs, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🧯 Failure Triage")
+	fmt.Fprintln(w)
+
+	if report.FailedJobs == 0 {
+		successColor := color.New(color.FgGreen)
+		successColor.Fprintln(w, "✅ No failed jobs in the specified time range")
+		return
+	}
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprintln(w, failuresOverview(report))
+	fmt.Fprintln(w)
+
+	if len(report.Clusters) == 0 {
+		warningColor := color.New(color.FgYellow)
+		if withLogs {
+			warningColor.Fprintln(w, "⚠️  No failure annotations or failing step logs found")
+		} else {
+			warningColor.Fprintln(w, "⚠️  No failure annotations found; re-run with --logs to inspect job logs")
+		}
+		return
+	}
+
+	header := []string{"#", "Count", "Source", "Jobs", "First Seen", "Last Seen", "Error"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetAutoWrapText(false)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+	)
+
+	for i, cluster := range report.Clusters {
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			fmt.Sprintf("%d", cluster.Count),
+			cluster.Source,
+			escapeMarkdownCell(formatFailureJobs(cluster.Jobs)),
+			cluster.FirstSeen.Format("2006-01-02 15:04"),
+			cluster.LastSeen.Format("2006-01-02 15:04"),
+			failureHeadline(cluster),
+		})
+	}
+
+	table.Render()
+
+	excerptColor := color.New(color.FgHiBlack)
+	for i, cluster := range report.Clusters {
+		if cluster.Source != metrics.FailureSourceLog {
+			continue
+		}
+		fmt.Fprintln(w)
+		titleColor.Fprintf(w, "#%d log excerpt\n", i+1)
+		for _, line := range strings.Split(cluster.Message, "\n") {
+			excerptColor.Fprintf(w, "  │ %s\n", line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownFailures(w io.Writer, report metrics.FailureReport, withLogs bool) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Failure Triage")
+	fmt.Fprintln(w)
+
+	if report.FailedJobs == 0 {
+		fmt.Fprintln(w, "_No failed jobs in the specified time range._")
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n\n", failuresOverview(report))
+
+	if len(report.Clusters) == 0 {
+		if withLogs {
+			fmt.Fprintln(w, "_No failure annotations or failing step logs found._")
+		} else {
+			fmt.Fprintln(w, "_No failure annotations found; re-run with `--logs` to inspect job logs._")
+		}
+		return
+	}
+
+	fmt.Fprintln(w, "| # | Count | Source | Jobs | First Seen | Last Seen | Error |")
+	fmt.Fprintln(w, "| ---: | ---: | --- | --- | --- | --- | --- |")
+	for i, cluster := range report.Clusters {
+		fmt.Fprintf(w, "| %d | %d | %s | %s | %s | %s | `%s` |\n",
+			i+1,
+			cluster.Count,
+			cluster.Source,
+			escapeMarkdownCell(formatFailureJobs(cluster.Jobs)),
+			cluster.FirstSeen.Format("2006-01-02 15:04"),
+			cluster.LastSeen.Format("2006-01-02 15:04"),
+			escapeMarkdownCell(failureHeadline(cluster)),
+		)
+	}
+
+	for i, cluster := range report.Clusters {
+		if cluster.Source != metrics.FailureSourceLog {
+			continue
+		}
+		fmt.Fprintf(w, "\n### #%d log excerpt\n\n", i+1)
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w, cluster.Message)
+		fmt.Fprintln(w, "```")
+	}
+	fmt.Fprintln(w)
+}
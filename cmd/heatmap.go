@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const flagTimezone = "timezone"
+
+// heatmapPanel is one metric drawn as a heatmap.
+type heatmapPanel struct {
+	title  string
+	value  func(metrics.HeatmapCell) float64
+	format func(float64) string
+}
+
+var heatmapPanels = []heatmapPanel{
+	{
+		title:  "Runs",
+		value:  func(c metrics.HeatmapCell) float64 { return float64(c.Runs) },
+		format: func(v float64) string { return fmt.Sprintf("%.0f runs", v) },
+	},
+	{
+		title:  "Failure Rate",
+		value:  func(c metrics.HeatmapCell) float64 { return c.FailureRate },
+		format: output.FormatFailureRate,
+	},
+	{
+		title:  "Median Queue Time",
+		value:  func(c metrics.HeatmapCell) float64 { return float64(c.QueueP50) },
+		format: func(v float64) string { return output.FormatDuration(time.Duration(v)) },
+	},
+}
+
+func newHeatmapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "heatmap <owner>/<repo>",
+		Short: "Show run counts, failure rate and queue time by weekday and hour",
+		Long: heredoc.Doc(`
+			Bucket runs by the weekday and hour of day they started, in the time zone given by
+			--timezone, and draw heatmaps of run counts, failure rate and median job queue time.
+			Darker cells are closer to the busiest, flakiest or slowest hour of the week.
+
+			The JSON output and --csv include the numbers behind every cell.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			timezone, err := cmd.Flags().GetString(flagTimezone)
+			if err != nil {
+				return err
+			}
+			loc, err := time.LoadLocation(timezone)
+			if err != nil {
+				return fmt.Errorf("invalid --%s: %w", flagTimezone, err)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			heatmap := metrics.BuildHeatmap(collection.records, collection.from, collection.to, loc, policy)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(heatmap)
+			}
+
+			if csvPath := strings.TrimSpace(viper.GetString(flagCSV)); csvPath != "" {
+				return exportHeatmapCSV(heatmap, csvPath)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownHeatmap(stdout, heatmap)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredHeatmap(os.Stdout, heatmap, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().String(flagTimezone, "Local", "IANA time zone used to bucket runs, e.g. Europe/Berlin")
+
+	return cmd
+}
+
+func exportHeatmapCSV(heatmap metrics.Heatmap, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create csv file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"weekday", "hour", "runs", "failed", "failure_rate", "queue_p50_ms"}); err != nil {
+		return err
+	}
+	for _, cell := range heatmap.Cells {
+		record := []string{
+			cell.Weekday,
+			strconv.Itoa(cell.Hour),
+			strconv.Itoa(cell.Runs),
+			strconv.Itoa(cell.Failed),
+			fmt.Sprintf("%.4f", cell.FailureRate),
+			fmt.Sprintf("%d", cell.QueueP50.Milliseconds()),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// heatmapLines draws one panel and returns its lines and the largest value.
+func heatmapLines(heatmap metrics.Heatmap, panel heatmapPanel) ([]string, float64) {
+	labels := make([]string, 0, len(metrics.HeatmapWeekdays))
+	values := make([][]float64, 0, len(metrics.HeatmapWeekdays))
+	maxValue := 0.0
+	for _, weekday := range metrics.HeatmapWeekdays {
+		labels = append(labels, weekday.String()[:3])
+		row := make([]float64, 24)
+		for hour := range row {
+			row[hour] = panel.value(heatmap.Cell(weekday, hour))
+			if row[hour] > maxValue {
+				maxValue = row[hour]
+			}
+		}
+		values = append(values, row)
+	}
+	return output.RenderHeatmap(labels, values), maxValue
+}
+
+func heatmapLegend(panel heatmapPanel, maxValue float64) string {
+	return fmt.Sprintf("Scale: · none, %s → %s up to %s",
+		string(output.HeatmapShades[0]), string(output.HeatmapShades[len(output.HeatmapShades)-1]), panel.format(maxValue))
+}
+
+func heatmapHasRuns(heatmap metrics.Heatmap) bool {
+	for _, cell := range heatmap.Cells {
+		if cell.Runs > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func renderColoredHeatmap(w io.Writer, heatmap metrics.Heatmap, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintf(w, "🗓️  Weekly Heatmap (%s)\n", heatmap.Timezone)
+	fmt.Fprintln(w)
+
+	if !heatmapHasRuns(heatmap) {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs found in the specified time range")
+		return
+	}
+
+	panelTitle := color.New(color.FgHiWhite, color.Bold)
+	cellColor := color.New(color.FgYellow)
+	labelColor := color.New(color.FgHiBlack)
+	for _, panel := range heatmapPanels {
+		lines, maxValue := heatmapLines(heatmap, panel)
+		panelTitle.Fprintln(w, panel.title)
+		labelColor.Fprintln(w, lines[0])
+		for _, line := range lines[1:] {
+			label, cells, _ := strings.Cut(line, " ")
+			fmt.Fprintf(w, "%s ", label)
+			cellColor.Fprintln(w, cells)
+		}
+		labelColor.Fprintln(w, heatmapLegend(panel, maxValue))
+		fmt.Fprintln(w)
+	}
+}
+
+func renderMarkdownHeatmap(w io.Writer, heatmap metrics.Heatmap) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "# Weekly Heatmap (%s)\n", heatmap.Timezone)
+	fmt.Fprintln(w)
+
+	if !heatmapHasRuns(heatmap) {
+		fmt.Fprintln(w, "_No workflow runs found in the specified time range._")
+		return
+	}
+
+	for _, panel := range heatmapPanels {
+		lines, maxValue := heatmapLines(heatmap, panel)
+		fmt.Fprintf(w, "## %s\n\n", panel.title)
+		fmt.Fprintln(w, "```")
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s\n\n", heatmapLegend(panel, maxValue))
+	}
+}
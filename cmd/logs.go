@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/joblog"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/briandowns/spinner"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	flagLogsIgnoreCase = "ignore-case"
+	flagLogsContext    = "context"
+)
+
+// logJob is a completed job whose log is searched.
+type logJob struct {
+	workflow string
+	run      githubapi.WorkflowRun
+	job      githubapi.WorkflowJob
+}
+
+type logMatch struct {
+	Workflow   string `json:"workflow"`
+	RunID      int64  `json:"run_id"`
+	RunNumber  int    `json:"run_number"`
+	RunAttempt int    `json:"run_attempt"`
+	Branch     string `json:"branch"`
+	Job        string `json:"job"`
+	JobID      int64  `json:"job_id"`
+	Step       string `json:"step,omitempty"`
+	joblog.Match
+}
+
+type logGrepReport struct {
+	Pattern      string     `json:"pattern"`
+	JobsSearched int        `json:"jobs_searched"`
+	JobsMatched  int        `json:"jobs_matched"`
+	RunsMatched  int        `json:"runs_matched"`
+	FirstSeen    *logMatch  `json:"first_seen,omitempty"`
+	LastSeen     *logMatch  `json:"last_seen,omitempty"`
+	Matches      []logMatch `json:"matches"`
+}
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Download and search job logs",
+	}
+	cmd.AddCommand(newLogsGrepCmd())
+	return cmd
+}
+
+func newLogsGrepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grep <owner>/<repo> <pattern>",
+		Short: "Search the logs of jobs in the window with a regular expression",
+		Long: heredoc.Doc(`
+			Download the log of every completed job in the window and print the lines matching a
+			regular expression (RE2 syntax), with the workflow run, job and step that wrote them.
+			Matches are listed oldest first, and the summary shows when the pattern was first and
+			last seen, which answers questions like "when did this warning start appearing".
+
+			Logs are cached on disk for --log-cache-ttl, so repeated searches over the same window
+			only download new jobs. Timestamps and color codes are stripped before matching.
+		`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			ignoreCase, err := cmd.Flags().GetBool(flagLogsIgnoreCase)
+			if err != nil {
+				return err
+			}
+			expr := args[1]
+			if ignoreCase {
+				expr = "(?i)" + expr
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", args[1], err)
+			}
+
+			contextLines, err := cmd.Flags().GetInt(flagLogsContext)
+			if err != nil {
+				return err
+			}
+			if contextLines < 0 {
+				return fmt.Errorf("--%s must be greater than or equal to 0", flagLogsContext)
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			jobs := completedLogJobs(collection)
+			matches, err := grepJobLogs(ctx, client, owner, repo, jobs, re, contextLines)
+			if err != nil {
+				return err
+			}
+			report := buildLogGrepReport(args[1], len(jobs), matches)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownLogGrep(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredLogGrep(os.Stdout, report, re, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Bool(flagLogsIgnoreCase, false, "Match the pattern case-insensitively")
+	cmd.Flags().Int(flagLogsContext, 0, "Number of lines to show before and after each match")
+
+	return cmd
+}
+
+// completedLogJobs returns the completed jobs of runs within the window,
+// oldest first. Logs of jobs still running are not available yet.
+func completedLogJobs(collection *runCollection) []logJob {
+	var jobs []logJob
+	for _, rec := range collection.records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(collection.from) || runTime.After(collection.to) {
+			continue
+		}
+		for _, job := range rec.Jobs {
+			if !strings.EqualFold(job.Status, "completed") || job.StartedAt.IsZero() {
+				continue
+			}
+			jobs = append(jobs, logJob{workflow: rec.Workflow.Name, run: rec.Run, job: job})
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if !jobs[i].job.StartedAt.Equal(jobs[j].job.StartedAt) {
+			return jobs[i].job.StartedAt.Before(jobs[j].job.StartedAt)
+		}
+		return jobs[i].job.ID < jobs[j].job.ID
+	})
+	return jobs
+}
+
+// grepJobLogs downloads the log of each job and returns the matching lines in
+// job order. Logs that cannot be downloaded are logged and skipped.
+func grepJobLogs(ctx context.Context, client *githubapi.Client, owner, repo string, jobs []logJob, re *regexp.Regexp, contextLines int) ([]logMatch, error) {
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	// Each goroutine fills its own slot, which keeps matches in job order.
+	perJob := make([][]logMatch, len(jobs))
+
+	terminal := term.FromEnv()
+	var s *spinner.Spinner
+	if terminal.IsTerminalOutput() && len(jobs) > 0 {
+		s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Searching logs of %d jobs...", len(jobs))
+		s.Start()
+	}
+
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for i, job := range jobs {
+		idx, target := i, job
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			data, err := client.GetJobLogs(gctx, owner, repo, target.job.ID)
+			if err != nil {
+				slog.Warn("failed to fetch job log", slog.String("job", target.job.Name), slog.Int64("run", target.run.ID), slog.String("error", err.Error()))
+				return nil
+			}
+
+			var found []logMatch
+			for _, match := range joblog.Grep(joblog.Parse(data), re, contextLines) {
+				found = append(found, newLogMatch(target, match))
+			}
+
+			perJob[idx] = found
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if s != nil {
+		s.Stop()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []logMatch
+	for _, found := range perJob {
+		matches = append(matches, found...)
+	}
+	return matches, nil
+}
+
+func newLogMatch(target logJob, match joblog.Match) logMatch {
+	m := logMatch{
+		Workflow:   target.workflow,
+		RunID:      target.run.ID,
+		RunNumber:  target.run.RunNumber,
+		RunAttempt: target.run.RunAttempt,
+		Branch:     target.run.HeadBranch,
+		Job:        target.job.Name,
+		JobID:      target.job.ID,
+		Match:      match,
+	}
+	if step, ok := joblog.StepAt(target.job.Steps, match.Line.Time); ok {
+		m.Step = step.Name
+	}
+	return m
+}
+
+func buildLogGrepReport(pattern string, searched int, matches []logMatch) logGrepReport {
+	report := logGrepReport{Pattern: pattern, JobsSearched: searched, Matches: matches}
+	jobs := make(map[int64]struct{})
+	runs := make(map[int64]struct{})
+	for i := range matches {
+		m := &matches[i]
+		jobs[m.JobID] = struct{}{}
+		runs[m.RunID] = struct{}{}
+		if report.FirstSeen == nil || m.Line.Time.Before(report.FirstSeen.Line.Time) {
+			report.FirstSeen = m
+		}
+		if report.LastSeen == nil || !m.Line.Time.Before(report.LastSeen.Line.Time) {
+			report.LastSeen = m
+		}
+	}
+	report.JobsMatched = len(jobs)
+	report.RunsMatched = len(runs)
+	return report
+}
+
+func formatLogMatchRun(m logMatch) string {
+	if m.RunAttempt > 1 {
+		return fmt.Sprintf("%s #%d (attempt %d)", m.Workflow, m.RunNumber, m.RunAttempt)
+	}
+	return fmt.Sprintf("%s #%d", m.Workflow, m.RunNumber)
+}
+
+func logGrepOverview(report logGrepReport) string {
+	overview := fmt.Sprintf("%d matches in %d of %d jobs across %d runs",
+		len(report.Matches), report.JobsMatched, report.JobsSearched, report.RunsMatched)
+	if report.FirstSeen == nil {
+		return overview
+	}
+	return fmt.Sprintf("%s · first seen %s (%s) · last seen %s (%s)", overview,
+		report.FirstSeen.Line.Time.Format("2006-01-02 15:04"), formatLogMatchRun(*report.FirstSeen),
+		report.LastSeen.Line.Time.Format("2006-01-02 15:04"), formatLogMatchRun(*report.LastSeen))
+}
+
+// highlightMatches colors every part of text matched by re.
+func highlightMatches(text string, re *regexp.Regexp, c *color.Color) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(c.Sprint(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func renderColoredLogGrep(w io.Writer, report logGrepReport, re *regexp.Regexp, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🔎 Log Search")
+	fmt.Fprintln(w)
+
+	labelColor := color.New(color.FgHiBlack)
+	if len(report.Matches) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintf(w, "⚠️  No lines matching %q in the logs of %d jobs\n", report.Pattern, report.JobsSearched)
+		return
+	}
+	labelColor.Fprintln(w, logGrepOverview(report))
+
+	headerColor := color.New(color.FgHiWhite, color.Bold)
+	numberColor := color.New(color.FgGreen)
+	matchColor := color.New(color.FgRed, color.Bold)
+
+	var currentJob int64
+	lastPrinted := 0
+	printLine := func(line joblog.Line, matched bool) {
+		if line.Number <= lastPrinted {
+			return
+		}
+		if lastPrinted > 0 && line.Number > lastPrinted+1 {
+			labelColor.Fprintln(w, "      ┈")
+		}
+		lastPrinted = line.Number
+		if matched {
+			fmt.Fprintf(w, "%s │ %s\n", numberColor.Sprintf("%6d", line.Number), highlightMatches(line.Text, re, matchColor))
+			return
+		}
+		labelColor.Fprintf(w, "%6d │ %s\n", line.Number, line.Text)
+	}
+
+	for _, m := range report.Matches {
+		if m.JobID != currentJob {
+			currentJob = m.JobID
+			lastPrinted = 0
+			fmt.Fprintln(w)
+			header := fmt.Sprintf("%s › %s", formatLogMatchRun(m), m.Job)
+			if m.Step != "" {
+				header += " › " + m.Step
+			}
+			headerColor.Fprint(w, header)
+			labelColor.Fprintf(w, "  %s\n", m.Line.Time.Format("2006-01-02 15:04"))
+		}
+		for _, line := range m.Before {
+			printLine(line, false)
+		}
+		printLine(m.Line, true)
+		for _, line := range m.After {
+			printLine(line, false)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownLogGrep(w io.Writer, report logGrepReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Log Search")
+	fmt.Fprintln(w)
+
+	if len(report.Matches) == 0 {
+		fmt.Fprintf(w, "_No lines matching `%s` in the logs of %d jobs._\n", report.Pattern, report.JobsSearched)
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n\n", logGrepOverview(report))
+	fmt.Fprintln(w, "| Time | Run | Branch | Job | Step | Line | Text |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | ---: | --- |")
+	for _, m := range report.Matches {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %d | `%s` |\n",
+			m.Line.Time.Format("2006-01-02 15:04"),
+			formatLogMatchRun(m),
+			escapeMarkdownCell(m.Branch),
+			escapeMarkdownCell(m.Job),
+			escapeMarkdownCell(m.Step),
+			m.Line.Number,
+			escapeMarkdownCell(m.Line.Text),
+		)
+	}
+	fmt.Fprintln(w)
+}
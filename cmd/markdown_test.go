@@ -6,8 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/JohnTitor/gh-actrics/internal/check"
 	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/joblog"
 	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
 )
 
 func TestRenderMarkdownSummary(t *testing.T) {
@@ -104,3 +107,889 @@ func TestRenderMarkdownWorkflows(t *testing.T) {
 		t.Fatalf("markdown workflows mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
 	}
 }
+
+func TestRenderStepSummary(t *testing.T) {
+	rows := []metrics.SummaryRow{{
+		Workflow:            "build <linux>",
+		WorkflowID:          1,
+		Runs:                4,
+		Failed:              1,
+		FailureRate:         0.25,
+		AvgDuration:         2 * time.Minute,
+		TotalDuration:       8 * time.Minute,
+		DurationPercentiles: metrics.Percentiles{P95: 3 * time.Minute},
+		Trend:               metrics.Trend{DurationChange: 0.5},
+		Jobs: []metrics.JobSummaryRow{{
+			Job:                 "test | unit",
+			Runs:                4,
+			AvgDuration:         time.Minute,
+			TotalDuration:       4 * time.Minute,
+			DurationPercentiles: metrics.Percentiles{P95: 90 * time.Second},
+			Trend:               metrics.Trend{DurationChange: -0.01},
+		}},
+	}}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	renderStepSummary(&buf, rows, "owner/repo", from, to)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `## 📊 Workflow Execution Summary for owner/repo
+
+_2025-01-01T00:00:00Z → 2025-01-08T00:00:00Z_
+
+| Workflow | Runs | Failed | Failure Rate | Avg Duration | P95 Duration | Trend |
+| --- | ---: | ---: | ---: | ---: | ---: | :---: |
+| build <linux> | 4 | 1 | 25.0% | 2m0s | 3m0s | ↑ +50.0% |
+
+<details>
+<summary>Jobs for build &lt;linux&gt; (1)</summary>
+
+| Job | Runs | Failed | Failure Rate | Avg Duration | P95 Duration | Trend |
+| --- | ---: | ---: | ---: | ---: | ---: | :---: |
+| test \| unit | 4 | 0 | 0% | 1m0s | 1m30s | → -1.0% |
+
+</details>`
+
+	if got != want {
+		t.Fatalf("step summary mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderStepOutputs(t *testing.T) {
+	var buf bytes.Buffer
+	renderStepOutputs(&buf, metrics.SummaryRow{
+		FailureRate:         0.125,
+		DurationPercentiles: metrics.Percentiles{P95: 15*time.Minute + 500*time.Millisecond},
+	})
+
+	const want = "failure_rate=0.1250\np95_duration=900\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("step outputs mismatch: want %q, got %q", want, got)
+	}
+}
+
+func TestRenderMarkdownCheck(t *testing.T) {
+	rule, err := check.Parse("job: p95(duration) < 15m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report := checkReport{
+		Rules:     []check.Rule{rule},
+		Workflows: 1,
+		Violations: []check.Violation{{
+			Rule:     rule,
+			Workflow: "build",
+			Job:      "test",
+			Value:    float64(18 * time.Minute),
+		}},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownCheck(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Threshold Check\n\n" +
+		"❌ 1 violation(s) of 1 rules across 1 workflows.\n\n" +
+		"| Target | Rule | Actual |\n" +
+		"| --- | --- | ---: |\n" +
+		"| build › test | `job: p95(duration) < 15m` | 18m0s |"
+
+	if got != want {
+		t.Fatalf("markdown check mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSummaryAlerts(t *testing.T) {
+	since := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rows := []metrics.SummaryRow{{
+		Workflow:    "build",
+		WorkflowID:  1,
+		Runs:        17,
+		AvgDuration: 10 * time.Minute,
+		Alerts: []metrics.Alert{
+			{Workflow: "build", WorkflowID: 1, Metric: metrics.AlertDuration, Baseline: float64(10 * time.Minute), Recent: float64(20 * time.Minute), Since: since},
+			{Workflow: "build", WorkflowID: 1, Job: "test", Metric: metrics.AlertFailureRate, Baseline: 0.1, Recent: 0.6, Since: since},
+		},
+	}}
+
+	var buf bytes.Buffer
+	renderMarkdownSummary(&buf, rows)
+	got := buf.String()
+
+	const want = `## Alerts
+
+| Target | Change | Since |
+| --- | --- | --- |
+| build | median 10m0s → 20m0s (+100%) | 2025-06-01T12:00:00Z |
+| build › test | failure rate 10.0% → 60.0% | 2025-06-01T12:00:00Z |
+`
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected alerts section:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSummaryConclusions(t *testing.T) {
+	rows := []metrics.SummaryRow{{
+		Workflow:   "build",
+		WorkflowID: 1,
+		Runs:       10,
+		Failed:     2,
+		Conclusions: map[string]int{
+			"success":         5,
+			"failure":         1,
+			"cancelled":       3,
+			"action_required": 1,
+		},
+		Superseded: 2,
+	}}
+
+	var buf bytes.Buffer
+	renderMarkdownSummary(&buf, rows)
+	got := buf.String()
+
+	const want = `## Conclusions
+
+| Workflow | Success | Failure | Cancelled | Superseded | Skipped | Timed Out | Other |
+| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |
+| build | 5 | 1 | 1 | 2 | 0 | 0 | 1 |
+`
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected conclusions section:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSummaryWorkflowChanges(t *testing.T) {
+	rows := []metrics.SummaryRow{{
+		Workflow:   "ci",
+		WorkflowID: 1,
+		Runs:       4,
+		Changes: []metrics.WorkflowChange{{
+			SHA:       "0123456789abcdef",
+			Message:   "Run tests | lint in parallel",
+			Author:    "octocat",
+			ChangedAt: time.Date(2025, 7, 2, 9, 30, 0, 0, time.UTC),
+			Before:    metrics.ChangeStats{Runs: 2, AvgDuration: 10 * time.Minute},
+			After:     metrics.ChangeStats{Runs: 2, Failed: 1, FailureRate: 0.5, AvgDuration: 20 * time.Minute},
+			Trend:     metrics.Trend{DurationChange: 1, FailureRateChange: 0.5},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	renderMarkdownSummary(&buf, rows)
+	got := buf.String()
+
+	const want = `## Workflow File Changes
+
+| Workflow | Commit | Changed | Author | Message | Runs | Avg Duration | Trend | Failure Rate |
+| --- | --- | --- | --- | --- | ---: | ---: | ---: | ---: |
+| ci | 0123456 | 2025-07-02 09:30 | octocat | Run tests \| lint in parallel | 2 → 2 | 10m0s → 20m0s | ↑ +100.0% | 0% → 50.0% |
+`
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected workflow changes section:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownRecovery(t *testing.T) {
+	start := time.Date(2025, 7, 1, 1, 0, 0, 0, time.UTC)
+	report := recoveryReport{
+		Workflows: []metrics.RecoveryRow{{
+			Workflow:          "ci",
+			Branch:            "main",
+			Runs:              10,
+			Outages:           1,
+			Recovered:         1,
+			MeanTimeToRecover: 3 * time.Hour,
+			MTTRPercentiles:   metrics.Percentiles{P50: 3 * time.Hour, P90: 3 * time.Hour},
+			LongestOutage:     3 * time.Hour,
+			LongestStreak:     2,
+		}},
+		Outages: []metrics.Outage{{
+			Workflow:      "ci",
+			Branch:        "main",
+			Start:         start,
+			End:           start.Add(3 * time.Hour),
+			Duration:      3 * time.Hour,
+			FailedRuns:    2,
+			FirstRunID:    2,
+			RecoveryRunID: 5,
+			Recovered:     true,
+		}},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownRecovery(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Failure Recovery
+
+| Workflow | Branch | Runs | Outages | MTTR p50 | MTTR p90 | Mean MTTR | Longest Outage | Longest Streak | Status |
+| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |
+| ci | main | 10 | 1 | 3h0m0s | 3h0m0s | 3h0m0s | 3h0m0s | 2 | green |
+
+## Longest Outages
+
+| Workflow | Branch | Went Red | Recovered | Failed Runs | Duration | First Failing Run |
+| --- | --- | --- | --- | ---: | ---: | ---: |
+| ci | main | 2025-07-01T01:00:00Z | 2025-07-01T04:00:00Z | 2 | 3h0m0s | 2 |`
+
+	if got != want {
+		t.Fatalf("markdown recovery mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownRun(t *testing.T) {
+	base := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	run := githubapi.WorkflowRun{
+		ID:         7,
+		Name:       "ci",
+		RunNumber:  12,
+		RunAttempt: 1,
+		HeadBranch: "main",
+		Event:      "push",
+		Conclusion: "success",
+		CreatedAt:  base,
+		UpdatedAt:  base.Add(100 * time.Second),
+		Duration:   100 * time.Second,
+	}
+	jobs := []githubapi.WorkflowJob{
+		{Name: "test", RunnerName: "r2", Conclusion: "success", CreatedAt: base.Add(40 * time.Second), StartedAt: base.Add(50 * time.Second), CompletedAt: base.Add(100 * time.Second)},
+		{Name: "build", RunnerName: "r1", Conclusion: "success", CreatedAt: base, StartedAt: base.Add(10 * time.Second), CompletedAt: base.Add(40 * time.Second)},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownRun(&buf, newRunDetail(run, jobs), false, 57)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Run 7 · ci #12 (attempt 1)\n" +
+		"\n" +
+		"- **Branch:** main\n" +
+		"- **Event:** push\n" +
+		"- **Conclusion:** success\n" +
+		"- **Duration:** 1m40s\n" +
+		"- **Critical path:** build → test (1m40s)\n" +
+		"\n" +
+		"| Job | Runner | Conclusion | Start | Queued | Duration | Critical |\n" +
+		"| --- | --- | --- | ---: | ---: | ---: | :---: |\n" +
+		"| build | r1 | success | +10s | 10s | 30s | ✓ |\n" +
+		"| test | r2 | success | +50s | 10s | 50s | ✓ |\n" +
+		"\n" +
+		"```text\n" +
+		"* build (r1)                         ░░██████\n" +
+		"* test (r2)                                  ░░██████████\n" +
+		"                                     0s───────50s───1m40s\n" +
+		"```"
+
+	if got != want {
+		t.Fatalf("markdown run mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownRunAttempt(t *testing.T) {
+	base := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	run := githubapi.WorkflowRun{
+		ID:           7,
+		Name:         "ci",
+		RunNumber:    12,
+		RunAttempt:   2,
+		CreatedAt:    base.Add(-24 * time.Hour),
+		RunStartedAt: base,
+		UpdatedAt:    base.Add(time.Minute),
+	}
+	jobs := []githubapi.WorkflowJob{
+		{Name: "build", CreatedAt: base, StartedAt: base.Add(20 * time.Second), CompletedAt: base.Add(time.Minute)},
+	}
+
+	detail := newRunDetail(run, jobs)
+	if got := runQueueTime(detail); got != 20*time.Second {
+		t.Fatalf("expected the queue time of the current attempt, got %s", got)
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownRun(&buf, detail, false, 57)
+	if got := buf.String(); !strings.Contains(got, "| build |  |  | +20s | 20s | 40s | ✓ |") {
+		t.Fatalf("expected job start relative to the attempt, got:\n%s", got)
+	}
+
+	buf.Reset()
+	renderMarkdownRun(&buf, newRunDetail(run, nil), false, 57)
+	if got := buf.String(); strings.Contains(got, "Critical path") {
+		t.Fatalf("expected no critical path for a run without jobs, got:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownCriticalPaths(t *testing.T) {
+	rows := []metrics.CriticalPathRow{{
+		Workflow:        "ci",
+		Runs:            3,
+		AvgPathDuration: 4*time.Minute + 30*time.Second,
+		CommonPath:      []string{"build", "deploy"},
+		CommonPathRuns:  2,
+		Jobs: []metrics.CriticalPathJob{
+			{Job: "deploy", Runs: 3, OnPath: 3, OnPathRate: 1, AvgDuration: time.Minute},
+			{Job: "build", Runs: 3, OnPath: 2, OnPathRate: 2.0 / 3, AvgDuration: 3 * time.Minute, MedianSlack: 105 * time.Second},
+		},
+	}}
+
+	var buf bytes.Buffer
+	renderMarkdownCriticalPaths(&buf, rows)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Critical Path
+
+## ci
+
+3 runs, average path 4m30s. Most common path (2/3 runs): build → deploy
+
+| Job | Runs | On Path | On Path % | Avg Duration | Median Slack |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| deploy | 3 | 3 | 100.0% | 1m0s | - |
+| build | 3 | 2 | 66.7% | 3m0s | 1m45s |`
+
+	if got != want {
+		t.Fatalf("markdown critical path mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSummaryJobConfig(t *testing.T) {
+	rows := []metrics.SummaryRow{{
+		Workflow:   "build",
+		WorkflowID: 1,
+		Runs:       2,
+		Jobs: []metrics.JobSummaryRow{{
+			Job:                 "test (ubuntu-latest)",
+			Runs:                2,
+			AvgDuration:         10 * time.Minute,
+			TotalDuration:       20 * time.Minute,
+			DurationPercentiles: metrics.Percentiles{P99: 12 * time.Minute},
+		}, {
+			Job:           "lint",
+			Runs:          2,
+			AvgDuration:   time.Minute,
+			TotalDuration: 2 * time.Minute,
+		}},
+	}}
+	files := map[int64]*workflowfile.Workflow{
+		1: {Jobs: []workflowfile.Job{{ID: "test", RunsOn: []string{"${{ matrix.os }}"}, TimeoutMinutes: 15}}},
+	}
+	metrics.AttachJobConfig(rows, files)
+
+	var buf bytes.Buffer
+	renderMarkdownSummary(&buf, rows)
+	got := strings.TrimSpace(buf.String())
+	got = got[strings.Index(got, "## Jobs for build"):]
+
+	const want = `## Jobs for build
+
+| Job | Runs | Failed | Failure Rate | Avg Duration | Total Duration | Top Runners | p99 / Timeout | Declared Runs-On |
+| --- | ---: | ---: | ---: | ---: | ---: | --- | ---: | --- |
+| test (ubuntu-latest) | 2 | 0 | 0% | 10m0s | 20m0s | - | 12m0s / 15m0s | ${{ matrix.os }} |
+| lint | 2 | 0 | 0% | 1m0s | 2m0s | - | - | - |`
+
+	if got != want {
+		t.Fatalf("markdown job config mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSummaryMatrix(t *testing.T) {
+	rows := []metrics.SummaryRow{{
+		Workflow:   "ci",
+		WorkflowID: 1,
+		Runs:       2,
+		Jobs: []metrics.JobSummaryRow{{
+			Job:           "test",
+			Runs:          4,
+			Failed:        1,
+			FailureRate:   0.25,
+			AvgDuration:   8 * time.Minute,
+			TotalDuration: 32 * time.Minute,
+			Matrix: []metrics.JobSummaryRow{
+				{Job: "test (windows-latest)", MatrixValues: []string{"windows-latest"}, Runs: 2, Failed: 1, FailureRate: 0.5, AvgDuration: 11 * time.Minute, TotalDuration: 22 * time.Minute},
+				{Job: "test (ubuntu-latest)", MatrixValues: []string{"ubuntu-latest"}, Runs: 2, AvgDuration: 5 * time.Minute, TotalDuration: 10 * time.Minute},
+			},
+		}},
+		MatrixAxis: []metrics.MatrixAxisRow{
+			{Workflow: "ci", Job: "test", Axis: "os", Value: "windows-latest", Cells: 1, Runs: 2, Failed: 1, FailureRate: 0.5, AvgDuration: 11 * time.Minute, DurationPercentiles: metrics.Percentiles{P95: 12 * time.Minute}},
+			{Workflow: "ci", Job: "test", Axis: "os", Value: "ubuntu-latest", Cells: 1, Runs: 2, AvgDuration: 5 * time.Minute, DurationPercentiles: metrics.Percentiles{P95: 6 * time.Minute}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	renderMarkdownSummary(&buf, rows)
+	got := strings.TrimSpace(buf.String())
+	got = got[strings.Index(got, "## Jobs for ci"):]
+
+	const want = `## Jobs for ci
+
+| Job | Runs | Failed | Failure Rate | Avg Duration | Total Duration | Top Runners |
+| --- | ---: | ---: | ---: | ---: | ---: | --- |
+| test | 4 | 1 | 25.0% | 8m0s | 32m0s | - |
+| ↳ windows-latest | 2 | 1 | 50.0% | 11m0s | 22m0s | - |
+| ↳ ubuntu-latest | 2 | 0 | 0% | 5m0s | 10m0s | - |
+
+## Matrix by os
+
+| Workflow | Job | os | Cells | Runs | Failure Rate | Avg Duration | P95 Duration | P90 Queue |
+| --- | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |
+| ci | test | windows-latest | 1 | 2 | 50.0% | 11m0s | 12m0s | - |
+| ci | test | ubuntu-latest | 1 | 2 | 0% | 5m0s | 6m0s | - |`
+
+	if got != want {
+		t.Fatalf("markdown matrix mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownTimeouts(t *testing.T) {
+	report := timeoutsReport{
+		Wasted: 88 * time.Minute,
+		Jobs: []metrics.TimeoutRow{
+			{Workflow: "ci", Job: "lint | vet", Runs: 7, HungCancelled: 1, Wasted: 58 * time.Minute, Timeout: 6 * time.Hour, SuccessP99: time.Minute, Recommended: 2 * time.Minute},
+			{Workflow: "ci", Job: "test", Runs: 8, TimedOut: 1, Wasted: 30 * time.Minute, Timeout: 30 * time.Minute, TimeoutDeclared: true, SuccessP99: 13 * time.Minute, Recommended: 20 * time.Minute},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownTimeouts(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Timeouts and Hung Jobs
+
+Runner time wasted by timed-out and hung jobs: **1h28m0s**
+
+| Workflow | Job | Runs | Timed Out | Hung | Wasted | Timeout | Success p99 | Suggested timeout-minutes |
+| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |
+| ci | lint \| vet | 7 | 0 | 1 | 58m0s | 6h0m0s (default) | 1m0s | 2 |
+| ci | test | 8 | 1 | 0 | 30m0s | 30m0s | 13m0s | 20 |`
+
+	if got != want {
+		t.Fatalf("markdown timeouts mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownRunners(t *testing.T) {
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	report := metrics.RunnerReport{
+		From: from,
+		To:   from.Add(100 * time.Minute),
+		Runners: []metrics.RunnerRow{
+			{Runner: "linux-1", RunnerID: 1, Status: "online", Registered: true, Labels: []string{"linux", "self-hosted"}, Jobs: 2, Busy: time.Hour, Idle: 40 * time.Minute, Utilization: 0.6, PeakConcurrency: 1},
+			{Runner: "ephemeral-abc", Labels: []string{"linux", "self-hosted"}, Jobs: 1, Busy: 20 * time.Minute, Idle: 80 * time.Minute, Utilization: 0.2, PeakConcurrency: 1},
+		},
+		Labels: []metrics.RunnerLabelRow{
+			{Label: "linux", Runners: 2, Jobs: 3, Busy: 80 * time.Minute, Idle: 120 * time.Minute, Utilization: 0.4, PeakConcurrency: 2},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownRunners(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Self-Hosted Runner Utilization
+
+Window: 2025-07-01 00:00 → 2025-07-01 01:40 (1h40m0s)
+
+| Runner | Status | Labels | Jobs | Busy | Idle | Utilization | Peak |
+| --- | --- | --- | ---: | ---: | ---: | ---: | ---: |
+| linux-1 | online | linux, self-hosted | 2 | 1h0m0s | 40m0s | 60.0% | 1 |
+| ephemeral-abc | unregistered | linux, self-hosted | 1 | 20m0s | 1h20m0s | 20.0% | 1 |
+
+## By Label
+
+| Label | Runners | Jobs | Busy | Idle | Utilization | Peak Concurrency |
+| --- | ---: | ---: | ---: | ---: | ---: | ---: |
+| linux | 2 | 3 | 1h20m0s | 2h0m0s | 40.0% | 2 |`
+
+	if got != want {
+		t.Fatalf("markdown runners mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownConcurrency(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	series := []metrics.ConcurrencySeries{
+		{
+			Label: metrics.AllLabels, Jobs: 3, Max: 2, P95: 2, Average: 1.5, PeakAt: base.Add(30 * time.Minute), AtLimit: time.Hour,
+			Points: []metrics.ConcurrencyPoint{
+				{Start: base, Max: 2, Average: 1.5},
+				{Start: base.Add(time.Hour), Max: 2, Average: 1.25},
+				{Start: base.Add(2 * time.Hour), Max: 0, Average: 0},
+			},
+		},
+		{Label: "ubuntu-latest", Jobs: 2, Max: 2, P95: 2, Average: 1, PeakAt: base.Add(30 * time.Minute)},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownConcurrency(&buf, series, 2)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Job Concurrency
+
+| Label | Jobs | Max | P95 | Average | Peak At | At Limit (2) |
+| --- | ---: | ---: | ---: | ---: | --- | ---: |
+| (all) | 3 | 2 | 2 | 1.50 | 2025-07-01 00:30 | 1h0m0s |
+| ubuntu-latest | 2 | 2 | 2 | 1.00 | 2025-07-01 00:30 | - |
+
+## Busiest Periods
+
+| Start | Average | Max |
+| --- | ---: | ---: |
+| 2025-07-01 00:00 | 1.50 | 2 |
+| 2025-07-01 01:00 | 1.25 | 2 |`
+
+	if got != want {
+		t.Fatalf("markdown concurrency mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownHeatmap(t *testing.T) {
+	records := []metrics.RunRecord{{
+		Workflow: githubapi.Workflow{ID: 1, Name: "nightly"},
+		Run:      githubapi.WorkflowRun{ID: 1, Status: "completed", Conclusion: "failure", RunStartedAt: time.Date(2025, 6, 30, 2, 15, 0, 0, time.UTC)},
+	}}
+	heatmap := metrics.BuildHeatmap(records, time.Time{}, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.UTC, metrics.FailurePolicy{})
+
+	var buf bytes.Buffer
+	renderMarkdownHeatmap(&buf, heatmap)
+	got := buf.String()
+
+	empty := strings.Repeat("·", 48)
+	want := "## Runs\n\n```\n    00    03    06    09    12    15    18    21\nMon ····██" + strings.Repeat("·", 42) + "\nTue " + empty + "\n"
+	if !strings.HasPrefix(got, "\n# Weekly Heatmap (UTC)\n\n") || !strings.Contains(got, want) {
+		t.Fatalf("unexpected heatmap markdown:\n%s", got)
+	}
+	if !strings.Contains(got, "Scale: · none, ░ → █ up to 1 runs") || !strings.Contains(got, "## Failure Rate") {
+		t.Fatalf("missing legend or failure rate panel:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownPRs(t *testing.T) {
+	report := metrics.PullRequestReport{
+		PullRequests:      2,
+		CITime:            57 * time.Minute,
+		MedianCITime:      27 * time.Minute,
+		Green:             1,
+		MedianTimeToGreen: 70 * time.Minute,
+		P90TimeToGreen:    70 * time.Minute,
+		Rows: []metrics.PullRequestRow{
+			{Number: 8, Branch: "flaky", Runs: 1, Pushes: 1, Failed: 1, CITime: 30 * time.Minute},
+			{Number: 7, Branch: "feature", Runs: 4, Pushes: 2, Reruns: 1, Failed: 1, CITime: 27 * time.Minute, TimeToGreen: 70 * time.Minute},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownPRs(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = `# Pull Request CI Impact
+
+2 pull requests · 57m0s CI time (median 27m0s per PR) · 1 went green, median time to green 1h10m0s (p90 1h10m0s)
+
+| PR | Branch | Runs | Pushes | Re-runs | Failed | CI Time | Time to Green |
+| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |
+| #8 | flaky | 1 | 1 | 0 | 1 | 30m0s | - |
+| #7 | feature | 4 | 2 | 1 | 1 | 27m0s | 1h10m0s |`
+
+	if got != want {
+		t.Fatalf("markdown prs mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownCommits(t *testing.T) {
+	report := metrics.CommitReport{
+		Commits:         2,
+		MedianWallClock: 25 * time.Minute,
+		P90WallClock:    50 * time.Minute,
+		Slowest: []metrics.SlowestWorkflowCount{
+			{Workflow: "ci", Commits: 1},
+			{Workflow: "docs", Commits: 1},
+		},
+		Rows: []metrics.CommitRow{
+			{SHA: "b1c2d3e4f5", Branch: "main", Workflows: 2, Runs: 2, Conclusion: "cancelled", WallClock: 25 * time.Minute, SlowestWorkflow: "docs", SlowestDuration: 25 * time.Minute},
+			{SHA: "a1", Branch: "main", Workflows: 2, Runs: 3, Conclusion: "success", WallClock: 50 * time.Minute, SlowestWorkflow: "ci", SlowestDuration: 20 * time.Minute},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownCommits(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Commit Rollup\n\n" +
+		"2 commits · median wall clock 25m0s (p90 50m0s) · slowest workflow: ci (1), docs (1)\n\n" +
+		"| Commit | Branch | Workflows | Runs | Conclusion | Wall Clock | Slowest Workflow | Slowest Took |\n" +
+		"| --- | --- | ---: | ---: | --- | ---: | --- | ---: |\n" +
+		"| `b1c2d3e` | main | 2 | 2 | cancelled | 25m0s | docs | 25m0s |\n" +
+		"| `a1` | main | 2 | 3 | success | 50m0s | ci | 20m0s |"
+
+	if got != want {
+		t.Fatalf("markdown commits mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownFailures(t *testing.T) {
+	seen := time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC)
+	// Distinct counts the clusters cut by --top as well.
+	report := metrics.FailureReport{
+		FailedJobs: 3,
+		Distinct:   5,
+		Clusters: []metrics.FailureCluster{
+			{Source: metrics.FailureSourceAnnotation, Message: "expected 3 | got 4", Count: 2, Jobs: []string{"ci / lint", "ci / test"}, FirstSeen: seen, LastSeen: seen.Add(time.Hour)},
+			{Source: metrics.FailureSourceLog, Message: "--- FAIL: TestThing\nError: Process completed with exit code 1.", Count: 1, Jobs: []string{"ci / test"}, FirstSeen: seen, LastSeen: seen},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownFailures(&buf, report, true)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Failure Triage\n\n" +
+		"3 failed jobs · 5 distinct errors\n\n" +
+		"| # | Count | Source | Jobs | First Seen | Last Seen | Error |\n" +
+		"| ---: | ---: | --- | --- | --- | --- | --- |\n" +
+		"| 1 | 2 | annotation | ci / lint +1 more | 2025-07-01 09:30 | 2025-07-01 10:30 | `expected 3 \\| got 4` |\n" +
+		"| 2 | 1 | log | ci / test | 2025-07-01 09:30 | 2025-07-01 09:30 | `Error: Process completed with exit code 1.` |\n\n" +
+		"### #2 log excerpt\n\n" +
+		"```\n--- FAIL: TestThing\nError: Process completed with exit code 1.\n```"
+
+	if got != want {
+		t.Fatalf("markdown failures mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+
+	for _, withLogs := range []bool{false, true} {
+		buf.Reset()
+		renderMarkdownFailures(&buf, metrics.FailureReport{FailedJobs: 1}, withLogs)
+		if hint := strings.Contains(buf.String(), "re-run with `--logs`"); hint == withLogs {
+			t.Fatalf("withLogs=%v: unexpected --logs hint in %q", withLogs, buf.String())
+		}
+	}
+}
+
+func TestRenderMarkdownLogGrep(t *testing.T) {
+	at := time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC)
+	matches := []logMatch{
+		{Workflow: "ci", RunID: 1, RunNumber: 12, RunAttempt: 1, Branch: "main", Job: "test", JobID: 10, Step: "Run tests",
+			Match: joblog.Match{Line: joblog.Line{Number: 42, Time: at, Text: "warning: a | b is deprecated"}}},
+		{Workflow: "ci", RunID: 2, RunNumber: 13, RunAttempt: 2, Branch: "main", Job: "test", JobID: 20,
+			Match: joblog.Match{Line: joblog.Line{Number: 7, Time: at.Add(time.Hour), Text: "warning: deprecated"}}},
+	}
+	report := buildLogGrepReport("deprecated", 5, matches)
+
+	var buf bytes.Buffer
+	renderMarkdownLogGrep(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Log Search\n\n" +
+		"2 matches in 2 of 5 jobs across 2 runs · first seen 2025-07-01 09:30 (ci #12) · last seen 2025-07-01 10:30 (ci #13 (attempt 2))\n\n" +
+		"| Time | Run | Branch | Job | Step | Line | Text |\n" +
+		"| --- | --- | --- | --- | --- | ---: | --- |\n" +
+		"| 2025-07-01 09:30 | ci #12 | main | test | Run tests | 42 | `warning: a \\| b is deprecated` |\n" +
+		"| 2025-07-01 10:30 | ci #13 (attempt 2) | main | test |  | 7 | `warning: deprecated` |"
+
+	if got != want {
+		t.Fatalf("markdown log grep mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownStorage(t *testing.T) {
+	created := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	report := metrics.StorageReport{
+		Artifacts:        1,
+		ArtifactBytes:    2048,
+		ExpiredArtifacts: 3,
+		Caches:           1,
+		CacheBytes:       512,
+		ByWorkflow: []metrics.StorageGroup{
+			{Name: "ci", Count: 1, Bytes: 2048, Share: 1, OldestAt: created, NextExpiry: created.Add(90 * 24 * time.Hour)},
+		},
+		ByKeyPrefix: []metrics.StorageGroup{
+			{Name: "Linux-go", Count: 1, Bytes: 512, Share: 1, OldestAt: created, NextExpiry: created.Add(7 * 24 * time.Hour)},
+		},
+		ArtifactRows: []metrics.ArtifactRow{
+			{Name: "coverage", Workflow: "ci", Branch: "main", Bytes: 2048, Age: 2 * 24 * time.Hour, ExpiresAt: created.Add(90 * 24 * time.Hour)},
+		},
+		CacheRows: []metrics.CacheRow{
+			{Key: "Linux-go-abc123", Ref: "refs/heads/main", Bytes: 512, Age: 5 * time.Hour, LastAccessedAt: created, ExpiresAt: created.Add(7 * 24 * time.Hour)},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownStorage(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Storage Usage\n\n" +
+		"1 artifacts (2.0 KiB) · 3 expired · 1 cache entries (512 B)\n\n" +
+		"## Artifacts by Workflow\n\n" +
+		"| Workflow | Artifacts | Size | Share | Oldest | Next Expiry |\n" +
+		"| --- | ---: | ---: | ---: | --- | --- |\n" +
+		"| ci | 1 | 2.0 KiB | 100.0% | 2025-07-01 | 2025-09-29 |\n\n" +
+		"## Largest Artifacts\n\n" +
+		"| Artifact | Workflow | Branch | Size | Age | Expires |\n" +
+		"| --- | --- | --- | ---: | ---: | --- |\n" +
+		"| coverage | ci | main | 2.0 KiB | 2d | 2025-09-29 |\n\n" +
+		"## Caches by Key Prefix\n\n" +
+		"| Key Prefix | Entries | Size | Share | Oldest | Next Eviction |\n" +
+		"| --- | ---: | ---: | ---: | --- | --- |\n" +
+		"| Linux-go | 1 | 512 B | 100.0% | 2025-07-01 | 2025-07-08 |\n\n" +
+		"## Largest Cache Entries\n\n" +
+		"| Key | Ref | Size | Age | Last Used | Evicted |\n" +
+		"| --- | --- | ---: | ---: | --- | --- |\n" +
+		"| `Linux-go-abc123` | refs/heads/main | 512 B | 5h | 2025-07-01 | 2025-07-08 |"
+
+	if got != want {
+		t.Fatalf("markdown storage mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownDeployments(t *testing.T) {
+	created := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	report := metrics.DeploymentReport{
+		Environments: []metrics.EnvironmentRow{{
+			Environment:        "production",
+			Deployments:        2,
+			Succeeded:          1,
+			Failed:             1,
+			ChangeFailureRate:  0.5,
+			PerDay:             0.25,
+			Approvals:          1,
+			MedianApprovalWait: 30 * time.Minute,
+			P90ApprovalWait:    30 * time.Minute,
+			MedianExecution:    20 * time.Minute,
+			MedianLeadTime:     80 * time.Minute,
+			P90LeadTime:        80 * time.Minute,
+			LastDeployedAt:     created.Add(50 * time.Minute),
+		}},
+		Deployments: []metrics.DeploymentRow{
+			{Environment: "production", Workflow: "deploy", Ref: "main", State: metrics.DeploymentSucceeded, CreatedAt: created, ApprovalWait: 30 * time.Minute, Execution: 20 * time.Minute, LeadTime: 80 * time.Minute},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownDeployments(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Deployments\n\n" +
+		"| Environment | Deploys | Per Day | Failed (CFR) | Approvals | Approval Wait | Run Time | Lead Time | Last Deploy |\n" +
+		"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |\n" +
+		"| production | 2 | 0.25 | 1 (50.0%) | 1 | 30m0s (p90 30m0s) | 20m0s | 1h20m0s (p90 1h20m0s) | 2025-07-01 09:50 |\n\n" +
+		"## Recent Deployments\n\n" +
+		"| Environment | Workflow | Ref | State | Created | Approval Wait | Run Time | Lead Time |\n" +
+		"| --- | --- | --- | --- | --- | ---: | ---: | ---: |\n" +
+		"| production | deploy | main | success | 2025-07-01 09:00 | 30m0s | 20m0s | 1h20m0s |"
+
+	if got != want {
+		t.Fatalf("markdown deployments mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownSchedules(t *testing.T) {
+	expected := time.Date(2025, 7, 2, 3, 0, 0, 0, time.UTC)
+	report := metrics.ScheduleReport{
+		Workflows: []metrics.ScheduleRow{{
+			Workflow:  "nightly",
+			State:     "active",
+			Schedules: []string{"0 3 * * *"},
+			Expected:  5,
+			Ran:       2,
+			Missed:    2,
+			Pending:   1,
+			MissRate:  0.5,
+			DelayP50:  5 * time.Minute,
+			DelayP90:  25 * time.Minute,
+			MaxDelay:  25 * time.Minute,
+			LastRunAt: expected.Add(48*time.Hour + 2*time.Hour),
+		}},
+		Missed: []metrics.MissedRun{{Workflow: "nightly", Schedule: "0 3 * * *", ExpectedAt: expected}},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownSchedules(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Scheduled Workflows\n\n" +
+		"1 scheduled workflows · 5 expected runs · 2 missed\n\n" +
+		"| Workflow | Schedule | State | Expected | Ran | Missed | Pending | Delay p50 | Delay p90 | Max Delay | Last Run |\n" +
+		"| --- | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |\n" +
+		"| nightly | `0 3 * * *` | active | 5 | 2 | 2 (50.0%) | 1 | 5m0s | 25m0s | 25m0s | 2025-07-04 05:00 |\n\n" +
+		"## Missed Executions\n\n" +
+		"| Workflow | Schedule | Expected At |\n" +
+		"| --- | --- | --- |\n" +
+		"| nightly | `0 3 * * *` | 2025-07-02 03:00 |"
+
+	if got != want {
+		t.Fatalf("markdown schedules mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownActors(t *testing.T) {
+	report := metrics.AttributionReport{
+		Runs:   4,
+		CITime: 100 * time.Minute,
+		ByKind: []metrics.AttributionRow{
+			{Name: metrics.ActorKindBot, Runs: 3, Failed: 1, FailureRate: 1.0 / 3, CITime: time.Hour, Share: 0.6},
+			{Name: metrics.ActorKindHuman, Runs: 1, CITime: 40 * time.Minute, Share: 0.4},
+		},
+		ByActor: []metrics.AttributionRow{
+			{Name: "dependabot[bot]", Kind: metrics.ActorKindBot, Events: []string{"pull_request", "push"}, Runs: 3, Failed: 1, FailureRate: 1.0 / 3, CITime: time.Hour, Share: 0.6},
+		},
+		ByEvent: []metrics.AttributionRow{
+			{Name: "pull_request", Runs: 4, Failed: 1, FailureRate: 0.25, CITime: 100 * time.Minute, Share: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownActors(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Trigger Attribution\n\n" +
+		"4 runs · 1h40m0s CI time · 60.0% of it triggered by bots\n\n" +
+		"| Actor Type | Runs | Failed | CI Time | Share |\n" +
+		"| --- | ---: | ---: | ---: | ---: |\n" +
+		"| bot | 3 | 1 (33.3%) | 1h0m0s | 60.0% |\n" +
+		"| human | 1 | 0 (0%) | 40m0s | 40.0% |\n\n" +
+		"## By Actor\n\n" +
+		"| Actor | Type | Events | Runs | Failed | CI Time | Share |\n" +
+		"| --- | --- | --- | ---: | ---: | ---: | ---: |\n" +
+		"| dependabot[bot] | bot | pull_request, push | 3 | 1 (33.3%) | 1h0m0s | 60.0% |\n\n" +
+		"## By Event\n\n" +
+		"| Event | Runs | Failed | CI Time | Share |\n" +
+		"| --- | ---: | ---: | ---: | ---: |\n" +
+		"| pull_request | 4 | 1 (25.0%) | 1h40m0s | 100.0% |"
+
+	if got != want {
+		t.Fatalf("markdown actors mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
+
+func TestRenderMarkdownReusable(t *testing.T) {
+	report := metrics.ReusableReport{
+		Workflows: []metrics.ReusableWorkflowRow{{
+			Workflow:    "org/shared/.github/workflows/build.yml",
+			Refs:        []string{"v2"},
+			Callers:     []string{"Build (org/api)", "CI (org/app)"},
+			Calls:       2,
+			FailedCalls: 1,
+			FailureRate: 0.5,
+			Jobs:        3,
+			CITime:      50 * time.Minute,
+			Share:       0.625,
+			MedianCall:  20 * time.Minute,
+			P90Call:     30 * time.Minute,
+		}},
+	}
+
+	var buf bytes.Buffer
+	renderMarkdownReusable(&buf, report)
+	got := strings.TrimSpace(buf.String())
+
+	const want = "# Reusable Workflows\n\n" +
+		"| Reusable Workflow | Refs | Callers | Calls | Failed Calls | Jobs | CI Time | Share | Call Duration |\n" +
+		"| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+		"| org/shared/.github/workflows/build.yml | v2 | 2 | 2 | 1 (50.0%) | 3 | 50m0s | 62.5% | 20m0s (p90 30m0s) |\n\n" +
+		"## Actions\n\n" +
+		"_No action steps found in the specified time range._"
+
+	if got != want {
+		t.Fatalf("markdown reusable mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+const flagMatrixAxis = "matrix-axis"
+
+// matrixAxisFunc resolves --matrix-axis and returns the label to show for
+// the axis. A number selects the matrix value at that position in job names;
+// a name is looked up in the declared matrix and therefore needs workflow
+// files.
+func matrixAxisFunc(axis string, files map[int64]*workflowfile.Workflow) (metrics.MatrixAxisFunc, string, error) {
+	if position, err := strconv.Atoi(axis); err == nil {
+		if position <= 0 {
+			return nil, "", fmt.Errorf("--%s position must be greater than 0", flagMatrixAxis)
+		}
+		return func(_ int64, _ string, values []string) (string, bool) {
+			if position > len(values) {
+				return "", false
+			}
+			return values[position-1], true
+		}, "axis " + axis, nil
+	}
+
+	if files == nil {
+		return nil, "", fmt.Errorf("--%s %s needs --%s or --%s to look up axis names; pass a position such as 1 instead", flagMatrixAxis, axis, flagWorkflowDir, flagFetchWorkflowFiles)
+	}
+	return func(workflowID int64, job string, values []string) (string, bool) {
+		wf := files[workflowID]
+		if wf == nil {
+			return "", false
+		}
+		declared := wf.MatchJob(job)
+		if declared == nil || declared.Matrix == nil {
+			return "", false
+		}
+		for i, a := range declared.Matrix.Axes {
+			if a.Name == axis && i < len(values) {
+				return values[i], true
+			}
+		}
+		return "", false
+	}, axis, nil
+}
+
+// expandMatrix lists each job followed by its matrix cells, labelling cells by
+// their matrix values.
+func expandMatrix(jobs []metrics.JobSummaryRow) []metrics.JobSummaryRow {
+	out := make([]metrics.JobSummaryRow, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, job)
+		for _, cell := range job.Matrix {
+			cell.Job = "↳ " + strings.Join(cell.MatrixValues, ", ")
+			out = append(out, cell)
+		}
+	}
+	return out
+}
+
+func collectMatrixAxis(rows []metrics.SummaryRow) []metrics.MatrixAxisRow {
+	var out []metrics.MatrixAxisRow
+	for _, row := range rows {
+		out = append(out, row.MatrixAxis...)
+	}
+	return out
+}
+
+func renderColoredMatrixAxis(w io.Writer, axisRows []metrics.MatrixAxisRow) {
+	title := color.New(color.FgHiWhite, color.Bold)
+	title.Fprintf(w, "🧮 Matrix by %s\n", axisRows[0].Axis)
+
+	header := []string{"Workflow", "Job", axisRows[0].Axis, "Cells", "Runs", "Failure Rate", "Avg Duration", "P95 Duration", "P90 Queue"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+	)
+
+	for _, row := range axisRows {
+		table.Append([]string{
+			row.Workflow,
+			row.Job,
+			row.Value,
+			fmt.Sprintf("%d", row.Cells),
+			fmt.Sprintf("%d", row.Runs),
+			output.FormatFailureRate(row.FailureRate),
+			output.FormatDuration(row.AvgDuration),
+			output.FormatDuration(row.DurationPercentiles.P95),
+			output.FormatDuration(row.QueuePercentiles.P90),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownMatrixAxis(w io.Writer, axisRows []metrics.MatrixAxisRow) {
+	fmt.Fprintf(w, "## Matrix by %s\n\n", axisRows[0].Axis)
+	fmt.Fprintf(w, "| Workflow | Job | %s | Cells | Runs | Failure Rate | Avg Duration | P95 Duration | P90 Queue |\n", axisRows[0].Axis)
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range axisRows {
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %d | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(row.Workflow),
+			escapeMarkdownCell(row.Job),
+			escapeMarkdownCell(row.Value),
+			row.Cells,
+			row.Runs,
+			output.FormatFailureRate(row.FailureRate),
+			output.FormatDuration(row.AvgDuration),
+			output.FormatDuration(row.DurationPercentiles.P95),
+			output.FormatDuration(row.QueuePercentiles.P90),
+		)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newPRsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prs <owner>/<repo>",
+		Short: "Report CI time and time to green per pull request",
+		Long: heredoc.Doc(`
+			Group workflow runs by the pull request they ran for and report, per pull request, the
+			runner time CI consumed, the number of pushes and re-runs, and the time from the first
+			push to the first head commit on which every workflow finished green.
+
+			Runs are matched to pull requests through the runs API, which does not link runs of pull
+			requests opened from forks.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			report := metrics.PullRequests(collection.records, collection.from, collection.to, policy)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Rows) > top {
+				report.Rows = report.Rows[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownPRs(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredPRs(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Int(flagTop, 20, "Show only the N rows with the most CI time (0 shows all; JSON always includes all)")
+
+	return cmd
+}
+
+func prsOverview(report metrics.PullRequestReport) string {
+	return fmt.Sprintf("%d pull requests · %s CI time (median %s per PR) · %d went green, median time to green %s (p90 %s)",
+		report.PullRequests,
+		output.FormatDuration(report.CITime),
+		output.FormatDuration(report.MedianCITime),
+		report.Green,
+		output.FormatDuration(report.MedianTimeToGreen),
+		output.FormatDuration(report.P90TimeToGreen),
+	)
+}
+
+func renderColoredPRs(w io.Writer, report metrics.PullRequestReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🔀 Pull Request CI Impact")
+	fmt.Fprintln(w)
+
+	if report.PullRequests == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs linked to pull requests found in the specified time range")
+		return
+	}
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprintln(w, prsOverview(report))
+	fmt.Fprintln(w)
+
+	header := []string{"PR", "Branch", "Runs", "Pushes", "Re-runs", "Failed", "CI Time", "Time to Green"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+	)
+
+	for _, row := range report.Rows {
+		table.Append([]string{
+			fmt.Sprintf("#%d", row.Number),
+			row.Branch,
+			fmt.Sprintf("%d", row.Runs),
+			fmt.Sprintf("%d", row.Pushes),
+			fmt.Sprintf("%d", row.Reruns),
+			fmt.Sprintf("%d", row.Failed),
+			output.FormatDuration(row.CITime),
+			output.FormatDuration(row.TimeToGreen),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownPRs(w io.Writer, report metrics.PullRequestReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Pull Request CI Impact")
+	fmt.Fprintln(w)
+
+	if report.PullRequests == 0 {
+		fmt.Fprintln(w, "_No workflow runs linked to pull requests found in the specified time range._")
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n\n", prsOverview(report))
+	fmt.Fprintln(w, "| PR | Branch | Runs | Pushes | Re-runs | Failed | CI Time | Time to Green |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "| #%d | %s | %d | %d | %d | %d | %s | %s |\n",
+			row.Number,
+			escapeMarkdownCell(row.Branch),
+			row.Runs,
+			row.Pushes,
+			row.Reruns,
+			row.Failed,
+			output.FormatDuration(row.CITime),
+			output.FormatDuration(row.TimeToGreen),
+		)
+	}
+	fmt.Fprintln(w)
+}
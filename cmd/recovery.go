@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const flagRecoveryAllBranches = "all-branches"
+
+type recoveryReport struct {
+	Workflows []metrics.RecoveryRow `json:"workflows"`
+	Outages   []metrics.Outage      `json:"outages"`
+}
+
+func newRecoveryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recovery <owner>/<repo>",
+		Short: "Report failure streaks and mean time to recovery",
+		Long: heredoc.Doc(`
+			Reconstruct red streaks for each workflow on a branch: when the branch first went red,
+			how many consecutive runs failed, and how long it took until the next successful run
+			completed (time to recovery). Runs that are not counted as failures by --failure-conclusions,
+			such as skipped runs and cancellations superseded by a newer run, neither start nor end a
+			streak.
+
+			Only the repository's default branch is analyzed unless --branch or --all-branches is given.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+			allBranches, err := cmd.Flags().GetBool(flagRecoveryAllBranches)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			opts := collectOptions{}
+			if viper.GetString(flagBranch) == "" && !allBranches {
+				repository, err := client.GetRepository(ctx, owner, repo)
+				if err != nil {
+					return fmt.Errorf("failed to look up default branch of %s/%s: %w", owner, repo, err)
+				}
+				opts.branch = repository.DefaultBranch
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, opts)
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			rows, outages := metrics.Recovery(collection.records, collection.to, policy)
+			report := recoveryReport{Workflows: rows, Outages: outages}
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Outages) > top {
+				report.Outages = report.Outages[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownRecovery(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredRecovery(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagTop, 20, "Show only the N longest outages (0 shows all; JSON always includes all)")
+	cmd.Flags().Bool(flagRecoveryAllBranches, false, "Analyze every branch instead of the default branch")
+
+	return cmd
+}
+
+func formatOutageEnd(outage metrics.Outage) string {
+	if !outage.Recovered {
+		return "still red"
+	}
+	return outage.End.Format(time.RFC3339)
+}
+
+func formatBranchStatus(row metrics.RecoveryRow) string {
+	if row.Red {
+		return "red"
+	}
+	return "green"
+}
+
+func renderColoredRecovery(w io.Writer, report recoveryReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🩹 Failure Recovery")
+	fmt.Fprintln(w)
+
+	if len(report.Workflows) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflow runs found in the specified time range")
+		return
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Workflow", "Branch", "Runs", "Outages", "MTTR p50", "MTTR p90", "Mean MTTR", "Longest Outage", "Longest Streak", "Status"})
+	table.SetBorder(true)
+	headerColors := make([]tablewriter.Colors, 10)
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+	)
+
+	for _, row := range report.Workflows {
+		table.Append([]string{
+			row.Workflow,
+			row.Branch,
+			fmt.Sprintf("%d", row.Runs),
+			fmt.Sprintf("%d", row.Outages),
+			output.FormatDuration(row.MTTRPercentiles.P50),
+			output.FormatDuration(row.MTTRPercentiles.P90),
+			output.FormatDuration(row.MeanTimeToRecover),
+			output.FormatDuration(row.LongestOutage),
+			fmt.Sprintf("%d", row.LongestStreak),
+			formatBranchStatus(row),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+
+	if len(report.Outages) == 0 {
+		return
+	}
+
+	outageTitle := color.New(color.FgHiWhite, color.Bold)
+	outageTitle.Fprintln(w, "⏱️  Longest Outages")
+
+	outageTable := tablewriter.NewWriter(w)
+	outageTable.SetHeader([]string{"Workflow", "Branch", "Went Red", "Recovered", "Failed Runs", "Duration", "First Failing Run"})
+	outageTable.SetBorder(true)
+	outageTable.SetHeaderColor(headerColors[:7]...)
+	outageTable.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+	)
+
+	for _, outage := range report.Outages {
+		outageTable.Append([]string{
+			escapeMarkdownCell(outage.Workflow),
+			escapeMarkdownCell(outage.Branch),
+			outage.Start.Format(time.RFC3339),
+			formatOutageEnd(outage),
+			fmt.Sprintf("%d", outage.FailedRuns),
+			output.FormatDuration(outage.Duration),
+			fmt.Sprintf("%d", outage.FirstRunID),
+		})
+	}
+
+	outageTable.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownRecovery(w io.Writer, report recoveryReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Failure Recovery")
+	fmt.Fprintln(w)
+
+	if len(report.Workflows) == 0 {
+		fmt.Fprintln(w, "_No workflow runs found in the specified time range._")
+		return
+	}
+
+	fmt.Fprintln(w, "| Workflow | Branch | Runs | Outages | MTTR p50 | MTTR p90 | Mean MTTR | Longest Outage | Longest Streak | Status |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |")
+	for _, row := range report.Workflows {
+		fmt.Fprintf(w, "| %s | %s | %d | %d | %s | %s | %s | %s | %d | %s |\n",
+			escapeMarkdownCell(row.Workflow),
+			escapeMarkdownCell(row.Branch),
+			row.Runs,
+			row.Outages,
+			output.FormatDuration(row.MTTRPercentiles.P50),
+			output.FormatDuration(row.MTTRPercentiles.P90),
+			output.FormatDuration(row.MeanTimeToRecover),
+			output.FormatDuration(row.LongestOutage),
+			row.LongestStreak,
+			formatBranchStatus(row),
+		)
+	}
+	fmt.Fprintln(w)
+
+	if len(report.Outages) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## Longest Outages")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Workflow | Branch | Went Red | Recovered | Failed Runs | Duration | First Failing Run |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | ---: | ---: | ---: |")
+	for _, outage := range report.Outages {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %d | %s | %d |\n",
+			escapeMarkdownCell(outage.Workflow),
+			escapeMarkdownCell(outage.Branch),
+			outage.Start.Format(time.RFC3339),
+			formatOutageEnd(outage),
+			outage.FailedRuns,
+			output.FormatDuration(outage.Duration),
+			outage.FirstRunID,
+		)
+	}
+	fmt.Fprintln(w)
+}
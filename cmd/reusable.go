@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newReusableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reusable <owner>/<repo>...",
+		Short: "Aggregate CI time and failures by called reusable workflow and action",
+		Long: heredoc.Doc(`
+			Attribute jobs to the reusable workflows that ran them and steps to the actions they
+			ran, and aggregate them across every caller in the given repositories. Shows the cost
+			and failure rate of shared workflows and actions rather than of the workflows that call
+			them.
+
+			Jobs of a reusable workflow are named "caller / job"; the caller job is looked up in the
+			workflow files to find the workflow it calls. Workflow files are fetched from GitHub
+			unless --workflow-dir is given, which is only allowed for a single repository. Actions
+			are recognized by the default step name ("Run owner/action@ref"), so steps given a
+			custom name are not attributed.
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			dir, err := cmd.Flags().GetString(flagWorkflowDir)
+			if err != nil {
+				return err
+			}
+			if dir != "" && len(args) > 1 {
+				return fmt.Errorf("--%s can only be used with a single repository", flagWorkflowDir)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			var sources []metrics.ReusableSource
+			for _, arg := range args {
+				owner, repo, err := util.ParseRepo(arg)
+				if err != nil {
+					return err
+				}
+
+				collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+				if err != nil {
+					return err
+				}
+				if collection == nil {
+					continue
+				}
+
+				files, err := loadWorkflowFiles(ctx, cmd, client, collection)
+				if err != nil {
+					return err
+				}
+				if files == nil {
+					if files, err = fetchWorkflowFiles(ctx, client, collection); err != nil {
+						return err
+					}
+				}
+
+				sources = append(sources, metrics.ReusableSource{
+					Repo:    fmt.Sprintf("%s/%s", owner, repo),
+					Records: collection.records,
+					Files:   files,
+					From:    collection.from,
+					To:      collection.to,
+				})
+			}
+			if len(sources) == 0 {
+				return nil
+			}
+
+			report := metrics.Reusable(sources, policy)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 {
+				if len(report.Workflows) > top {
+					report.Workflows = report.Workflows[:top]
+				}
+				if len(report.Actions) > top {
+					report.Actions = report.Actions[:top]
+				}
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownReusable(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredReusable(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Int(flagTop, 20, "Show only the N reusable workflows and actions with the most CI time (0 shows all; JSON always includes all)")
+	addWorkflowFileFlags(cmd)
+
+	return cmd
+}
+
+// formatUsesRefs renders the refs a workflow or action was used at.
+func formatUsesRefs(refs []string) string {
+	if len(refs) == 0 {
+		return "-"
+	}
+	return strings.Join(refs, ", ")
+}
+
+func reusableWorkflowCells(row metrics.ReusableWorkflowRow) []string {
+	return []string{
+		row.Workflow,
+		formatUsesRefs(row.Refs),
+		fmt.Sprintf("%d", len(row.Callers)),
+		fmt.Sprintf("%d", row.Calls),
+		fmt.Sprintf("%d (%s)", row.FailedCalls, output.FormatFailureRate(row.FailureRate)),
+		fmt.Sprintf("%d", row.Jobs),
+		output.FormatDuration(row.CITime),
+		output.FormatFailureRate(row.Share),
+		fmt.Sprintf("%s (p90 %s)", output.FormatDuration(row.MedianCall), output.FormatDuration(row.P90Call)),
+	}
+}
+
+func actionCells(row metrics.ActionRow) []string {
+	return []string{
+		row.Action,
+		formatUsesRefs(row.Refs),
+		fmt.Sprintf("%d", row.Steps),
+		fmt.Sprintf("%d (%s)", row.Failed, output.FormatFailureRate(row.FailureRate)),
+		output.FormatDuration(row.AvgDuration),
+		output.FormatDuration(row.TotalTime),
+	}
+}
+
+var (
+	reusableWorkflowHeader = []string{"Reusable Workflow", "Refs", "Callers", "Calls", "Failed Calls", "Jobs", "CI Time", "Share", "Call Duration"}
+	actionHeader           = []string{"Action", "Refs", "Steps", "Failed", "Avg Duration", "Total Time"}
+)
+
+func renderColoredReusable(w io.Writer, report metrics.ReusableReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "♻️  Reusable Workflows")
+	fmt.Fprintln(w)
+
+	warningColor := color.New(color.FgYellow)
+	if len(report.Workflows) == 0 {
+		warningColor.Fprintln(w, "⚠️  No jobs run by reusable workflows found in the specified time range")
+	} else {
+		headerColors := make([]tablewriter.Colors, len(reusableWorkflowHeader))
+		for i := range headerColors {
+			headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+		}
+
+		table := tablewriter.NewWriter(w)
+		table.SetHeader(reusableWorkflowHeader)
+		table.SetBorder(true)
+		table.SetHeaderColor(headerColors...)
+		table.SetColumnColor(
+			tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgHiBlackColor},
+			tablewriter.Colors{tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgGreenColor},
+			tablewriter.Colors{tablewriter.FgRedColor},
+			tablewriter.Colors{tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgBlueColor},
+			tablewriter.Colors{tablewriter.FgMagentaColor},
+			tablewriter.Colors{tablewriter.FgYellowColor},
+		)
+		for _, row := range report.Workflows {
+			table.Append(reusableWorkflowCells(row))
+		}
+		table.Render()
+	}
+
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🧩 Actions")
+	fmt.Fprintln(w)
+
+	if len(report.Actions) == 0 {
+		warningColor.Fprintln(w, "⚠️  No action steps found in the specified time range")
+		return
+	}
+
+	headerColors := make([]tablewriter.Colors, len(actionHeader))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(actionHeader)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+	)
+	for _, row := range report.Actions {
+		table.Append(actionCells(row))
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownReusable(w io.Writer, report metrics.ReusableReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Reusable Workflows")
+	fmt.Fprintln(w)
+
+	if len(report.Workflows) == 0 {
+		fmt.Fprintln(w, "_No jobs run by reusable workflows found in the specified time range._")
+	} else {
+		fmt.Fprintln(w, "| "+strings.Join(reusableWorkflowHeader, " | ")+" |")
+		fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |")
+		for _, row := range report.Workflows {
+			cells := reusableWorkflowCells(row)
+			for i := range cells {
+				cells[i] = escapeMarkdownCell(cells[i])
+			}
+			fmt.Fprintln(w, "| "+strings.Join(cells, " | ")+" |")
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Actions")
+	fmt.Fprintln(w)
+
+	if len(report.Actions) == 0 {
+		fmt.Fprintln(w, "_No action steps found in the specified time range._")
+		return
+	}
+
+	fmt.Fprintln(w, "| "+strings.Join(actionHeader, " | ")+" |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: |")
+	for _, row := range report.Actions {
+		cells := actionCells(row)
+		for i := range cells {
+			cells[i] = escapeMarkdownCell(cells[i])
+		}
+		fmt.Fprintln(w, "| "+strings.Join(cells, " | ")+" |")
+	}
+	fmt.Fprintln(w)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/spf13/cobra"
@@ -28,7 +29,10 @@ const (
 	flagThreads  = "threads"
 	flagCacheTTL = "cache-ttl"
 	flagNoCache  = "no-cache"
+	flagLogCache = "log-cache-ttl"
 	flagLogLevel = "log-level"
+	flagFailures = "failure-conclusions"
+	flagTop      = "top"
 	defaultLast  = "30d"
 )
 
@@ -85,7 +89,9 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().Int(flagThreads, 4, "Maximum number of concurrent API requests")
 	cmd.PersistentFlags().Duration(flagCacheTTL, 0, "Duration to cache API responses (e.g. 10m, 1h)")
 	cmd.PersistentFlags().Bool(flagNoCache, false, "Disable on-disk API response cache")
+	cmd.PersistentFlags().Duration(flagLogCache, 7*24*time.Hour, "Duration to cache downloaded job logs")
 	cmd.PersistentFlags().String(flagLogLevel, "info", "Minimum log level (debug|info|warn|error)")
+	cmd.PersistentFlags().StringSlice(flagFailures, nil, "Run and job conclusions counted as failures (default failure,cancelled,timed_out,action_required,stale)")
 
 	viper.SetEnvPrefix("GH_ACTIONS_METRICS")
 	viper.AutomaticEnv()
@@ -96,6 +102,24 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newSummaryCmd())
 	cmd.AddCommand(newWorkflowsCmd())
 	cmd.AddCommand(newRunsCmd())
+	cmd.AddCommand(newCheckCmd())
+	cmd.AddCommand(newRecoveryCmd())
+	cmd.AddCommand(newTUICmd())
+	cmd.AddCommand(newRunCmd())
+	cmd.AddCommand(newCriticalPathCmd())
+	cmd.AddCommand(newTimeoutsCmd())
+	cmd.AddCommand(newRunnersCmd())
+	cmd.AddCommand(newConcurrencyCmd())
+	cmd.AddCommand(newHeatmapCmd())
+	cmd.AddCommand(newPRsCmd())
+	cmd.AddCommand(newCommitsCmd())
+	cmd.AddCommand(newFailuresCmd())
+	cmd.AddCommand(newLogsCmd())
+	cmd.AddCommand(newStorageCmd())
+	cmd.AddCommand(newDeploymentsCmd())
+	cmd.AddCommand(newSchedulesCmd())
+	cmd.AddCommand(newActorsCmd())
+	cmd.AddCommand(newReusableCmd())
 
 	return cmd
 }
@@ -195,6 +219,16 @@ func parseScalarDuration(value string, unit time.Duration) (time.Duration, error
 	return time.Duration(amount * float64(unit)), nil
 }
 
+// failurePolicyFromFlags returns the failure policy selected with
+// --failure-conclusions.
+func failurePolicyFromFlags() (metrics.FailurePolicy, error) {
+	policy, err := metrics.ParseFailurePolicy(mustGetStringSlice(flagFailures))
+	if err != nil {
+		return metrics.FailurePolicy{}, fmt.Errorf("invalid --%s: %w", flagFailures, err)
+	}
+	return policy, nil
+}
+
 func mustGetStringSlice(flag string) []string {
 	values := viper.GetStringSlice(flag)
 	out := make([]string, 0, len(values))
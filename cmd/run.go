@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagRunSteps = "steps"
+	flagRunWidth = "width"
+
+	ganttLabelWidth = 36
+	ganttStepFill   = '▒'
+)
+
+type runDetail struct {
+	Run          githubapi.WorkflowRun   `json:"run"`
+	Jobs         []githubapi.WorkflowJob `json:"jobs"`
+	CriticalPath []string                `json:"critical_path"`
+
+	critical map[int]bool
+}
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Inspect a single workflow run",
+	}
+
+	cmd.AddCommand(newRunShowCmd())
+	return cmd
+}
+
+func newRunShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <owner>/<repo> <run-id>",
+		Short: "Show a Gantt-style timeline of a run's jobs and steps",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+			runID, err := strconv.ParseInt(strings.TrimSpace(args[1]), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid run id %q", args[1])
+			}
+
+			showSteps, err := cmd.Flags().GetBool(flagRunSteps)
+			if err != nil {
+				return err
+			}
+			width, err := cmd.Flags().GetInt(flagRunWidth)
+			if err != nil {
+				return err
+			}
+			if width <= 0 {
+				width = 120
+				if w, _, err := term.FromEnv().Size(); err == nil && w > 0 {
+					width = w
+				}
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			run, err := client.GetWorkflowRun(ctx, owner, repo, runID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch run %d: %w", runID, err)
+			}
+			jobs, err := client.ListJobs(ctx, owner, repo, runID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch jobs for run %d: %w", runID, err)
+			}
+
+			detail := newRunDetail(run, jobs)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(detail)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownRun(stdout, detail, showSteps, width)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredRun(os.Stdout, detail, showSteps, width, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagRunSteps, true, "Include steps in the timeline")
+	cmd.Flags().Int(flagRunWidth, 0, "Total width of the timeline output (defaults to the terminal width)")
+
+	return cmd
+}
+
+func newRunDetail(run githubapi.WorkflowRun, jobs []githubapi.WorkflowJob) runDetail {
+	sorted := append([]githubapi.WorkflowJob(nil), jobs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return jobReadyAt(sorted[i]).Before(jobReadyAt(sorted[j]))
+	})
+
+	detail := runDetail{Run: run, Jobs: sorted, CriticalPath: []string{}, critical: make(map[int]bool)}
+	for _, idx := range metrics.CriticalPath(sorted, nil) {
+		detail.critical[idx] = true
+		detail.CriticalPath = append(detail.CriticalPath, sorted[idx].Name)
+	}
+	return detail
+}
+
+func jobReadyAt(job githubapi.WorkflowJob) time.Time {
+	if !job.CreatedAt.IsZero() {
+		return job.CreatedAt
+	}
+	return job.StartedAt
+}
+
+// runStartedAt returns when the run's current attempt started. CreatedAt is
+// kept from the first attempt, so it would stretch the timeline of a re-run
+// back to the original run.
+func runStartedAt(run githubapi.WorkflowRun) time.Time {
+	if !run.RunStartedAt.IsZero() {
+		return run.RunStartedAt
+	}
+	return run.CreatedAt
+}
+
+// timelineBounds returns the span covered by the run's current attempt and
+// its jobs.
+func (d runDetail) timelineBounds() (time.Time, time.Time) {
+	origin := runStartedAt(d.Run)
+	end := d.Run.UpdatedAt
+	for _, job := range d.Jobs {
+		if ready := jobReadyAt(job); !ready.IsZero() && (origin.IsZero() || ready.Before(origin)) {
+			origin = ready
+		}
+		if job.CompletedAt.After(end) {
+			end = job.CompletedAt
+		}
+	}
+	return origin, end
+}
+
+// criticalPathDuration is the time from the start of the first critical job
+// to the end of the last one.
+func (d runDetail) criticalPathDuration() time.Duration {
+	var first, last time.Time
+	for idx := range d.critical {
+		job := d.Jobs[idx]
+		if ready := jobReadyAt(job); first.IsZero() || ready.Before(first) {
+			first = ready
+		}
+		if job.CompletedAt.After(last) {
+			last = job.CompletedAt
+		}
+	}
+	if first.IsZero() || last.IsZero() {
+		return 0
+	}
+	return last.Sub(first)
+}
+
+// ganttLines renders the timeline and reports which lines belong to jobs on
+// the critical path.
+func (d runDetail) ganttLines(showSteps bool, width int) ([]string, []bool) {
+	origin, end := d.timelineBounds()
+
+	var (
+		bars     []output.GanttBar
+		critical []bool
+	)
+	for i, job := range d.Jobs {
+		label := job.Name
+		if d.critical[i] {
+			label = "* " + label
+		}
+		if job.RunnerName != "" {
+			label = fmt.Sprintf("%s (%s)", label, job.RunnerName)
+		}
+		bars = append(bars, output.GanttBar{Label: label, Queued: job.CreatedAt, Start: job.StartedAt, End: job.CompletedAt})
+		critical = append(critical, d.critical[i])
+
+		if !showSteps {
+			continue
+		}
+		for _, step := range job.Steps {
+			bars = append(bars, output.GanttBar{Label: "    " + step.Name, Start: step.StartedAt, End: step.CompletedAt, Fill: ganttStepFill})
+			critical = append(critical, false)
+		}
+	}
+
+	barWidth := width - ganttLabelWidth - 1
+	return output.RenderGantt(bars, origin, end, ganttLabelWidth, barWidth), append(critical, false)
+}
+
+// runQueueTime is how long the current attempt waited before its first job
+// started.
+func runQueueTime(d runDetail) time.Duration {
+	started := runStartedAt(d.Run)
+	var first time.Time
+	for _, job := range d.Jobs {
+		if !job.StartedAt.IsZero() && (first.IsZero() || job.StartedAt.Before(first)) {
+			first = job.StartedAt
+		}
+	}
+	if started.IsZero() || first.IsZero() || first.Before(started) {
+		return 0
+	}
+	return first.Sub(started)
+}
+
+func (d runDetail) criticalPathSummary() string {
+	return fmt.Sprintf("%s (%s)", strings.Join(d.CriticalPath, " → "), output.FormatDuration(d.criticalPathDuration()))
+}
+
+func renderColoredRun(w io.Writer, detail runDetail, showSteps bool, width int, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	run := detail.Run
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintf(w, "🔍 Run %d · %s #%d (attempt %d)\n", run.ID, run.Name, run.RunNumber, run.RunAttempt)
+	fmt.Fprintln(w)
+
+	labelColor := color.New(color.FgHiBlack)
+	info := [][2]string{
+		{"Branch", run.HeadBranch},
+		{"Event", run.Event},
+		{"Conclusion", run.Conclusion},
+		{"Started", runStartedAt(run).Format(time.RFC3339)},
+		{"Duration", output.FormatDuration(run.Duration)},
+		{"Queued", output.FormatDuration(runQueueTime(detail))},
+	}
+	if len(detail.Jobs) > 0 {
+		info = append(info, [2]string{"Critical path", detail.criticalPathSummary()})
+	}
+	for _, kv := range info {
+		labelColor.Fprintf(w, "%-14s", kv[0])
+		fmt.Fprintln(w, kv[1])
+	}
+	fmt.Fprintln(w)
+
+	if len(detail.Jobs) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No jobs found for this run")
+		return
+	}
+
+	criticalColor := color.New(color.FgRed, color.Bold)
+	lines, critical := detail.ganttLines(showSteps, width)
+	for i, line := range lines {
+		if critical[i] {
+			criticalColor.Fprintln(w, line)
+			continue
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+	labelColor.Fprintf(w, "%c queued  %c running  %c step  * critical path\n", output.GanttQueued, output.GanttRunning, ganttStepFill)
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownRun(w io.Writer, detail runDetail, showSteps bool, width int) {
+	run := detail.Run
+	origin, _ := detail.timelineBounds()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "# Run %d · %s #%d (attempt %d)\n", run.ID, run.Name, run.RunNumber, run.RunAttempt)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "- **Branch:** %s\n", run.HeadBranch)
+	fmt.Fprintf(w, "- **Event:** %s\n", run.Event)
+	fmt.Fprintf(w, "- **Conclusion:** %s\n", run.Conclusion)
+	fmt.Fprintf(w, "- **Duration:** %s\n", output.FormatDuration(run.Duration))
+	if len(detail.Jobs) > 0 {
+		fmt.Fprintf(w, "- **Critical path:** %s\n", detail.criticalPathSummary())
+	}
+	fmt.Fprintln(w)
+
+	if len(detail.Jobs) == 0 {
+		fmt.Fprintln(w, "_No jobs found for this run._")
+		return
+	}
+
+	fmt.Fprintln(w, "| Job | Runner | Conclusion | Start | Queued | Duration | Critical |")
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | ---: | :---: |")
+	for i, job := range detail.Jobs {
+		critical := ""
+		if detail.critical[i] {
+			critical = "✓"
+		}
+		start := "-"
+		if !job.StartedAt.IsZero() {
+			start = "+" + output.FormatDuration(job.StartedAt.Sub(origin))
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(job.Name),
+			escapeMarkdownCell(job.RunnerName),
+			job.Conclusion,
+			start,
+			output.FormatDuration(job.QueueTime()),
+			output.FormatDuration(job.Duration()),
+			critical,
+		)
+	}
+	fmt.Fprintln(w)
+
+	lines, _ := detail.ganttLines(showSteps, width)
+	fmt.Fprintln(w, "```text")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w)
+}
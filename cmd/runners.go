@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const flagRunnersOrg = "org"
+
+func newRunnersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runners <owner>/<repo>",
+		Short: "Report self-hosted runner utilization",
+		Long: heredoc.Doc(`
+			List the self-hosted runners registered with the repository (and, with --org, with the
+			owning organization), join them to the jobs they ran, and report busy time, idle time,
+			peak concurrency and job count per runner and per runner label over the window.
+
+			Jobs are matched to runners by runner ID, then by runner name. Jobs that requested the
+			self-hosted label on runners that are no longer registered, such as ephemeral runners of
+			an autoscaling group, are reported as unregistered runners; their idle time only counts
+			the span between their first and last job. Listing runners needs admin access to the
+			repository or organization.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			includeOrg, err := cmd.Flags().GetBool(flagRunnersOrg)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			runners, err := client.ListRunners(ctx, owner, repo)
+			if err != nil {
+				return fmt.Errorf("failed to list runners for %s/%s: %w", owner, repo, err)
+			}
+			if includeOrg {
+				orgRunners, err := client.ListOrgRunners(ctx, owner)
+				if err != nil {
+					return fmt.Errorf("failed to list runners for organization %s: %w", owner, err)
+				}
+				runners = mergeRunners(runners, orgRunners)
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			report := metrics.RunnerUtilization(collection.records, runners, collection.from, collection.to)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownRunners(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredRunners(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+	cmd.Flags().Bool(flagRunnersOrg, false, "Also include runners registered with the organization that owns the repository")
+
+	return cmd
+}
+
+// mergeRunners appends the runners of extra that are not already in runners.
+func mergeRunners(runners, extra []githubapi.Runner) []githubapi.Runner {
+	seen := make(map[int64]bool, len(runners))
+	for _, r := range runners {
+		seen[r.ID] = true
+	}
+	for _, r := range extra {
+		if !seen[r.ID] {
+			seen[r.ID] = true
+			runners = append(runners, r)
+		}
+	}
+	return runners
+}
+
+func formatRunnerStatus(row metrics.RunnerRow) string {
+	if !row.Registered {
+		return "unregistered"
+	}
+	return row.Status
+}
+
+func renderColoredRunners(w io.Writer, report metrics.RunnerReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🖥️  Self-Hosted Runner Utilization")
+	fmt.Fprintln(w)
+
+	if len(report.Runners) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No self-hosted runners are registered and no jobs ran on self-hosted runners")
+		return
+	}
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprintf(w, "Window: %s → %s (%s)\n\n",
+		report.From.Format("2006-01-02 15:04"), report.To.Format("2006-01-02 15:04"), output.FormatDuration(report.To.Sub(report.From)))
+
+	header := []string{"Runner", "Status", "Labels", "Jobs", "Busy", "Idle", "Utilization", "Peak"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+	)
+	for _, row := range report.Runners {
+		table.Append([]string{
+			row.Runner,
+			formatRunnerStatus(row),
+			strings.Join(row.Labels, ", "),
+			fmt.Sprintf("%d", row.Jobs),
+			output.FormatDuration(row.Busy),
+			output.FormatDuration(row.Idle),
+			output.FormatFailureRate(row.Utilization),
+			fmt.Sprintf("%d", row.PeakConcurrency),
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+
+	if len(report.Labels) == 0 {
+		return
+	}
+
+	labelTitle := color.New(color.FgHiWhite, color.Bold)
+	labelTitle.Fprintln(w, "🏷️  By Label")
+
+	header = []string{"Label", "Runners", "Jobs", "Busy", "Idle", "Utilization", "Peak Concurrency"}
+	headerColors = make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	labelTable := tablewriter.NewWriter(w)
+	labelTable.SetHeader(header)
+	labelTable.SetBorder(true)
+	labelTable.SetHeaderColor(headerColors...)
+	labelTable.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+	)
+	for _, row := range report.Labels {
+		labelTable.Append([]string{
+			row.Label,
+			fmt.Sprintf("%d", row.Runners),
+			fmt.Sprintf("%d", row.Jobs),
+			output.FormatDuration(row.Busy),
+			output.FormatDuration(row.Idle),
+			output.FormatFailureRate(row.Utilization),
+			fmt.Sprintf("%d", row.PeakConcurrency),
+		})
+	}
+	labelTable.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownRunners(w io.Writer, report metrics.RunnerReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Self-Hosted Runner Utilization")
+	fmt.Fprintln(w)
+
+	if len(report.Runners) == 0 {
+		fmt.Fprintln(w, "_No self-hosted runners are registered and no jobs ran on self-hosted runners._")
+		return
+	}
+
+	fmt.Fprintf(w, "Window: %s → %s (%s)\n\n",
+		report.From.Format("2006-01-02 15:04"), report.To.Format("2006-01-02 15:04"), output.FormatDuration(report.To.Sub(report.From)))
+	fmt.Fprintln(w, "| Runner | Status | Labels | Jobs | Busy | Idle | Utilization | Peak |")
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range report.Runners {
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %s | %s | %s | %d |\n",
+			escapeMarkdownCell(row.Runner),
+			formatRunnerStatus(row),
+			escapeMarkdownCell(strings.Join(row.Labels, ", ")),
+			row.Jobs,
+			output.FormatDuration(row.Busy),
+			output.FormatDuration(row.Idle),
+			output.FormatFailureRate(row.Utilization),
+			row.PeakConcurrency,
+		)
+	}
+	fmt.Fprintln(w)
+
+	if len(report.Labels) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## By Label")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Label | Runners | Jobs | Busy | Idle | Utilization | Peak Concurrency |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range report.Labels {
+		fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %d |\n",
+			escapeMarkdownCell(row.Label),
+			row.Runners,
+			row.Jobs,
+			output.FormatDuration(row.Busy),
+			output.FormatDuration(row.Idle),
+			output.FormatFailureRate(row.Utilization),
+			row.PeakConcurrency,
+		)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/cron"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const flagMaxDelay = "max-delay"
+
+func newSchedulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedules <owner>/<repo>",
+		Short: "Compare cron schedules with the runs they actually triggered",
+		Long: heredoc.Doc(`
+			Parse the schedule triggers of each workflow file, compute when the cron expressions
+			should have fired within the window, and match those times to the runs created by the
+			schedule event. Reports the delay between the expected and actual start per workflow
+			and lists fire times that never produced a run.
+
+			GitHub delays scheduled runs under load and drops some of them entirely. A fire time
+			counts as missed when no run was created before --max-delay passed or the next fire
+			time came, whichever is earlier. Fire times within --max-delay of the end of the
+			window are reported as pending. The workflow state shows schedules GitHub disabled
+			after a period of repository inactivity.
+
+			The window always comes from --from, --to and --last; --runs is not supported because
+			the most recent runs of each workflow would not cover the same span of fire times.
+
+			Workflow files are read from a local checkout with --workflow-dir, and otherwise
+			fetched from the repository's default branch, which is where GitHub reads schedule
+			triggers from.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			maxDelay, err := cmd.Flags().GetDuration(flagMaxDelay)
+			if err != nil {
+				return err
+			}
+			if maxDelay <= 0 {
+				return fmt.Errorf("--%s must be greater than 0", flagMaxDelay)
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			// Schedules always run from the default branch, so the cron
+			// expressions of a recent run's head commit may not be the ones
+			// GitHub used.
+			dir, err := cmd.Flags().GetString(flagWorkflowDir)
+			if err != nil {
+				return err
+			}
+			var files map[int64]*workflowfile.Workflow
+			if dir != "" {
+				files = readWorkflowFiles(dir, collection)
+			} else if files, err = fetchDefaultBranchWorkflowFiles(ctx, client, collection); err != nil {
+				return err
+			}
+
+			scheduled := scheduledWorkflows(collection, files)
+			report := metrics.ScheduleDrift(collection.records, scheduled, collection.from, collection.to, maxDelay)
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 && len(report.Missed) > top {
+				report.Missed = report.Missed[:top]
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownSchedules(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredSchedules(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	cmd.Flags().Duration(flagMaxDelay, time.Hour, "Longest delay after a fire time for a run to still count as triggered by it")
+	cmd.Flags().Int(flagTop, 20, "Show only the N most recent missed executions (0 shows all; JSON always includes all)")
+	cmd.Flags().String(flagWorkflowDir, "", "Read workflow files from this local checkout of the repository")
+
+	return cmd
+}
+
+// scheduledWorkflows parses the cron schedules of the collected workflows.
+// Invalid expressions are skipped with a warning.
+func scheduledWorkflows(collection *runCollection, files map[int64]*workflowfile.Workflow) []metrics.ScheduledWorkflow {
+	var scheduled []metrics.ScheduledWorkflow
+	for _, wf := range collection.workflows {
+		file, ok := files[wf.ID]
+		if !ok || len(file.Schedules) == 0 {
+			continue
+		}
+		sw := metrics.ScheduledWorkflow{Workflow: wf}
+		for _, expr := range file.Schedules {
+			schedule, err := cron.Parse(expr)
+			if err != nil {
+				slog.Warn("failed to parse schedule", slog.String("workflow", wf.Name), slog.String("error", err.Error()))
+				continue
+			}
+			sw.Schedules = append(sw.Schedules, schedule)
+		}
+		scheduled = append(scheduled, sw)
+	}
+	return scheduled
+}
+
+func formatScheduleState(state string) string {
+	if state == "" || state == "active" {
+		return state
+	}
+	return color.RedString(state)
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func schedulesOverview(report metrics.ScheduleReport) string {
+	var expected, missed int
+	for _, row := range report.Workflows {
+		expected += row.Expected
+		missed += row.Missed
+	}
+	return fmt.Sprintf("%d scheduled workflows · %d expected runs · %d missed", len(report.Workflows), expected, missed)
+}
+
+func scheduleCells(row metrics.ScheduleRow, schedules string) []string {
+	return []string{
+		row.Workflow,
+		schedules,
+		row.State,
+		fmt.Sprintf("%d", row.Expected),
+		fmt.Sprintf("%d", row.Ran),
+		fmt.Sprintf("%d (%s)", row.Missed, output.FormatFailureRate(row.MissRate)),
+		fmt.Sprintf("%d", row.Pending),
+		output.FormatDuration(row.DelayP50),
+		output.FormatDuration(row.DelayP90),
+		output.FormatDuration(row.MaxDelay),
+		formatScheduleTime(row.LastRunAt),
+	}
+}
+
+var scheduleHeader = []string{"Workflow", "Schedule", "State", "Expected", "Ran", "Missed", "Pending", "Delay p50", "Delay p90", "Max Delay", "Last Run"}
+
+func renderColoredSchedules(w io.Writer, report metrics.ScheduleReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "⏰ Scheduled Workflows")
+	fmt.Fprintln(w)
+
+	if len(report.Workflows) == 0 {
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No workflows with schedule triggers found")
+		return
+	}
+
+	overviewColor := color.New(color.FgHiBlack)
+	overviewColor.Fprintln(w, schedulesOverview(report))
+	fmt.Fprintln(w)
+
+	headerColors := make([]tablewriter.Colors, len(scheduleHeader))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(scheduleHeader)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+	)
+	for _, row := range report.Workflows {
+		cells := scheduleCells(row, strings.Join(row.Schedules, ", "))
+		cells[2] = formatScheduleState(row.State)
+		table.Append(cells)
+	}
+	table.Render()
+
+	if len(report.Missed) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🕳️  Missed Executions")
+
+	header := []string{"Workflow", "Schedule", "Expected At"}
+	missedColors := make([]tablewriter.Colors, len(header))
+	for i := range missedColors {
+		missedColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	missed := tablewriter.NewWriter(w)
+	missed.SetHeader(header)
+	missed.SetBorder(true)
+	missed.SetHeaderColor(missedColors...)
+	missed.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+	)
+	for _, m := range report.Missed {
+		missed.Append([]string{m.Workflow, m.Schedule, formatScheduleTime(m.ExpectedAt)})
+	}
+	missed.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownSchedules(w io.Writer, report metrics.ScheduleReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Scheduled Workflows")
+	fmt.Fprintln(w)
+
+	if len(report.Workflows) == 0 {
+		fmt.Fprintln(w, "_No workflows with schedule triggers found._")
+		return
+	}
+
+	fmt.Fprintln(w, schedulesOverview(report))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| "+strings.Join(scheduleHeader, " | ")+" |")
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |")
+	for _, row := range report.Workflows {
+		schedules := make([]string, len(row.Schedules))
+		for i, s := range row.Schedules {
+			schedules[i] = "`" + s + "`"
+		}
+		cells := scheduleCells(row, strings.Join(schedules, ", "))
+		for i := range cells {
+			cells[i] = escapeMarkdownCell(cells[i])
+		}
+		fmt.Fprintln(w, "| "+strings.Join(cells, " | ")+" |")
+	}
+
+	if len(report.Missed) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Missed Executions")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Workflow | Schedule | Expected At |")
+	fmt.Fprintln(w, "| --- | --- | --- |")
+	for _, m := range report.Missed {
+		fmt.Fprintf(w, "| %s | `%s` | %s |\n", escapeMarkdownCell(m.Workflow), escapeMarkdownCell(m.Schedule), formatScheduleTime(m.ExpectedAt))
+	}
+	fmt.Fprintln(w)
+}
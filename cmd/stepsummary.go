@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+)
+
+const (
+	envStepSummary = "GITHUB_STEP_SUMMARY"
+	envOutput      = "GITHUB_OUTPUT"
+
+	// trendThreshold is the relative duration change below which a workflow is
+	// considered stable.
+	trendThreshold = 0.05
+)
+
+// appendToEnvFile opens the file referenced by the given GitHub Actions
+// environment variable for appending.
+func appendToEnvFile(env string) (*os.File, error) {
+	path := os.Getenv(env)
+	if path == "" {
+		return nil, fmt.Errorf("$%s is not set; is this running inside GitHub Actions?", env)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open $%s: %w", env, err)
+	}
+	return file, nil
+}
+
+func writeStepSummary(rows []metrics.SummaryRow, repository string, from, to time.Time) error {
+	file, err := appendToEnvFile(envStepSummary)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	renderStepSummary(file, rows, repository, from, to)
+	return nil
+}
+
+func writeStepOutputs(overall metrics.SummaryRow) error {
+	file, err := appendToEnvFile(envOutput)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	renderStepOutputs(file, overall)
+	return nil
+}
+
+// renderStepOutputs writes step outputs in the `name=value` format expected by
+// $GITHUB_OUTPUT. Durations are reported in whole seconds so they can be
+// compared numerically in workflow expressions.
+func renderStepOutputs(w io.Writer, overall metrics.SummaryRow) {
+	fmt.Fprintf(w, "failure_rate=%.4f\n", overall.FailureRate)
+	fmt.Fprintf(w, "p95_duration=%d\n", int64(overall.DurationPercentiles.P95.Seconds()))
+}
+
+func renderStepSummary(w io.Writer, rows []metrics.SummaryRow, repository string, from, to time.Time) {
+	fmt.Fprintf(w, "## 📊 Workflow Execution Summary for %s\n\n", repository)
+	fmt.Fprintf(w, "_%s → %s_\n\n", from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "_No workflow runs found in the specified time range._")
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintln(w, "| Workflow | Runs | Failed | Failure Rate | Avg Duration | P95 Duration | Trend |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | :---: |")
+	for _, row := range rows {
+		fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(row.Workflow),
+			row.Runs,
+			row.Failed,
+			output.FormatFailureRate(row.FailureRate),
+			output.FormatDuration(row.AvgDuration),
+			output.FormatDuration(row.DurationPercentiles.P95),
+			formatTrend(row.Trend),
+		)
+	}
+	fmt.Fprintln(w)
+
+	if alerts := collectAlerts(rows); len(alerts) > 0 {
+		renderMarkdownAlerts(w, alerts, "### 🚨")
+	}
+
+	for _, row := range rows {
+		if len(row.Jobs) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "<details>\n<summary>Jobs for %s (%d)</summary>\n\n", html.EscapeString(row.Workflow), len(row.Jobs))
+		fmt.Fprintln(w, "| Job | Runs | Failed | Failure Rate | Avg Duration | P95 Duration | Trend |")
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | :---: |")
+		for _, job := range row.Jobs {
+			fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(job.Job),
+				job.Runs,
+				job.Failed,
+				output.FormatFailureRate(job.FailureRate),
+				output.FormatDuration(job.AvgDuration),
+				output.FormatDuration(job.DurationPercentiles.P95),
+				formatTrend(job.Trend),
+			)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "</details>")
+		fmt.Fprintln(w)
+	}
+}
+
+// formatTrend renders the duration trend as an arrow followed by the relative
+// change. Slower runs point up, faster runs point down.
+func formatTrend(trend metrics.Trend) string {
+	change := trend.DurationChange
+	switch {
+	case change == 0:
+		return "-"
+	case math.Abs(change) < trendThreshold:
+		return fmt.Sprintf("→ %+.1f%%", change*100)
+	case change > 0:
+		return fmt.Sprintf("↑ %+.1f%%", change*100)
+	default:
+		return fmt.Sprintf("↓ %+.1f%%", change*100)
+	}
+}
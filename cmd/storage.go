@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/briandowns/spinner"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+func newStorageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage <owner>/<repo>",
+		Short: "Report artifact and Actions cache storage usage",
+		Long: heredoc.Doc(`
+			List the artifacts and Actions cache entries currently stored for a repository with their
+			size, age and expiry, and aggregate artifacts by the workflow that produced them and cache
+			entries by key prefix (the key without trailing hashes and numbers).
+
+			Storage is reported as it is now, so the time range and workflow filters do not apply.
+			Cache entries are evicted after 7 days without access; that date is shown as their expiry.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			top, err := getTop(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			terminal := term.FromEnv()
+			var s *spinner.Spinner
+			if terminal.IsTerminalOutput() {
+				s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+				s.Suffix = fmt.Sprintf(" Fetching artifacts and caches for %s/%s...", owner, repo)
+				s.Start()
+			}
+
+			artifacts, caches, runWorkflows, err := collectStorage(ctx, client, owner, repo)
+			if s != nil {
+				s.Stop()
+			}
+			if err != nil {
+				return err
+			}
+
+			report := metrics.Storage(artifacts, runWorkflows, caches, time.Now().UTC())
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if top > 0 {
+				if len(report.ArtifactRows) > top {
+					report.ArtifactRows = report.ArtifactRows[:top]
+				}
+				if len(report.CacheRows) > top {
+					report.CacheRows = report.CacheRows[:top]
+				}
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownStorage(stdout, report)
+				return nil
+			}
+
+			renderColoredStorage(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagTop, 20, "Show only the N largest artifacts and cache entries (0 shows all; JSON always includes all)")
+
+	return cmd
+}
+
+// collectStorage lists the artifacts and cache entries of a repository and
+// resolves the workflow that produced each unexpired artifact. Runs are
+// matched in bulk against the repository's runs since the oldest artifact;
+// only runs missing from that listing, such as older runs re-run later, are
+// fetched one by one. Runs that cannot be fetched, typically because they
+// were deleted, stay unresolved.
+func collectStorage(ctx context.Context, client *githubapi.Client, owner, repo string) ([]githubapi.Artifact, []githubapi.ActionsCache, map[int64]string, error) {
+	artifacts, err := client.ListArtifacts(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list artifacts for %s/%s: %w", owner, repo, err)
+	}
+	caches, err := client.ListActionsCaches(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list caches for %s/%s: %w", owner, repo, err)
+	}
+
+	runIDs := make(map[int64]struct{})
+	var oldest time.Time
+	for _, artifact := range artifacts {
+		if !artifact.Expired && artifact.RunID != 0 {
+			runIDs[artifact.RunID] = struct{}{}
+			if oldest.IsZero() || artifact.CreatedAt.Before(oldest) {
+				oldest = artifact.CreatedAt
+			}
+		}
+	}
+	if len(runIDs) == 0 {
+		return artifacts, caches, map[int64]string{}, nil
+	}
+
+	workflows, err := client.ListWorkflows(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list workflows for %s/%s: %w", owner, repo, err)
+	}
+	workflowNames := make(map[int64]string, len(workflows))
+	for _, wf := range workflows {
+		workflowNames[wf.ID] = wf.Name
+	}
+	// The run name can be customized with run-name, so prefer the name of
+	// the workflow itself.
+	workflowName := func(run githubapi.WorkflowRun) string {
+		if name, ok := workflowNames[run.WorkflowID]; ok {
+			return name
+		}
+		return run.Name
+	}
+
+	runWorkflows := make(map[int64]string, len(runIDs))
+	// A run is created before its artifacts, so start a day early.
+	filter := githubapi.WorkflowRunFilter{Created: ">=" + oldest.AddDate(0, 0, -1).Format("2006-01-02")}
+	runs, err := client.ListRepositoryRuns(ctx, owner, repo, filter, 0)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list workflow runs for %s/%s: %w", owner, repo, err)
+	}
+	for _, run := range runs {
+		if _, ok := runIDs[run.ID]; ok {
+			runWorkflows[run.ID] = workflowName(run)
+			delete(runIDs, run.ID)
+		}
+	}
+
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var mu sync.Mutex
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for id := range runIDs {
+		runID := id
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			run, err := client.GetWorkflowRun(gctx, owner, repo, runID)
+			if err != nil {
+				slog.Debug("failed to resolve artifact run", slog.Int64("run", runID), slog.String("error", err.Error()))
+				return nil
+			}
+			mu.Lock()
+			runWorkflows[runID] = workflowName(run)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, nil, err
+	}
+	return artifacts, caches, runWorkflows, nil
+}
+
+func formatStorageDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+func storageOverview(report metrics.StorageReport) string {
+	return fmt.Sprintf("%d artifacts (%s) · %d expired · %d cache entries (%s)",
+		report.Artifacts, output.FormatBytes(report.ArtifactBytes), report.ExpiredArtifacts,
+		report.Caches, output.FormatBytes(report.CacheBytes))
+}
+
+func storageGroupCells(group metrics.StorageGroup) []string {
+	return []string{
+		group.Name,
+		fmt.Sprintf("%d", group.Count),
+		output.FormatBytes(group.Bytes),
+		output.FormatFailureRate(group.Share),
+		formatStorageDate(group.OldestAt),
+		formatStorageDate(group.NextExpiry),
+	}
+}
+
+func newStorageTable(w io.Writer, header []string, columnColors ...tablewriter.Colors) *tablewriter.Table {
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetAutoWrapText(false)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(columnColors...)
+	return table
+}
+
+func renderColoredStorage(w io.Writer, report metrics.StorageReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "🗄️  Storage Usage")
+	fmt.Fprintln(w)
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprintln(w, storageOverview(report))
+
+	if report.Artifacts == 0 && report.Caches == 0 {
+		fmt.Fprintln(w)
+		warningColor := color.New(color.FgYellow)
+		warningColor.Fprintln(w, "⚠️  No artifacts or cache entries are stored")
+		return
+	}
+
+	groupColors := []tablewriter.Colors{
+		{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		{tablewriter.FgGreenColor},
+		{tablewriter.FgMagentaColor},
+		{tablewriter.FgYellowColor},
+		{tablewriter.FgBlueColor},
+		{tablewriter.FgBlueColor},
+	}
+
+	if len(report.ByWorkflow) > 0 {
+		fmt.Fprintln(w)
+		titleColor.Fprintln(w, "📦 Artifacts by Workflow")
+		table := newStorageTable(w, []string{"Workflow", "Artifacts", "Size", "Share", "Oldest", "Next Expiry"}, groupColors...)
+		for _, group := range report.ByWorkflow {
+			table.Append(storageGroupCells(group))
+		}
+		table.Render()
+
+		fmt.Fprintln(w)
+		titleColor.Fprintln(w, "📦 Largest Artifacts")
+		table = newStorageTable(w, []string{"Artifact", "Workflow", "Branch", "Size", "Age", "Expires"},
+			tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgHiBlackColor},
+			tablewriter.Colors{tablewriter.FgMagentaColor},
+			tablewriter.Colors{tablewriter.FgYellowColor},
+			tablewriter.Colors{tablewriter.FgBlueColor},
+		)
+		for _, row := range report.ArtifactRows {
+			table.Append([]string{
+				escapeMarkdownCell(row.Name),
+				escapeMarkdownCell(row.Workflow),
+				escapeMarkdownCell(row.Branch),
+				output.FormatBytes(row.Bytes),
+				output.FormatAge(row.Age),
+				formatStorageDate(row.ExpiresAt),
+			})
+		}
+		table.Render()
+	}
+
+	if len(report.ByKeyPrefix) > 0 {
+		fmt.Fprintln(w)
+		titleColor.Fprintln(w, "🧊 Caches by Key Prefix")
+		table := newStorageTable(w, []string{"Key Prefix", "Entries", "Size", "Share", "Oldest", "Next Eviction"}, groupColors...)
+		for _, group := range report.ByKeyPrefix {
+			table.Append(storageGroupCells(group))
+		}
+		table.Render()
+
+		fmt.Fprintln(w)
+		titleColor.Fprintln(w, "🧊 Largest Cache Entries")
+		table = newStorageTable(w, []string{"Key", "Ref", "Size", "Age", "Last Used", "Evicted"},
+			tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+			tablewriter.Colors{tablewriter.FgHiBlackColor},
+			tablewriter.Colors{tablewriter.FgMagentaColor},
+			tablewriter.Colors{tablewriter.FgYellowColor},
+			tablewriter.Colors{tablewriter.FgBlueColor},
+			tablewriter.Colors{tablewriter.FgBlueColor},
+		)
+		for _, row := range report.CacheRows {
+			table.Append([]string{
+				escapeMarkdownCell(row.Key),
+				escapeMarkdownCell(row.Ref),
+				output.FormatBytes(row.Bytes),
+				output.FormatAge(row.Age),
+				formatStorageDate(row.LastAccessedAt),
+				formatStorageDate(row.ExpiresAt),
+			})
+		}
+		table.Render()
+	}
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownStorage(w io.Writer, report metrics.StorageReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Storage Usage")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s\n\n", storageOverview(report))
+
+	if report.Artifacts == 0 && report.Caches == 0 {
+		fmt.Fprintln(w, "_No artifacts or cache entries are stored._")
+		return
+	}
+
+	writeGroups := func(title, nameHeader, countHeader, expiryHeader string, groups []metrics.StorageGroup) {
+		fmt.Fprintf(w, "## %s\n\n", title)
+		fmt.Fprintf(w, "| %s | %s | Size | Share | Oldest | %s |\n", nameHeader, countHeader, expiryHeader)
+		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | --- | --- |")
+		for _, group := range groups {
+			cells := storageGroupCells(group)
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n", escapeMarkdownCell(cells[0]), cells[1], cells[2], cells[3], cells[4], cells[5])
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.ByWorkflow) > 0 {
+		writeGroups("Artifacts by Workflow", "Workflow", "Artifacts", "Next Expiry", report.ByWorkflow)
+
+		fmt.Fprintln(w, "## Largest Artifacts")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Artifact | Workflow | Branch | Size | Age | Expires |")
+		fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: | --- |")
+		for _, row := range report.ArtifactRows {
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(row.Name),
+				escapeMarkdownCell(row.Workflow),
+				escapeMarkdownCell(row.Branch),
+				output.FormatBytes(row.Bytes),
+				output.FormatAge(row.Age),
+				formatStorageDate(row.ExpiresAt),
+			)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.ByKeyPrefix) > 0 {
+		writeGroups("Caches by Key Prefix", "Key Prefix", "Entries", "Next Eviction", report.ByKeyPrefix)
+
+		fmt.Fprintln(w, "## Largest Cache Entries")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Key | Ref | Size | Age | Last Used | Evicted |")
+		fmt.Fprintln(w, "| --- | --- | ---: | ---: | --- | --- |")
+		for _, row := range report.CacheRows {
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s | %s |\n",
+				escapeMarkdownCell(row.Key),
+				escapeMarkdownCell(row.Ref),
+				output.FormatBytes(row.Bytes),
+				output.FormatAge(row.Age),
+				formatStorageDate(row.LastAccessedAt),
+				formatStorageDate(row.ExpiresAt),
+			)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -4,30 +4,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/JohnTitor/gh-actrics/internal/githubapi"
 	"github.com/JohnTitor/gh-actrics/internal/metrics"
 	"github.com/JohnTitor/gh-actrics/internal/output"
 	"github.com/JohnTitor/gh-actrics/internal/util"
-	"github.com/briandowns/spinner"
 	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 const (
-	flagSummaryRuns = "runs"
+	flagSummaryRuns        = "runs"
+	flagSummaryStepSummary = "step-summary"
+	flagSummaryStepOutputs = "step-outputs"
+	flagAlertThreshold     = "alert-threshold"
+	flagAlertRuns          = "alert-runs"
+	flagSummaryTrend       = "trend"
+
+	// trendWidth is the widest a duration sparkline is drawn.
+	trendWidth = 30
+	// failureBarWidth is the width of the failure rate bar.
+	failureBarWidth = 10
 )
 
 func newSummaryCmd() *cobra.Command {
@@ -43,150 +49,96 @@ func newSummaryCmd() *cobra.Command {
 				return err
 			}
 
-			now := time.Now().UTC()
-			from, to, err := resolveTimeRange(now, viper.GetString(flagFrom), viper.GetString(flagTo), viper.GetString(flagLast))
+			runLimit, err := getRunLimit(cmd)
 			if err != nil {
 				return err
 			}
 
-			cacheTTL := viper.GetDuration(flagCacheTTL)
-			enableCache := cacheTTL > 0 && !viper.GetBool(flagNoCache)
-
-			client, err := githubapi.NewClient(githubapi.Options{CacheTTL: cacheTTL, EnableCache: enableCache})
+			policy, err := failurePolicyFromFlags()
 			if err != nil {
-				return fmt.Errorf("failed to create API client: %w", err)
+				return err
 			}
 
-			allWorkflows, err := client.ListWorkflows(ctx, owner, repo)
+			client, err := newAPIClient()
 			if err != nil {
-				return fmt.Errorf("failed to list workflows for %s/%s: %w", owner, repo, err)
-			}
-
-			// Filter out GitHub-hosted workflows
-			workflows := make([]githubapi.Workflow, 0, len(allWorkflows))
-			for _, wf := range allWorkflows {
-				if strings.HasPrefix(wf.Path, "dynamic") {
-					continue
-				}
-				workflows = append(workflows, wf)
+				return err
 			}
 
-			selected, err := filterWorkflows(workflows, mustGetStringSlice(flagWorkflow))
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
 			if err != nil {
 				return err
 			}
-			if len(selected) == 0 {
-				fmt.Fprintf(stderr, "No workflows in %s/%s matched the current selection.\n", owner, repo)
+			if collection == nil {
 				return nil
 			}
+			records, from, to := collection.records, collection.from, collection.to
 
-			createdFilter := fmt.Sprintf("%s..%s", from.Format(time.RFC3339), to.Format(time.RFC3339))
-			runFilter := githubapi.WorkflowRunFilter{
-				Branch:  viper.GetString(flagBranch),
-				Status:  viper.GetString(flagStatus),
-				Created: createdFilter,
-			}
+			summary := metrics.AggregateWithPolicy(records, from, to, policy)
 
-			runLimit, err := cmd.Flags().GetInt(flagSummaryRuns)
+			files, err := loadWorkflowFiles(ctx, cmd, client, collection)
 			if err != nil {
 				return err
 			}
-			if runLimit < 0 {
-				return fmt.Errorf("--%s must be greater than or equal to 0", flagSummaryRuns)
+			metrics.AttachJobConfig(summary, files)
+
+			matrixAxis, err := cmd.Flags().GetString(flagMatrixAxis)
+			if err != nil {
+				return err
 			}
-			if runLimit > 0 {
-				runFilter.Created = ""
+			if matrixAxis != "" {
+				valueOf, label, err := matrixAxisFunc(matrixAxis, files)
+				if err != nil {
+					return err
+				}
+				metrics.AttachMatrixAxis(summary, metrics.AggregateMatrixAxis(records, from, to, label, valueOf, policy))
 			}
 
-			threads := viper.GetInt(flagThreads)
-			if threads <= 0 {
-				threads = 1
+			trend, err := cmd.Flags().GetBool(flagSummaryTrend)
+			if err != nil {
+				return err
 			}
-
-			var (
-				mu      sync.Mutex
-				records []metrics.RunRecord
-			)
-
-			// Start spinner for fetching workflow runs
-			terminal := term.FromEnv()
-			var s *spinner.Spinner
-			if terminal.IsTerminalOutput() {
-				s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-				s.Suffix = fmt.Sprintf(" Fetching workflow runs for %d workflows...", len(selected))
-				s.Start()
+			if trend {
+				metrics.AttachDailyTrends(summary, records, from, to, policy)
+
+				// Commits are listed from the default branch, so only its runs
+				// are known to include each edit.
+				repository, err := client.GetRepository(ctx, owner, repo)
+				if err != nil {
+					return fmt.Errorf("failed to look up default branch of %s/%s: %w", owner, repo, err)
+				}
+				changes, err := fetchWorkflowChanges(ctx, client, collection)
+				if err != nil {
+					return err
+				}
+				metrics.AttachWorkflowChanges(summary, records, changes, repository.DefaultBranch, from, to, policy)
 			}
 
-			sem := semaphore.NewWeighted(int64(threads))
-			g, gctx := errgroup.WithContext(ctx)
-
-			for _, wf := range selected {
-				workflow := wf
-				g.Go(func() error {
-					if err := sem.Acquire(gctx, 1); err != nil {
-						return err
-					}
-					defer sem.Release(1)
-
-					runs, err := client.ListWorkflowRuns(gctx, owner, repo, workflow.ID, runFilter, runLimit)
-					if err != nil {
-						return fmt.Errorf("workflow %s: %w", workflow.Name, err)
-					}
-
-					for _, run := range runs {
-						jobs, jobErr := client.ListJobs(gctx, owner, repo, run.ID)
-						if jobErr != nil {
-							slog.Warn("failed to fetch jobs", slog.String("workflow", workflow.Name), slog.Int64("run", run.ID), slog.String("error", jobErr.Error()))
-						}
-						mu.Lock()
-						records = append(records, metrics.RunRecord{Workflow: workflow, Run: run, Jobs: jobs})
-						mu.Unlock()
-					}
-					return nil
-				})
+			anomalyOpts, err := anomalyOptionsFromFlags(cmd)
+			if err != nil {
+				return err
 			}
+			anomalyOpts.Failure = policy
+			metrics.AttachAlerts(summary, metrics.DetectAnomalies(records, from, to, anomalyOpts))
 
-			err = g.Wait()
-			if s != nil {
-				s.Stop()
-			}
+			stepSummary, err := cmd.Flags().GetBool(flagSummaryStepSummary)
 			if err != nil {
 				return err
 			}
-
-			if runLimit > 0 && len(records) > 0 {
-				var earliest, latest time.Time
-				for _, rec := range records {
-					runTime := rec.Run.RunStartedAt
-					if runTime.IsZero() {
-						runTime = rec.Run.CreatedAt
-					}
-					if runTime.IsZero() {
-						continue
-					}
-					if earliest.IsZero() || runTime.Before(earliest) {
-						earliest = runTime
-					}
-					if latest.IsZero() || runTime.After(latest) {
-						latest = runTime
-					}
-				}
-				if !earliest.IsZero() {
-					from = earliest
-				} else {
-					from = time.Time{}
-				}
-				if !latest.IsZero() {
-					to = latest
-				} else {
-					to = time.Now().UTC()
-				}
-				if to.Before(from) {
-					to = from
+			if stepSummary {
+				if err := writeStepSummary(summary, fmt.Sprintf("%s/%s", owner, repo), from, to); err != nil {
+					return err
 				}
 			}
 
-			summary := metrics.Aggregate(records, from, to)
+			stepOutputs, err := cmd.Flags().GetBool(flagSummaryStepOutputs)
+			if err != nil {
+				return err
+			}
+			if stepOutputs {
+				if err := writeStepOutputs(metrics.Overall(records, from, to, policy)); err != nil {
+					return err
+				}
+			}
 
 			if viper.GetBool(flagJSON) {
 				encoder := json.NewEncoder(stdout)
@@ -212,11 +164,57 @@ func newSummaryCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().Int(flagSummaryRuns, 0, "Fetch only the most recent N runs per workflow (overrides time range filters)")
+	addRunLimitFlag(cmd)
+	cmd.Flags().Bool(flagSummaryStepSummary, false, "Append a Markdown report to $GITHUB_STEP_SUMMARY")
+	cmd.Flags().Bool(flagSummaryStepOutputs, false, "Write failure_rate and p95_duration step outputs to $GITHUB_OUTPUT")
+	cmd.Flags().Float64(flagAlertThreshold, metrics.DefaultAnomalyOptions().Threshold, "Robust z-score at which a duration shift is flagged as an alert (0 disables alerts)")
+	cmd.Flags().Int(flagAlertRuns, metrics.DefaultAnomalyOptions().RecentRuns, "Number of most recent runs compared against earlier runs for alerts")
+	cmd.Flags().Bool(flagSummaryTrend, false, "Add a daily duration sparkline and a failure bar to the workflow and job tables, and compare runs before and after each workflow file edit")
+	cmd.Flags().String(flagMatrixAxis, "", "Aggregate matrix jobs by one matrix axis, given by name (needs workflow files) or 1-based position")
+	addWorkflowFileFlags(cmd)
 
 	return cmd
 }
 
+func anomalyOptionsFromFlags(cmd *cobra.Command) (metrics.AnomalyOptions, error) {
+	opts := metrics.DefaultAnomalyOptions()
+
+	threshold, err := cmd.Flags().GetFloat64(flagAlertThreshold)
+	if err != nil {
+		return opts, err
+	}
+	if threshold < 0 {
+		return opts, fmt.Errorf("--%s must be greater than or equal to 0", flagAlertThreshold)
+	}
+	opts.Threshold = threshold
+
+	recentRuns, err := cmd.Flags().GetInt(flagAlertRuns)
+	if err != nil {
+		return opts, err
+	}
+	if recentRuns <= 0 {
+		return opts, fmt.Errorf("--%s must be greater than 0", flagAlertRuns)
+	}
+	opts.RecentRuns = recentRuns
+
+	return opts, nil
+}
+
+func collectAlerts(rows []metrics.SummaryRow) []metrics.Alert {
+	var alerts []metrics.Alert
+	for _, row := range rows {
+		alerts = append(alerts, row.Alerts...)
+	}
+	return alerts
+}
+
+func alertTarget(alert metrics.Alert) string {
+	if alert.Job != "" {
+		return fmt.Sprintf("%s › %s", alert.Workflow, alert.Job)
+	}
+	return alert.Workflow
+}
+
 func filterWorkflows(workflows []githubapi.Workflow, selectors []string) ([]githubapi.Workflow, error) {
 	if len(workflows) == 0 {
 		return nil, fmt.Errorf("repository has no workflows")
@@ -283,28 +281,32 @@ func renderColoredSummary(w io.Writer, rows []metrics.SummaryRow, colorEnabled b
 		return
 	}
 
+	withTrend := trendKnown(rows)
+	header := []string{"Workflow", "Runs", "Failed", "Failure Rate", "Avg Duration", "Total Duration", "Top Runners"}
+	columnColors := []tablewriter.Colors{
+		{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		{tablewriter.FgGreenColor},
+		{tablewriter.FgRedColor},
+		{tablewriter.FgYellowColor},
+		{tablewriter.FgBlueColor},
+		{tablewriter.FgMagentaColor},
+		{tablewriter.FgHiBlackColor},
+	}
+	if withTrend {
+		header = append(header, "Duration Trend", "Failures")
+		columnColors = append(columnColors, tablewriter.Colors{tablewriter.FgBlueColor}, tablewriter.Colors{})
+	}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
 	// Create table
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"Workflow", "Runs", "Failed", "Failure Rate", "Avg Duration", "Total Duration", "Top Runners"})
+	table.SetHeader(header)
 	table.SetBorder(true)
-	table.SetHeaderColor(
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-	)
-	table.SetColumnColor(
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
-		tablewriter.Colors{tablewriter.FgGreenColor},
-		tablewriter.Colors{tablewriter.FgRedColor},
-		tablewriter.Colors{tablewriter.FgYellowColor},
-		tablewriter.Colors{tablewriter.FgBlueColor},
-		tablewriter.Colors{tablewriter.FgMagentaColor},
-		tablewriter.Colors{tablewriter.FgHiBlackColor},
-	)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(columnColors...)
 
 	for _, row := range rows {
 		failureRate := fmt.Sprintf("%.1f%%", row.FailureRate*100)
@@ -312,7 +314,7 @@ func renderColoredSummary(w io.Writer, rows []metrics.SummaryRow, colorEnabled b
 		totalDuration := output.FormatDuration(row.TotalDuration)
 		topRunners := output.FormatRunnerSummary(row.RunnerSummary, 2)
 
-		table.Append([]string{
+		cells := []string{
 			row.Workflow,
 			fmt.Sprintf("%d", row.Runs),
 			fmt.Sprintf("%d", row.Failed),
@@ -320,12 +322,36 @@ func renderColoredSummary(w io.Writer, rows []metrics.SummaryRow, colorEnabled b
 			avgDuration,
 			totalDuration,
 			topRunners,
-		})
+		}
+		if withTrend {
+			cells = append(cells, formatDurationTrend(row.Daily), formatFailureBar(row.FailureRate))
+		}
+		table.Append(cells)
 	}
 
 	table.Render()
 	fmt.Fprintln(w)
 
+	if conclusionsKnown(rows) {
+		renderColoredConclusions(w, rows)
+	}
+
+	if alerts := collectAlerts(rows); len(alerts) > 0 {
+		alertTitle := color.New(color.FgRed, color.Bold)
+		alertTitle.Fprintln(w, "🚨 Alerts")
+		alertColor := color.New(color.FgYellow)
+		for _, alert := range alerts {
+			fmt.Fprintf(w, "  • %s: ", alertTarget(alert))
+			alertColor.Fprintf(w, "%s", output.FormatAlertChange(alert))
+			fmt.Fprintf(w, " since %s\n", alert.Since.Format(time.RFC3339))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if hasWorkflowChanges(rows) {
+		renderColoredWorkflowChanges(w, rows)
+	}
+
 	for _, row := range rows {
 		if len(row.Jobs) == 0 {
 			continue
@@ -334,35 +360,43 @@ func renderColoredSummary(w io.Writer, rows []metrics.SummaryRow, colorEnabled b
 		jobTitle := color.New(color.FgHiWhite, color.Bold)
 		jobTitle.Fprintf(w, "🔧 Jobs for %s\n", row.Workflow)
 
+		withConfig := jobConfigKnown(row)
+		header := []string{"Job", "Runs", "Failed", "Failure Rate", "Avg Duration", "Total Duration", "Top Runners"}
+		columnColors := []tablewriter.Colors{
+			{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+			{tablewriter.FgGreenColor},
+			{tablewriter.FgRedColor},
+			{tablewriter.FgYellowColor},
+			{tablewriter.FgBlueColor},
+			{tablewriter.FgMagentaColor},
+			{tablewriter.FgHiBlackColor},
+		}
+		if withConfig {
+			header = append(header, "p99 / Timeout", "Declared Runs-On")
+			columnColors = append(columnColors, tablewriter.Colors{tablewriter.FgYellowColor}, tablewriter.Colors{tablewriter.FgHiBlackColor})
+		}
+		if withTrend {
+			header = append(header, "Duration Trend", "Failures")
+			columnColors = append(columnColors, tablewriter.Colors{tablewriter.FgBlueColor}, tablewriter.Colors{})
+		}
+		headerColors := make([]tablewriter.Colors, len(header))
+		for i := range headerColors {
+			headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+		}
+
 		jobTable := tablewriter.NewWriter(w)
-		jobTable.SetHeader([]string{"Job", "Runs", "Failed", "Failure Rate", "Avg Duration", "Total Duration", "Top Runners"})
+		jobTable.SetHeader(header)
 		jobTable.SetBorder(true)
-		jobTable.SetHeaderColor(
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor},
-		)
-		jobTable.SetColumnColor(
-			tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
-			tablewriter.Colors{tablewriter.FgGreenColor},
-			tablewriter.Colors{tablewriter.FgRedColor},
-			tablewriter.Colors{tablewriter.FgYellowColor},
-			tablewriter.Colors{tablewriter.FgBlueColor},
-			tablewriter.Colors{tablewriter.FgMagentaColor},
-			tablewriter.Colors{tablewriter.FgHiBlackColor},
-		)
+		jobTable.SetHeaderColor(headerColors...)
+		jobTable.SetColumnColor(columnColors...)
 
-		for _, job := range row.Jobs {
+		for _, job := range expandMatrix(row.Jobs) {
 			jobFailureRate := fmt.Sprintf("%.1f%%", job.FailureRate*100)
 			jobAvgDuration := output.FormatDuration(job.AvgDuration)
 			jobTotalDuration := output.FormatDuration(job.TotalDuration)
 			jobTopRunners := output.FormatRunnerSummary(job.RunnerSummary, 2)
 
-			jobTable.Append([]string{
+			cells := []string{
 				job.Job,
 				fmt.Sprintf("%d", job.Runs),
 				fmt.Sprintf("%d", job.Failed),
@@ -370,12 +404,29 @@ func renderColoredSummary(w io.Writer, rows []metrics.SummaryRow, colorEnabled b
 				jobAvgDuration,
 				jobTotalDuration,
 				jobTopRunners,
-			})
+			}
+			if withConfig {
+				cells = append(cells, formatTimeoutUsage(job), formatDeclaredRunsOn(job))
+			}
+			if withTrend {
+				cells = append(cells, formatDurationTrend(job.Daily), formatFailureBar(job.FailureRate))
+			}
+			jobTable.Append(cells)
 		}
 
 		jobTable.Render()
 		fmt.Fprintln(w)
 	}
+
+	if axisRows := collectMatrixAxis(rows); len(axisRows) > 0 {
+		renderColoredMatrixAxis(w, axisRows)
+	}
+}
+
+// escapeMarkdownCell escapes pipes so that a name containing one does not
+// split a Markdown table cell.
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
 }
 
 func renderMarkdownSummary(w io.Writer, rows []metrics.SummaryRow) {
@@ -397,41 +448,316 @@ func renderMarkdownSummary(w io.Writer, rows []metrics.SummaryRow) {
 		topRunners := output.FormatRunnerSummary(row.RunnerSummary, len(row.RunnerSummary))
 
 		fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %s |\n",
-			row.Workflow,
+			escapeMarkdownCell(row.Workflow),
 			row.Runs,
 			row.Failed,
 			failureRate,
 			avgDuration,
 			totalDuration,
-			topRunners,
+			escapeMarkdownCell(topRunners),
 		)
 	}
 	fmt.Fprintln(w)
 
+	if conclusionsKnown(rows) {
+		renderMarkdownConclusions(w, rows)
+	}
+
+	if alerts := collectAlerts(rows); len(alerts) > 0 {
+		renderMarkdownAlerts(w, alerts, "##")
+	}
+
+	if hasWorkflowChanges(rows) {
+		renderMarkdownWorkflowChanges(w, rows)
+	}
+
 	for _, row := range rows {
 		if len(row.Jobs) == 0 {
 			continue
 		}
 
+		withConfig := jobConfigKnown(row)
 		fmt.Fprintf(w, "## Jobs for %s\n\n", row.Workflow)
-		fmt.Fprintln(w, "| Job | Runs | Failed | Failure Rate | Avg Duration | Total Duration | Top Runners |")
-		fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | --- |")
-		for _, job := range row.Jobs {
+		if withConfig {
+			fmt.Fprintln(w, "| Job | Runs | Failed | Failure Rate | Avg Duration | Total Duration | Top Runners | p99 / Timeout | Declared Runs-On |")
+			fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | --- | ---: | --- |")
+		} else {
+			fmt.Fprintln(w, "| Job | Runs | Failed | Failure Rate | Avg Duration | Total Duration | Top Runners |")
+			fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | --- |")
+		}
+		for _, job := range expandMatrix(row.Jobs) {
 			failureRate := output.FormatFailureRate(job.FailureRate)
 			avgDuration := output.FormatDuration(job.AvgDuration)
 			totalDuration := output.FormatDuration(job.TotalDuration)
 			topRunners := output.FormatRunnerSummary(job.RunnerSummary, len(job.RunnerSummary))
 
-			fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %s |\n",
-				job.Job,
+			fmt.Fprintf(w, "| %s | %d | %d | %s | %s | %s | %s |",
+				escapeMarkdownCell(job.Job),
 				job.Runs,
 				job.Failed,
 				failureRate,
 				avgDuration,
 				totalDuration,
-				topRunners,
+				escapeMarkdownCell(topRunners),
 			)
+			if withConfig {
+				fmt.Fprintf(w, " %s | %s |", formatTimeoutUsage(job), escapeMarkdownCell(formatDeclaredRunsOn(job)))
+			}
+			fmt.Fprintln(w)
 		}
 		fmt.Fprintln(w)
 	}
+
+	if axisRows := collectMatrixAxis(rows); len(axisRows) > 0 {
+		renderMarkdownMatrixAxis(w, axisRows)
+	}
+}
+
+// conclusionColumns are the conclusions broken out in the conclusions table;
+// everything else is counted under "Other".
+var conclusionColumns = []string{"success", "failure", "cancelled", "skipped", "timed_out"}
+
+// conclusionCounts splits the conclusion histogram of a workflow into the
+// columns of the conclusions table: success, failure, cancelled (excluding
+// superseded runs), superseded, skipped, timed out and other.
+func conclusionCounts(row metrics.SummaryRow) []int {
+	counts := make([]int, 0, len(conclusionColumns)+2)
+	other := 0
+	for _, n := range row.Conclusions {
+		other += n
+	}
+	for _, c := range conclusionColumns {
+		n := row.Conclusions[c]
+		other -= n
+		if c == "cancelled" {
+			counts = append(counts, n-row.Superseded, row.Superseded)
+			continue
+		}
+		counts = append(counts, n)
+	}
+	return append(counts, other)
+}
+
+// conclusionsKnown reports whether any workflow has a conclusion histogram.
+func conclusionsKnown(rows []metrics.SummaryRow) bool {
+	for _, row := range rows {
+		if len(row.Conclusions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func renderColoredConclusions(w io.Writer, rows []metrics.SummaryRow) {
+	title := color.New(color.FgHiWhite, color.Bold)
+	title.Fprintln(w, "🧾 Conclusions")
+
+	header := []string{"Workflow", "Success", "Failure", "Cancelled", "Superseded", "Skipped", "Timed Out", "Other"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+	)
+
+	for _, row := range rows {
+		cells := []string{row.Workflow}
+		for _, n := range conclusionCounts(row) {
+			cells = append(cells, fmt.Sprintf("%d", n))
+		}
+		table.Append(cells)
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownConclusions(w io.Writer, rows []metrics.SummaryRow) {
+	fmt.Fprintln(w, "## Conclusions")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Workflow | Success | Failure | Cancelled | Superseded | Skipped | Timed Out | Other |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range rows {
+		fmt.Fprintf(w, "| %s |", escapeMarkdownCell(row.Workflow))
+		for _, n := range conclusionCounts(row) {
+			fmt.Fprintf(w, " %d |", n)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// trendKnown reports whether daily trends were attached to the rows.
+func trendKnown(rows []metrics.SummaryRow) bool {
+	for _, row := range rows {
+		if row.Daily != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDurationTrend draws the average duration of each day as a sparkline,
+// leaving days without runs blank.
+func formatDurationTrend(daily []metrics.DailyStat) string {
+	values := make([]float64, len(daily))
+	for i, day := range daily {
+		values[i] = math.NaN()
+		if day.Runs > 0 {
+			values[i] = float64(day.AvgDuration)
+		}
+	}
+	if trend := output.Sparkline(values, trendWidth); trend != "" {
+		return trend
+	}
+	return "-"
+}
+
+// formatFailureBar draws the failure rate as a bar colored by severity.
+func formatFailureBar(rate float64) string {
+	bar := output.FailureBar(rate, failureBarWidth)
+	switch {
+	case rate == 0:
+		return color.GreenString(bar)
+	case rate < 0.2:
+		return color.YellowString(bar)
+	default:
+		return color.RedString(bar)
+	}
+}
+
+// hasWorkflowChanges reports whether any workflow file was edited within the
+// window.
+func hasWorkflowChanges(rows []metrics.SummaryRow) bool {
+	for _, row := range rows {
+		if len(row.Changes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var workflowChangeHeader = []string{"Workflow", "Commit", "Changed", "Author", "Message", "Runs", "Avg Duration", "Trend", "Failure Rate"}
+
+func workflowChangeCells(row metrics.SummaryRow, change metrics.WorkflowChange) []string {
+	return []string{
+		row.Workflow,
+		shortSHA(change.SHA),
+		change.ChangedAt.Format("2006-01-02 15:04"),
+		change.Author,
+		change.Message,
+		fmt.Sprintf("%d → %d", change.Before.Runs, change.After.Runs),
+		fmt.Sprintf("%s → %s", output.FormatDuration(change.Before.AvgDuration), output.FormatDuration(change.After.AvgDuration)),
+		formatTrend(change.Trend),
+		fmt.Sprintf("%s → %s", output.FormatFailureRate(change.Before.FailureRate), output.FormatFailureRate(change.After.FailureRate)),
+	}
+}
+
+func renderColoredWorkflowChanges(w io.Writer, rows []metrics.SummaryRow) {
+	titleColor := color.New(color.FgHiWhite, color.Bold)
+	titleColor.Fprintln(w, "📝 Workflow File Changes")
+
+	headerColors := make([]tablewriter.Colors, len(workflowChangeHeader))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(workflowChangeHeader)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgHiBlackColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+	)
+	for _, row := range rows {
+		for _, change := range row.Changes {
+			cells := workflowChangeCells(row, change)
+			switch {
+			case change.Trend.DurationChange >= trendThreshold:
+				cells[7] = color.RedString(cells[7])
+			case change.Trend.DurationChange <= -trendThreshold:
+				cells[7] = color.GreenString(cells[7])
+			}
+			table.Append(cells)
+		}
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownWorkflowChanges(w io.Writer, rows []metrics.SummaryRow) {
+	fmt.Fprintln(w, "## Workflow File Changes")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| "+strings.Join(workflowChangeHeader, " | ")+" |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | ---: | ---: | ---: | ---: |")
+	for _, row := range rows {
+		for _, change := range row.Changes {
+			cells := workflowChangeCells(row, change)
+			for _, i := range []int{0, 4} {
+				cells[i] = escapeMarkdownCell(cells[i])
+			}
+			fmt.Fprintln(w, "| "+strings.Join(cells, " | ")+" |")
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// jobConfigKnown reports whether any job of the workflow was matched to its
+// declaration in the workflow file.
+func jobConfigKnown(row metrics.SummaryRow) bool {
+	for _, job := range row.Jobs {
+		if job.Config != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func formatTimeoutUsage(job metrics.JobSummaryRow) string {
+	if job.Config == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s / %s", output.FormatDuration(job.DurationPercentiles.P99), output.FormatDuration(job.Config.Timeout()))
+}
+
+func formatDeclaredRunsOn(job metrics.JobSummaryRow) string {
+	if job.Config == nil || len(job.Config.RunsOn) == 0 {
+		return "-"
+	}
+	return strings.Join(job.Config.RunsOn, ", ")
+}
+
+func renderMarkdownAlerts(w io.Writer, alerts []metrics.Alert, heading string) {
+	fmt.Fprintf(w, "%s Alerts\n\n", heading)
+	fmt.Fprintln(w, "| Target | Change | Since |")
+	fmt.Fprintln(w, "| --- | --- | --- |")
+	for _, alert := range alerts {
+		fmt.Fprintf(w, "| %s | %s | %s |\n",
+			escapeMarkdownCell(alertTarget(alert)),
+			output.FormatAlertChange(alert),
+			alert.Since.Format(time.RFC3339),
+		)
+	}
+	fmt.Fprintln(w)
 }
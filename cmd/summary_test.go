@@ -79,3 +79,32 @@ func TestWriteCSV(t *testing.T) {
 		t.Fatalf("expected header in csv, got %s", content)
 	}
 }
+
+func TestRenderColoredSummaryTrend(t *testing.T) {
+	day := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	daily := []metrics.DailyStat{
+		{Day: day, Runs: 1, AvgDuration: time.Minute},
+		{Day: day.Add(24 * time.Hour)},
+		{Day: day.Add(48 * time.Hour), Runs: 2, AvgDuration: 3 * time.Minute},
+	}
+	rows := []metrics.SummaryRow{{
+		Workflow:    "build",
+		WorkflowID:  1,
+		Runs:        3,
+		Failed:      1,
+		FailureRate: 0.5,
+		Daily:       daily,
+		Jobs:        []metrics.JobSummaryRow{{Job: "test", Runs: 3, Daily: daily}},
+	}}
+
+	var buf strings.Builder
+	renderColoredSummary(&buf, rows, false)
+	got := buf.String()
+
+	if strings.Count(got, "DURATION TREND") != 2 {
+		t.Fatalf("expected trend columns in workflow and job tables:\n%s", got)
+	}
+	if !strings.Contains(got, "▁ █") || !strings.Contains(got, "█████░░░░░") || !strings.Contains(got, "░░░░░░░░░░") {
+		t.Fatalf("expected sparklines and failure bars:\n%s", got)
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagTimeoutsNear     = "near-timeout"
+	flagTimeoutsHeadroom = "headroom"
+)
+
+type timeoutsReport struct {
+	Wasted time.Duration        `json:"wasted"`
+	Jobs   []metrics.TimeoutRow `json:"jobs"`
+}
+
+func newTimeoutsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timeouts <owner>/<repo>",
+		Short: "Find timed-out and hung jobs and recommend tighter timeouts",
+		Long: heredoc.Doc(`
+			Report jobs that concluded timed_out, or were cancelled after running close to their
+			timeout-minutes (likely hung), along with the runner time they burned. For jobs with enough
+			successful runs, recommend a tighter timeout from the p99 duration of those runs.
+			Cancellations of runs superseded by a newer run never count as hangs.
+
+			Timeouts are read from the workflow files, fetched at the head commit of each workflow's
+			latest run unless --workflow-dir points at a local checkout. Jobs without timeout-minutes
+			use GitHub's default of 360 minutes.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			opts := metrics.DefaultTimeoutOptions()
+			if opts.NearTimeout, err = cmd.Flags().GetFloat64(flagTimeoutsNear); err != nil {
+				return err
+			}
+			if opts.NearTimeout <= 0 || opts.NearTimeout > 1 {
+				return fmt.Errorf("--%s must be greater than 0 and at most 1", flagTimeoutsNear)
+			}
+			if opts.Headroom, err = cmd.Flags().GetFloat64(flagTimeoutsHeadroom); err != nil {
+				return err
+			}
+			if opts.Headroom < 1 {
+				return fmt.Errorf("--%s must be at least 1", flagTimeoutsHeadroom)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			files, err := loadWorkflowFiles(ctx, cmd, client, collection)
+			if err != nil {
+				return err
+			}
+			if files == nil {
+				if files, err = fetchWorkflowFiles(ctx, client, collection); err != nil {
+					return err
+				}
+			}
+
+			rows := metrics.Timeouts(collection.records, collection.from, collection.to, timeoutLookup(files), opts)
+			report := timeoutsReport{Jobs: rows}
+			for _, row := range rows {
+				report.Wasted += row.Wasted
+			}
+
+			if viper.GetBool(flagJSON) {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			if viper.GetBool(flagMarkdown) {
+				renderMarkdownTimeouts(stdout, report)
+				return nil
+			}
+
+			terminal := term.FromEnv()
+			renderColoredTimeouts(os.Stdout, report, terminal.IsColorEnabled())
+			return nil
+		},
+	}
+
+	defaults := metrics.DefaultTimeoutOptions()
+	addRunLimitFlag(cmd)
+	cmd.Flags().Float64(flagTimeoutsNear, defaults.NearTimeout, "Fraction of the timeout after which a cancelled job counts as hung")
+	cmd.Flags().Float64(flagTimeoutsHeadroom, defaults.Headroom, "Multiplier applied to the p99 duration of successful runs to recommend a timeout")
+	addWorkflowFileFlags(cmd)
+
+	return cmd
+}
+
+// timeoutLookup resolves job timeouts from the workflow files, falling back
+// to GitHub's default for jobs that are not found.
+func timeoutLookup(files map[int64]*workflowfile.Workflow) metrics.TimeoutLookup {
+	return func(workflowID int64, job string) (time.Duration, bool) {
+		if wf := files[workflowID]; wf != nil {
+			if declared := wf.MatchJob(job); declared != nil {
+				return declared.Timeout(), declared.TimeoutMinutes > 0
+			}
+		}
+		return workflowfile.DefaultTimeoutMinutes * time.Minute, false
+	}
+}
+
+func formatConfiguredTimeout(row metrics.TimeoutRow) string {
+	if row.TimeoutDeclared {
+		return output.FormatDuration(row.Timeout)
+	}
+	return output.FormatDuration(row.Timeout) + " (default)"
+}
+
+func formatRecommendedTimeout(row metrics.TimeoutRow) string {
+	if row.Recommended == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", int(row.Recommended/time.Minute))
+}
+
+func renderColoredTimeouts(w io.Writer, report timeoutsReport, colorEnabled bool) {
+	if !colorEnabled {
+		color.NoColor = true
+	}
+
+	titleColor := color.New(color.FgCyan, color.Bold)
+	fmt.Fprintln(w)
+	titleColor.Fprintln(w, "⏳ Timeouts and Hung Jobs")
+	fmt.Fprintln(w)
+
+	if len(report.Jobs) == 0 {
+		successColor := color.New(color.FgGreen)
+		successColor.Fprintln(w, "✅ No timed-out or hung jobs, and every timeout is already tight")
+		return
+	}
+
+	labelColor := color.New(color.FgHiBlack)
+	labelColor.Fprint(w, "Runner time wasted by timed-out and hung jobs: ")
+	color.New(color.FgRed, color.Bold).Fprintln(w, output.FormatDuration(report.Wasted))
+	fmt.Fprintln(w)
+
+	header := []string{"Workflow", "Job", "Runs", "Timed Out", "Hung", "Wasted", "Timeout", "Success p99", "Suggested timeout-minutes"}
+	headerColors := make([]tablewriter.Colors, len(header))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgCyanColor}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(true)
+	table.SetHeaderColor(headerColors...)
+	table.SetColumnColor(
+		tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgHiWhiteColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgYellowColor},
+		tablewriter.Colors{tablewriter.FgBlueColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+	)
+
+	for _, row := range report.Jobs {
+		table.Append([]string{
+			row.Workflow,
+			row.Job,
+			fmt.Sprintf("%d", row.Runs),
+			fmt.Sprintf("%d", row.TimedOut),
+			fmt.Sprintf("%d", row.HungCancelled),
+			output.FormatDuration(row.Wasted),
+			formatConfiguredTimeout(row),
+			output.FormatDuration(row.SuccessP99),
+			formatRecommendedTimeout(row),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+}
+
+func renderMarkdownTimeouts(w io.Writer, report timeoutsReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Timeouts and Hung Jobs")
+	fmt.Fprintln(w)
+
+	if len(report.Jobs) == 0 {
+		fmt.Fprintln(w, "_No timed-out or hung jobs, and every timeout is already tight._")
+		return
+	}
+
+	fmt.Fprintf(w, "Runner time wasted by timed-out and hung jobs: **%s**\n\n", output.FormatDuration(report.Wasted))
+	fmt.Fprintln(w, "| Workflow | Job | Runs | Timed Out | Hung | Wasted | Timeout | Success p99 | Suggested timeout-minutes |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |")
+	for _, row := range report.Jobs {
+		fmt.Fprintf(w, "| %s | %s | %d | %d | %d | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(row.Workflow),
+			escapeMarkdownCell(row.Job),
+			row.Runs,
+			row.TimedOut,
+			row.HungCancelled,
+			output.FormatDuration(row.Wasted),
+			formatConfiguredTimeout(row),
+			output.FormatDuration(row.SuccessP99),
+			formatRecommendedTimeout(row),
+		)
+	}
+	fmt.Fprintln(w)
+}
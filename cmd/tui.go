@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JohnTitor/gh-actrics/internal/tui"
+	"github.com/JohnTitor/gh-actrics/internal/util"
+	"github.com/MakeNowJust/heredoc/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+func newTUICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui <owner>/<repo>",
+		Short: "Browse workflow metrics interactively",
+		Long: heredoc.Doc(`
+			Open an interactive browser over the workflow summary. Select a workflow to see its jobs,
+			a job to see its recent runs, and a run to see its steps.
+
+			Keys: ↑/↓ move, enter open, esc go back, s cycle sort column, S reverse sort,
+			/ filter, r refresh, q quit.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			owner, repo, err := util.ParseRepo(args[0])
+			if err != nil {
+				return err
+			}
+
+			runLimit, err := getRunLimit(cmd)
+			if err != nil {
+				return err
+			}
+
+			policy, err := failurePolicyFromFlags()
+			if err != nil {
+				return err
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			collection, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit})
+			if err != nil {
+				return err
+			}
+			if collection == nil {
+				return nil
+			}
+
+			load := func(ctx context.Context) (tui.Data, error) {
+				refreshed, err := collectRuns(ctx, client, owner, repo, collectOptions{runLimit: runLimit, quiet: true})
+				if err != nil || refreshed == nil {
+					return tui.Data{}, err
+				}
+				return tui.Data{Records: refreshed.records, From: refreshed.from, To: refreshed.to, Policy: policy}, nil
+			}
+
+			data := tui.Data{Records: collection.records, From: collection.from, To: collection.to, Policy: policy}
+			model := tui.New(ctx, fmt.Sprintf("%s/%s", owner, repo), data, load)
+			program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx))
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("failed to run terminal UI: %w", err)
+			}
+			return nil
+		},
+	}
+
+	addRunLimitFlag(cmd)
+
+	return cmd
+}
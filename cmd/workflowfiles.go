@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	flagWorkflowDir        = "workflow-dir"
+	flagFetchWorkflowFiles = "fetch-workflow-files"
+)
+
+// addWorkflowFileFlags registers the flags that select where workflow files
+// are read from.
+func addWorkflowFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagWorkflowDir, "", "Read workflow files from this local checkout of the repository")
+	cmd.Flags().Bool(flagFetchWorkflowFiles, false, "Fetch workflow files from GitHub at the head commit of each workflow's latest run")
+	cmd.MarkFlagsMutuallyExclusive(flagWorkflowDir, flagFetchWorkflowFiles)
+}
+
+// loadWorkflowFiles parses the workflow files of the collected workflows,
+// keyed by workflow ID. It returns nil when neither source flag is set. Files
+// that cannot be read or parsed are skipped with a warning.
+func loadWorkflowFiles(ctx context.Context, cmd *cobra.Command, client *githubapi.Client, collection *runCollection) (map[int64]*workflowfile.Workflow, error) {
+	dir, err := cmd.Flags().GetString(flagWorkflowDir)
+	if err != nil {
+		return nil, err
+	}
+	fetch, err := cmd.Flags().GetBool(flagFetchWorkflowFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case dir != "":
+		return readWorkflowFiles(dir, collection), nil
+	case fetch:
+		return fetchWorkflowFiles(ctx, client, collection)
+	}
+	return nil, nil
+}
+
+// readWorkflowFiles parses the workflow files of the collected workflows from
+// a local checkout of the repository.
+func readWorkflowFiles(dir string, collection *runCollection) map[int64]*workflowfile.Workflow {
+	files := make(map[int64]*workflowfile.Workflow)
+	for _, wf := range collection.workflows {
+		parsed, err := workflowfile.ParseFile(filepath.Join(dir, filepath.FromSlash(wf.Path)))
+		if err != nil {
+			slog.Warn("failed to read workflow file", slog.String("workflow", wf.Name), slog.String("error", err.Error()))
+			continue
+		}
+		files[wf.ID] = parsed
+	}
+	return files
+}
+
+func fetchWorkflowFiles(ctx context.Context, client *githubapi.Client, collection *runCollection) (map[int64]*workflowfile.Workflow, error) {
+	// Use the workflow file as of the most recent run so the configuration
+	// matches what produced the latest data.
+	latest := make(map[int64]githubapi.WorkflowRun)
+	for _, rec := range collection.records {
+		if run, ok := latest[rec.Workflow.ID]; !ok || rec.Run.CreatedAt.After(run.CreatedAt) {
+			latest[rec.Workflow.ID] = rec.Run
+		}
+	}
+
+	refs := make(map[int64]string, len(latest))
+	for id, run := range latest {
+		refs[id] = run.HeadSHA
+	}
+	return fetchWorkflowFilesAt(ctx, client, collection, refs)
+}
+
+// fetchDefaultBranchWorkflowFiles fetches the workflow files as they are on
+// the repository's default branch, which is the version GitHub reads
+// schedule triggers from.
+func fetchDefaultBranchWorkflowFiles(ctx context.Context, client *githubapi.Client, collection *runCollection) (map[int64]*workflowfile.Workflow, error) {
+	repository, err := client.GetRepository(ctx, collection.owner, collection.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up default branch of %s/%s: %w", collection.owner, collection.repo, err)
+	}
+
+	refs := make(map[int64]string, len(collection.workflows))
+	for _, wf := range collection.workflows {
+		refs[wf.ID] = repository.DefaultBranch
+	}
+	return fetchWorkflowFilesAt(ctx, client, collection, refs)
+}
+
+// fetchWorkflowFilesAt fetches the file of each collected workflow at the ref
+// given for its ID. Files that cannot be fetched or parsed are skipped with a
+// warning.
+func fetchWorkflowFilesAt(ctx context.Context, client *githubapi.Client, collection *runCollection, refs map[int64]string) (map[int64]*workflowfile.Workflow, error) {
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		mu    sync.Mutex
+		files = make(map[int64]*workflowfile.Workflow)
+	)
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, wf := range collection.workflows {
+		workflow := wf
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			ref := refs[workflow.ID]
+			data, err := client.GetFileContents(gctx, collection.owner, collection.repo, workflow.Path, ref)
+			if err != nil {
+				slog.Warn("failed to fetch workflow file", slog.String("workflow", workflow.Name), slog.String("ref", ref), slog.String("error", err.Error()))
+				return nil
+			}
+			parsed, err := workflowfile.Parse(data)
+			if err != nil {
+				slog.Warn("failed to parse workflow file", slog.String("workflow", workflow.Name), slog.String("error", fmt.Sprintf("%s: %v", workflow.Path, err)))
+				return nil
+			}
+
+			mu.Lock()
+			files[workflow.ID] = parsed
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// fetchWorkflowChanges lists the commits within the window that edited the
+// file of each collected workflow, keyed by workflow ID. Workflows whose
+// history cannot be fetched are skipped with a warning.
+func fetchWorkflowChanges(ctx context.Context, client *githubapi.Client, collection *runCollection) (map[int64][]githubapi.Commit, error) {
+	threads := viper.GetInt(flagThreads)
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		commits = make(map[int64][]githubapi.Commit)
+	)
+	sem := semaphore.NewWeighted(int64(threads))
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, wf := range collection.workflows {
+		workflow := wf
+		g.Go(func() error {
+			if err := sem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			history, err := client.ListCommits(gctx, collection.owner, collection.repo, workflow.Path, collection.from, collection.to)
+			if err != nil {
+				slog.Warn("failed to fetch workflow file history", slog.String("workflow", workflow.Name), slog.String("error", err.Error()))
+				return nil
+			}
+
+			mu.Lock()
+			commits[workflow.ID] = history
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
@@ -150,8 +150,8 @@ func renderMarkdownWorkflows(w io.Writer, workflows []githubapi.Workflow, owner,
 		state := workflow.State
 		fmt.Fprintf(w, "| %d | %s | %s | %s |\n",
 			workflow.ID,
-			workflow.Name,
-			workflow.Path,
+			escapeMarkdownCell(workflow.Name),
+			escapeMarkdownCell(workflow.Path),
 			state,
 		)
 	}
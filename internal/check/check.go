@@ -0,0 +1,339 @@
+package check
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+)
+
+// Scope selects which summary rows a rule is evaluated against.
+type Scope string
+
+const (
+	// ScopeWorkflow evaluates a rule once per workflow.
+	ScopeWorkflow Scope = "workflow"
+	// ScopeJob evaluates a rule once per job of every workflow.
+	ScopeJob Scope = "job"
+)
+
+type unit int
+
+const (
+	unitRatio unit = iota
+	unitDuration
+	unitCount
+)
+
+// Rule is a threshold that an aggregated metric must satisfy, such as
+// `failure_rate < 5%` or `job[test]: p95(duration) < 15m`.
+type Rule struct {
+	Raw   string `json:"rule"`
+	Scope Scope  `json:"scope"`
+	// Target restricts the rule to the workflow or job with this name. An
+	// empty target applies the rule to every workflow or job.
+	Target    string  `json:"target,omitempty"`
+	Metric    string  `json:"metric"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+
+	unit unit
+}
+
+// Violation reports a row that did not satisfy a rule.
+type Violation struct {
+	Rule     Rule    `json:"rule"`
+	Workflow string  `json:"workflow"`
+	Job      string  `json:"job,omitempty"`
+	Value    float64 `json:"value"`
+}
+
+var (
+	scopePattern    = regexp.MustCompile(`^(?i:(workflow|job))\s*(?:\[([^\]]+)\])?\s*:(.*)$`)
+	rulePattern     = regexp.MustCompile(`^(.+?)\s*(<=|>=|==|!=|<|>)\s*(.+)$`)
+	funcPattern     = regexp.MustCompile(`^(avg|total|p\d{2})\((duration|queue)\)$`)
+	suffixPattern   = regexp.MustCompile(`^(duration|queue)_(avg|total|p\d{2})$`)
+	prefixPattern   = regexp.MustCompile(`^(avg|total)_(duration|queue)$`)
+	supportedPctile = map[string]bool{"p50": true, "p90": true, "p95": true, "p99": true}
+)
+
+// Parse parses a single rule. A rule has the form
+//
+//	[workflow[<name>]:|job[<name>]:] <metric> <op> <threshold>
+//
+// where the optional bracketed name restricts the rule to one workflow or job,
+// and metric is one of failure_rate, runs, failed, avg(duration),
+// total(duration), pNN(duration) or pNN(queue) (also accepted as
+// duration_pNN, queue_pNN, avg_duration, ...). Rates accept percentages
+// ("5%") or fractions ("0.05") and durations accept Go duration strings
+// ("15m").
+func Parse(raw string) (Rule, error) {
+	rule := Rule{Raw: strings.TrimSpace(raw), Scope: ScopeWorkflow}
+	body := rule.Raw
+	if m := scopePattern.FindStringSubmatch(body); m != nil {
+		rule.Scope = Scope(strings.ToLower(m[1]))
+		rule.Target = strings.TrimSpace(m[2])
+		body = m[3]
+	} else if scope, _, ok := strings.Cut(body, ":"); ok {
+		return Rule{}, fmt.Errorf("invalid rule %q: unknown scope %q", raw, scope)
+	}
+
+	m := rulePattern.FindStringSubmatch(strings.TrimSpace(body))
+	if m == nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: expected <metric> <op> <threshold>", raw)
+	}
+
+	metric, u, err := canonicalMetric(m[1])
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", raw, err)
+	}
+	threshold, err := parseThreshold(m[3], u)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", raw, err)
+	}
+
+	rule.Metric = metric
+	rule.Op = m[2]
+	rule.Threshold = threshold
+	rule.unit = u
+	return rule, nil
+}
+
+// ParseFile parses one rule per line, ignoring blank lines and lines starting
+// with '#'.
+func ParseFile(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rule, err := Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Evaluate checks every rule against the summary rows and returns the
+// violations in rule order.
+func Evaluate(rules []Rule, rows []metrics.SummaryRow) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		for _, row := range rows {
+			if rule.Scope == ScopeJob {
+				for _, job := range row.Jobs {
+					if !rule.targets(job.Job) {
+						continue
+					}
+					value := jobValue(rule.Metric, job)
+					if !rule.satisfied(value) {
+						violations = append(violations, Violation{Rule: rule, Workflow: row.Workflow, Job: job.Job, Value: value})
+					}
+				}
+				continue
+			}
+			if !rule.targets(row.Workflow) {
+				continue
+			}
+
+			value := workflowValue(rule.Metric, row)
+			if !rule.satisfied(value) {
+				violations = append(violations, Violation{Rule: rule, Workflow: row.Workflow, Value: value})
+			}
+		}
+	}
+	return violations
+}
+
+// Unmatched returns the rules that no summary row was evaluated against, such
+// as a rule naming a workflow that had no runs in the window.
+func Unmatched(rules []Rule, rows []metrics.SummaryRow) []Rule {
+	var unmatched []Rule
+	for _, rule := range rules {
+		if !rule.matchesAny(rows) {
+			unmatched = append(unmatched, rule)
+		}
+	}
+	return unmatched
+}
+
+func (r Rule) matchesAny(rows []metrics.SummaryRow) bool {
+	for _, row := range rows {
+		if r.Scope != ScopeJob {
+			if r.targets(row.Workflow) {
+				return true
+			}
+			continue
+		}
+		for _, job := range row.Jobs {
+			if r.targets(job.Job) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r Rule) targets(name string) bool {
+	return r.Target == "" || r.Target == name
+}
+
+// FormatValue renders a metric value in the rule's unit.
+func (r Rule) FormatValue(v float64) string {
+	switch r.unit {
+	case unitRatio:
+		return fmt.Sprintf("%.1f%%", v*100)
+	case unitDuration:
+		return time.Duration(v).Truncate(time.Second).String()
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+func (r Rule) satisfied(v float64) bool {
+	switch r.Op {
+	case "<":
+		return v < r.Threshold
+	case "<=":
+		return v <= r.Threshold
+	case ">":
+		return v > r.Threshold
+	case ">=":
+		return v >= r.Threshold
+	case "==":
+		return v == r.Threshold
+	case "!=":
+		return v != r.Threshold
+	default:
+		return false
+	}
+}
+
+func canonicalMetric(raw string) (string, unit, error) {
+	name := strings.ToLower(strings.Join(strings.Fields(raw), ""))
+	switch name {
+	case "failure_rate", "failurerate":
+		return "failure_rate", unitRatio, nil
+	case "runs":
+		return "runs", unitCount, nil
+	case "failed", "failures":
+		return "failed", unitCount, nil
+	}
+
+	var fn, field string
+	if m := funcPattern.FindStringSubmatch(name); m != nil {
+		fn, field = m[1], m[2]
+	} else if m := suffixPattern.FindStringSubmatch(name); m != nil {
+		fn, field = m[2], m[1]
+	} else if m := prefixPattern.FindStringSubmatch(name); m != nil {
+		fn, field = m[1], m[2]
+	} else {
+		return "", 0, fmt.Errorf("unknown metric %q", raw)
+	}
+
+	if strings.HasPrefix(fn, "p") && !supportedPctile[fn] {
+		return "", 0, fmt.Errorf("unsupported percentile %q (use p50, p90, p95 or p99)", fn)
+	}
+	if field == "queue" && !supportedPctile[fn] {
+		return "", 0, fmt.Errorf("queue time only supports percentiles")
+	}
+	return fmt.Sprintf("%s(%s)", fn, field), unitDuration, nil
+}
+
+func parseThreshold(raw string, u unit) (float64, error) {
+	value := strings.TrimSpace(raw)
+	switch u {
+	case unitRatio:
+		if strings.HasSuffix(value, "%") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid percentage %q", raw)
+			}
+			return f / 100, nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rate %q", raw)
+		}
+		return f, nil
+	case unitDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		return float64(d), nil
+	default:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f != math.Trunc(f) {
+			return 0, fmt.Errorf("invalid count %q", raw)
+		}
+		return f, nil
+	}
+}
+
+func workflowValue(metric string, row metrics.SummaryRow) float64 {
+	switch metric {
+	case "failure_rate":
+		return row.FailureRate
+	case "runs":
+		return float64(row.Runs)
+	case "failed":
+		return float64(row.Failed)
+	case "avg(duration)":
+		return float64(row.AvgDuration)
+	case "total(duration)":
+		return float64(row.TotalDuration)
+	}
+	return percentileValue(metric, row.DurationPercentiles, row.QueuePercentiles)
+}
+
+func jobValue(metric string, job metrics.JobSummaryRow) float64 {
+	switch metric {
+	case "failure_rate":
+		return job.FailureRate
+	case "runs":
+		return float64(job.Runs)
+	case "failed":
+		return float64(job.Failed)
+	case "avg(duration)":
+		return float64(job.AvgDuration)
+	case "total(duration)":
+		return float64(job.TotalDuration)
+	}
+	return percentileValue(metric, job.DurationPercentiles, job.QueuePercentiles)
+}
+
+func percentileValue(metric string, duration, queue metrics.Percentiles) float64 {
+	fn, field, _ := strings.Cut(strings.TrimSuffix(metric, ")"), "(")
+	p := duration
+	if field == "queue" {
+		p = queue
+	}
+	switch fn {
+	case "p50":
+		return float64(p.P50)
+	case "p90":
+		return float64(p.P90)
+	case "p95":
+		return float64(p.P95)
+	case "p99":
+		return float64(p.P99)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,151 @@
+package check
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		raw       string
+		scope     Scope
+		metric    string
+		op        string
+		threshold float64
+	}{
+		{"failure_rate < 5%", ScopeWorkflow, "failure_rate", "<", 0.05},
+		{"failure_rate<=0.1", ScopeWorkflow, "failure_rate", "<=", 0.1},
+		{"p95(duration) < 15m", ScopeWorkflow, "p95(duration)", "<", float64(15 * time.Minute)},
+		{"job: queue_p90 < 2m", ScopeJob, "p90(queue)", "<", float64(2 * time.Minute)},
+		{"workflow: avg_duration >= 30s", ScopeWorkflow, "avg(duration)", ">=", float64(30 * time.Second)},
+		{"runs > 3", ScopeWorkflow, "runs", ">", 3},
+		{"job[unit tests]: failed == 0", ScopeJob, "failed", "==", 0},
+		{"Workflow[CI: lint]: runs >= 1", ScopeWorkflow, "runs", ">=", 1},
+	}
+
+	for _, tc := range cases {
+		rule, err := Parse(tc.raw)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.raw, err)
+		}
+		if rule.Scope != tc.scope || rule.Metric != tc.metric || rule.Op != tc.op || rule.Threshold != tc.threshold {
+			t.Fatalf("unexpected rule for %q: %#v", tc.raw, rule)
+		}
+	}
+
+	for _, raw := range []string{"", "failure_rate", "bogus < 1", "p42(duration) < 1m", "avg(queue) < 1m", "p95(duration) < soon", "team: runs > 1", "runs > 1.5", "job[]: runs > 1"} {
+		if _, err := Parse(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	input := `
+# CI health
+failure_rate < 5%
+
+job: p95(duration) < 15m
+`
+	rules, err := ParseFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+
+	if _, err := ParseFile(strings.NewReader("runs > 1\nnope\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line number in error, got %v", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rows := []metrics.SummaryRow{
+		{
+			Workflow:            "build",
+			Runs:                10,
+			Failed:              2,
+			FailureRate:         0.2,
+			DurationPercentiles: metrics.Percentiles{P95: 20 * time.Minute},
+			Jobs: []metrics.JobSummaryRow{
+				{Job: "test", Runs: 10, QueuePercentiles: metrics.Percentiles{P90: 5 * time.Minute}},
+				{Job: "lint", Runs: 10, QueuePercentiles: metrics.Percentiles{P90: 10 * time.Second}},
+			},
+		},
+		{
+			Workflow:            "docs",
+			Runs:                4,
+			FailureRate:         0,
+			DurationPercentiles: metrics.Percentiles{P95: time.Minute},
+		},
+	}
+
+	var rules []Rule
+	for _, raw := range []string{"failure_rate < 5%", "p95(duration) < 15m", "job: p90(queue) < 2m"} {
+		rule, err := Parse(raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	violations := Evaluate(rules, rows)
+	if len(violations) != 3 {
+		t.Fatalf("expected 3 violations, got %#v", violations)
+	}
+	if v := violations[0]; v.Workflow != "build" || v.Rule.FormatValue(v.Value) != "20.0%" {
+		t.Fatalf("unexpected failure rate violation: %#v", v)
+	}
+	if v := violations[1]; v.Workflow != "build" || v.Rule.FormatValue(v.Value) != "20m0s" {
+		t.Fatalf("unexpected duration violation: %#v", v)
+	}
+	if v := violations[2]; v.Job != "test" || v.Rule.FormatValue(v.Value) != "5m0s" {
+		t.Fatalf("unexpected queue violation: %#v", v)
+	}
+}
+
+func TestEvaluateTarget(t *testing.T) {
+	rows := []metrics.SummaryRow{
+		{
+			Workflow:    "build",
+			FailureRate: 0.2,
+			Jobs: []metrics.JobSummaryRow{
+				{Job: "test", Failed: 2},
+				{Job: "lint", Failed: 1},
+			},
+		},
+		{Workflow: "docs", FailureRate: 0.5},
+	}
+
+	var rules []Rule
+	for _, raw := range []string{"workflow[docs]: failure_rate < 30%", "job[lint]: failed == 0", "job[deploy]: failed == 0"} {
+		rule, err := Parse(raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if rules[0].Target != "docs" || rules[1].Target != "lint" {
+		t.Fatalf("unexpected targets: %#v", rules)
+	}
+
+	violations := Evaluate(rules, rows)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %#v", violations)
+	}
+	if v := violations[0]; v.Workflow != "docs" || v.Job != "" {
+		t.Fatalf("unexpected workflow violation: %#v", v)
+	}
+	if v := violations[1]; v.Workflow != "build" || v.Job != "lint" {
+		t.Fatalf("unexpected job violation: %#v", v)
+	}
+
+	unmatched := Unmatched(rules, rows)
+	if len(unmatched) != 1 || unmatched[0].Target != "deploy" {
+		t.Fatalf("expected only the deploy rule to be unmatched, got %#v", unmatched)
+	}
+}
@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(s string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("bad time %q: %v", s, err)
+		}
+		return parsed
+	}
+	cases := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"30 2 * * *", "2025-07-01T02:30:00Z", "2025-07-02T02:30:00Z"},
+		{"*/15 * * * *", "2025-07-01T10:07:00Z", "2025-07-01T10:15:00Z"},
+		{"0 9 * * mon-fri", "2025-07-04T10:00:00Z", "2025-07-07T09:00:00Z"},
+		{"0 0 1 jan,jul *", "2025-07-02T00:00:00Z", "2026-01-01T00:00:00Z"},
+		{"0 0 * * 7", "2025-07-01T00:00:00Z", "2025-07-06T00:00:00Z"},
+		// Both day fields restricted: the 15th or any Monday.
+		{"0 0 15 * 1", "2025-07-08T00:00:00Z", "2025-07-14T00:00:00Z"},
+		{"0 0 */2 * *", "2025-07-01T00:00:00Z", "2025-07-03T00:00:00Z"},
+		{"0 12 31 * *", "2025-06-01T00:00:00Z", "2025-07-31T12:00:00Z"},
+	}
+	for _, tc := range cases {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.expr, err)
+		}
+		if got := s.Next(at(tc.from)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q after %s = %s, want %s", tc.expr, tc.from, got.Format(time.RFC3339), tc.want)
+		}
+	}
+
+	never, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := never.Next(at("2025-01-01T00:00:00Z")); !got.IsZero() {
+		t.Fatalf("expected February 30 never to fire, got %s", got)
+	}
+}
+
+func TestBetweenIncludesBounds(t *testing.T) {
+	s, err := Parse("0 */6 * * *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	times := s.Between(from, from.Add(12*time.Hour))
+	if len(times) != 3 || !times[0].Equal(from) || !times[2].Equal(from.Add(12*time.Hour)) {
+		t.Fatalf("unexpected fire times %v", times)
+	}
+}
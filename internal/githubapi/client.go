@@ -2,12 +2,16 @@ package githubapi
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/JohnTitor/gh-actrics/internal/cache"
@@ -19,16 +23,22 @@ type Options struct {
 	CacheTTL    time.Duration
 	EnableCache bool
 	CacheDir    string
+	// LogCacheTTL caches downloaded job logs separately from API responses
+	// when positive. Logs of completed jobs never change, so they are worth
+	// keeping longer than listings.
+	LogCacheTTL time.Duration
 }
 
 type restClient interface {
 	Get(path string, response interface{}) error
+	Request(method string, path string, body io.Reader) (*http.Response, error)
 }
 
 // Client wraps github.com/cli/go-gh REST client for higher-level operations.
 type Client struct {
-	rest  restClient
-	cache *cache.Cache
+	rest     restClient
+	cache    *cache.Cache
+	logCache *cache.Cache
 }
 
 // NewClient constructs a Client respecting gh configuration.
@@ -44,27 +54,48 @@ func NewClient(opts Options) (*Client, error) {
 		return nil, err
 	}
 
+	cacheDir := opts.CacheDir
+	if cacheDir == "" && (opts.EnableCache || opts.LogCacheTTL > 0) {
+		cacheDir, err = defaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var cacheStore *cache.Cache
 	if opts.EnableCache && opts.CacheTTL > 0 {
-		cacheDir := opts.CacheDir
-		if cacheDir == "" {
-			cacheDir, err = defaultCacheDir()
-			if err != nil {
-				return nil, err
-			}
-		}
 		cacheStore, err = cache.New(cacheDir, opts.CacheTTL)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	var logCacheStore *cache.Cache
+	if opts.LogCacheTTL > 0 {
+		logCacheStore, err = cache.New(filepath.Join(cacheDir, "logs"), opts.LogCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Client{
-		rest:  rest,
-		cache: cacheStore,
+		rest:     rest,
+		cache:    cacheStore,
+		logCache: logCacheStore,
 	}, nil
 }
 
+// GetRepository returns metadata for the repository.
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (Repository, error) {
+	_ = ctx
+	path := fmt.Sprintf("repos/%s/%s", owner, repo)
+	var response repositoryJSON
+	if err := c.cachedGet(path, &response); err != nil {
+		return Repository{}, err
+	}
+	return Repository(response), nil
+}
+
 // ListWorkflows returns all workflows in the repository.
 func (c *Client) ListWorkflows(ctx context.Context, owner, repo string) ([]Workflow, error) {
 	_ = ctx // context not yet used by go-gh REST client Get
@@ -100,6 +131,15 @@ type WorkflowRunFilter struct {
 
 // ListWorkflowRuns returns runs for the given workflow id.
 func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo string, workflowID int64, filter WorkflowRunFilter, limit int) ([]WorkflowRun, error) {
+	return c.listWorkflowRuns(ctx, fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs", owner, repo, workflowID), filter, limit)
+}
+
+// ListRepositoryRuns returns runs of every workflow in the repository.
+func (c *Client) ListRepositoryRuns(ctx context.Context, owner, repo string, filter WorkflowRunFilter, limit int) ([]WorkflowRun, error) {
+	return c.listWorkflowRuns(ctx, fmt.Sprintf("repos/%s/%s/actions/runs", owner, repo), filter, limit)
+}
+
+func (c *Client) listWorkflowRuns(ctx context.Context, base string, filter WorkflowRunFilter, limit int) ([]WorkflowRun, error) {
 	_ = ctx
 	page := 1
 	var runs []WorkflowRun
@@ -127,7 +167,7 @@ func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo string, workf
 		if filter.Created != "" {
 			params.Set("created", filter.Created)
 		}
-		path := fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?%s", owner, repo, workflowID, params.Encode())
+		path := fmt.Sprintf("%s?%s", base, params.Encode())
 
 		var response workflowRunsResponse
 		if err := c.cachedGet(path, &response); err != nil {
@@ -150,6 +190,17 @@ func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo string, workf
 	return runs, nil
 }
 
+// GetWorkflowRun returns a single workflow run.
+func (c *Client) GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (WorkflowRun, error) {
+	_ = ctx
+	path := fmt.Sprintf("repos/%s/%s/actions/runs/%d", owner, repo, runID)
+	var response workflowRunJSON
+	if err := c.cachedGet(path, &response); err != nil {
+		return WorkflowRun{}, err
+	}
+	return mapWorkflowRun(response), nil
+}
+
 // ListJobs returns jobs for a workflow run.
 func (c *Client) ListJobs(ctx context.Context, owner, repo string, runID int64) ([]WorkflowJob, error) {
 	_ = ctx
@@ -176,6 +227,298 @@ func (c *Client) ListJobs(ctx context.Context, owner, repo string, runID int64)
 	return jobs, nil
 }
 
+// ListRunners returns the self-hosted runners registered with the repository.
+func (c *Client) ListRunners(ctx context.Context, owner, repo string) ([]Runner, error) {
+	return c.listRunners(ctx, fmt.Sprintf("repos/%s/%s/actions/runners", owner, repo))
+}
+
+// ListOrgRunners returns the self-hosted runners registered with the
+// organization.
+func (c *Client) ListOrgRunners(ctx context.Context, org string) ([]Runner, error) {
+	return c.listRunners(ctx, fmt.Sprintf("orgs/%s/actions/runners", org))
+}
+
+func (c *Client) listRunners(ctx context.Context, base string) ([]Runner, error) {
+	_ = ctx
+	page := 1
+	var runners []Runner
+
+	for {
+		path := fmt.Sprintf("%s?per_page=100&page=%d", base, page)
+		var response runnersResponse
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		for _, runner := range response.Runners {
+			runners = append(runners, mapRunner(runner))
+		}
+
+		if len(response.Runners) == 0 || len(runners) >= response.TotalCount {
+			break
+		}
+		page++
+	}
+
+	return runners, nil
+}
+
+// ListArtifacts returns the artifacts stored for the repository, including
+// expired ones.
+func (c *Client) ListArtifacts(ctx context.Context, owner, repo string) ([]Artifact, error) {
+	_ = ctx
+	page := 1
+	var artifacts []Artifact
+
+	for {
+		path := fmt.Sprintf("repos/%s/%s/actions/artifacts?per_page=100&page=%d", owner, repo, page)
+		var response artifactsResponse
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		for _, artifact := range response.Artifacts {
+			artifacts = append(artifacts, mapArtifact(artifact))
+		}
+
+		if len(response.Artifacts) == 0 || len(artifacts) >= response.TotalCount {
+			break
+		}
+		page++
+	}
+
+	return artifacts, nil
+}
+
+// ListActionsCaches returns the Actions cache entries of the repository.
+func (c *Client) ListActionsCaches(ctx context.Context, owner, repo string) ([]ActionsCache, error) {
+	_ = ctx
+	page := 1
+	var caches []ActionsCache
+
+	for {
+		path := fmt.Sprintf("repos/%s/%s/actions/caches?per_page=100&page=%d", owner, repo, page)
+		var response actionsCachesResponse
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range response.ActionsCaches {
+			caches = append(caches, mapActionsCache(entry))
+		}
+
+		if len(response.ActionsCaches) == 0 || len(caches) >= response.TotalCount {
+			break
+		}
+		page++
+	}
+
+	return caches, nil
+}
+
+// ListDeployments returns the deployments of the repository created at or
+// after since, newest first. An empty environment lists all environments.
+func (c *Client) ListDeployments(ctx context.Context, owner, repo, environment string, since time.Time) ([]Deployment, error) {
+	_ = ctx
+	page := 1
+	var deployments []Deployment
+
+	for {
+		params := url.Values{}
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		if environment != "" {
+			params.Set("environment", environment)
+		}
+		path := fmt.Sprintf("repos/%s/%s/deployments?%s", owner, repo, params.Encode())
+		var response []deploymentJSON
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		done := len(response) < 100
+		for _, deployment := range response {
+			if deployment.CreatedAt.Before(since) {
+				done = true
+				break
+			}
+			deployments = append(deployments, Deployment{
+				ID:          deployment.ID,
+				SHA:         deployment.SHA,
+				Ref:         deployment.Ref,
+				Environment: deployment.Environment,
+				CreatedAt:   deployment.CreatedAt.UTC(),
+			})
+		}
+
+		if done {
+			break
+		}
+		page++
+	}
+
+	return deployments, nil
+}
+
+// ListDeploymentStatuses returns the statuses of a deployment, oldest first.
+func (c *Client) ListDeploymentStatuses(ctx context.Context, owner, repo string, deploymentID int64) ([]DeploymentStatus, error) {
+	_ = ctx
+	page := 1
+	var statuses []DeploymentStatus
+
+	for {
+		path := fmt.Sprintf("repos/%s/%s/deployments/%d/statuses?per_page=100&page=%d", owner, repo, deploymentID, page)
+		var response []deploymentStatusJSON
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		for _, status := range response {
+			statuses = append(statuses, DeploymentStatus{
+				State:       status.State,
+				Environment: status.Environment,
+				CreatedAt:   status.CreatedAt.UTC(),
+				LogURL:      status.LogURL,
+				TargetURL:   status.TargetURL,
+			})
+		}
+
+		if len(response) < 100 {
+			break
+		}
+		page++
+	}
+
+	// The API lists the most recent status first.
+	for i, j := 0, len(statuses)-1; i < j; i, j = i+1, j-1 {
+		statuses[i], statuses[j] = statuses[j], statuses[i]
+	}
+	return statuses, nil
+}
+
+// ListCommits returns the commits on the default branch that touched path and
+// were committed within [since, until], newest first.
+func (c *Client) ListCommits(ctx context.Context, owner, repo, path string, since, until time.Time) ([]Commit, error) {
+	_ = ctx
+	page := 1
+	var commits []Commit
+
+	for {
+		params := url.Values{}
+		params.Set("path", path)
+		params.Set("since", since.UTC().Format(time.RFC3339))
+		params.Set("until", until.UTC().Format(time.RFC3339))
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		apiPath := fmt.Sprintf("repos/%s/%s/commits?%s", owner, repo, params.Encode())
+		var response []commitJSON
+		if err := c.cachedGet(apiPath, &response); err != nil {
+			return nil, err
+		}
+
+		for _, commit := range response {
+			commits = append(commits, mapCommit(commit))
+		}
+
+		if len(response) < 100 {
+			break
+		}
+		page++
+	}
+
+	return commits, nil
+}
+
+// ListAnnotations returns the annotations of a check run. Every workflow job
+// is backed by a check run with the same ID.
+func (c *Client) ListAnnotations(ctx context.Context, owner, repo string, checkRunID int64) ([]Annotation, error) {
+	_ = ctx
+	page := 1
+	var annotations []Annotation
+
+	for {
+		path := fmt.Sprintf("repos/%s/%s/check-runs/%d/annotations?per_page=100&page=%d", owner, repo, checkRunID, page)
+		var response []annotationJSON
+		if err := c.cachedGet(path, &response); err != nil {
+			return nil, err
+		}
+
+		for _, annotation := range response {
+			annotations = append(annotations, Annotation(annotation))
+		}
+
+		// The endpoint returns a bare array without a total count.
+		if len(response) < 100 {
+			break
+		}
+		page++
+	}
+
+	return annotations, nil
+}
+
+// GetJobLogs returns the plain-text log of a workflow job.
+func (c *Client) GetJobLogs(ctx context.Context, owner, repo string, jobID int64) ([]byte, error) {
+	_ = ctx
+	path := fmt.Sprintf("repos/%s/%s/actions/jobs/%d/logs", owner, repo, jobID)
+	if c.logCache != nil {
+		if data, ok, err := c.logCache.Get(path); err == nil && ok {
+			return data, nil
+		}
+	}
+
+	// The endpoint redirects to a short-lived download URL; the HTTP client
+	// follows it without forwarding the token.
+	resp, err := c.rest.Request(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", path, err)
+	}
+
+	if c.logCache != nil {
+		_ = c.logCache.Set(path, data)
+	}
+	return data, nil
+}
+
+// GetFileContents returns the contents of a file in the repository at ref.
+func (c *Client) GetFileContents(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	_ = ctx
+	params := url.Values{}
+	if ref != "" {
+		params.Set("ref", ref)
+	}
+	escaped := make([]string, 0, strings.Count(path, "/")+1)
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	apiPath := fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, strings.Join(escaped, "/"))
+	if len(params) > 0 {
+		apiPath += "?" + params.Encode()
+	}
+
+	var response contentJSON
+	if err := c.cachedGet(apiPath, &response); err != nil {
+		return nil, err
+	}
+	if response.Type != "file" {
+		return nil, fmt.Errorf("%s is a %s, not a file", path, response.Type)
+	}
+	if response.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported encoding %q for %s", response.Encoding, path)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(response.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return data, nil
+}
+
 func (c *Client) cachedGet(path string, out interface{}) error {
 	if c.cache != nil {
 		if data, ok, err := c.cache.Get(path); err == nil && ok {
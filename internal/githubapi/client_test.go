@@ -1,8 +1,11 @@
 package githubapi
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
@@ -38,6 +41,21 @@ func (m *mockRESTClient) Get(path string, response interface{}) error {
 	return json.Unmarshal(data, response)
 }
 
+func (m *mockRESTClient) Request(method string, path string, body io.Reader) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[path]++
+	payload, ok := m.responses[path]
+	if !ok {
+		return nil, fmt.Errorf("no mock response for %s %s", method, path)
+	}
+	text, ok := payload.(string)
+	if !ok {
+		return nil, fmt.Errorf("mock response for %s is not raw text", path)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(text))}, nil
+}
+
 func TestListWorkflowRunsHonorsLimit(t *testing.T) {
 	responses := map[string]interface{}{
 		"repos/org/repo/actions/workflows/1/runs?created=2025-01-01T00%3A00%3A00Z..2025-01-02T00%3A00%3A00Z&page=1&per_page=2": workflowRunsResponse{
@@ -67,6 +85,28 @@ func TestListWorkflowRunsHonorsLimit(t *testing.T) {
 	}
 }
 
+func TestListRepositoryRuns(t *testing.T) {
+	path := "repos/org/repo/actions/runs?created=%3E%3D2025-01-01&page=1&per_page=100"
+	responses := map[string]interface{}{
+		path: workflowRunsResponse{
+			TotalCount: 2,
+			WorkflowRuns: []workflowRunJSON{
+				{ID: 1, WorkflowID: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+				{ID: 2, WorkflowID: 2, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			},
+		},
+	}
+
+	client := &Client{rest: newMockREST(responses)}
+	runs, err := client.ListRepositoryRuns(nil, "org", "repo", WorkflowRunFilter{Created: ">=2025-01-01"}, 0)
+	if err != nil {
+		t.Fatalf("ListRepositoryRuns failed: %v", err)
+	}
+	if len(runs) != 2 || runs[1].WorkflowID != 2 {
+		t.Fatalf("unexpected runs: %#v", runs)
+	}
+}
+
 func TestListWorkflowsUsesCache(t *testing.T) {
 	path := "repos/org/repo/actions/workflows?per_page=100&page=1"
 	responses := map[string]interface{}{
@@ -100,3 +140,180 @@ func TestListWorkflowsUsesCache(t *testing.T) {
 		t.Fatalf("expected cached response to prevent duplicate API call, got %d calls", calls)
 	}
 }
+
+func TestGetFileContentsDecodesBase64(t *testing.T) {
+	path := "repos/org/repo/contents/.github/workflows/ci.yml?ref=abc123"
+	responses := map[string]interface{}{
+		path: contentJSON{Type: "file", Encoding: "base64", Content: "bmFtZTog\nY2kK\n"},
+	}
+	client := &Client{rest: newMockREST(responses)}
+
+	data, err := client.GetFileContents(nil, "org", "repo", ".github/workflows/ci.yml", "abc123")
+	if err != nil {
+		t.Fatalf("GetFileContents failed: %v", err)
+	}
+	if string(data) != "name: ci\n" {
+		t.Fatalf("unexpected contents %q", data)
+	}
+}
+
+func TestListRunnersFlattensLabels(t *testing.T) {
+	path := "orgs/org/actions/runners?per_page=100&page=1"
+	response := runnersResponse{TotalCount: 1, Runners: []runnerJSON{{ID: 5, Name: "linux-1", OS: "Linux", Status: "online"}}}
+	response.Runners[0].Labels = append(response.Runners[0].Labels, struct {
+		Name string `json:"name"`
+	}{Name: "self-hosted"})
+	client := &Client{rest: newMockREST(map[string]interface{}{path: response})}
+
+	runners, err := client.ListOrgRunners(nil, "org")
+	if err != nil {
+		t.Fatalf("ListOrgRunners failed: %v", err)
+	}
+	if len(runners) != 1 || runners[0].Name != "linux-1" || len(runners[0].Labels) != 1 || runners[0].Labels[0] != "self-hosted" {
+		t.Fatalf("unexpected runners %+v", runners)
+	}
+}
+
+func TestListAnnotationsPaginates(t *testing.T) {
+	first := make([]annotationJSON, 100)
+	for i := range first {
+		first[i] = annotationJSON{Path: "main.go", StartLine: i + 1, Level: "failure", Message: "boom"}
+	}
+	responses := map[string]interface{}{
+		"repos/org/repo/check-runs/7/annotations?per_page=100&page=1": first,
+		"repos/org/repo/check-runs/7/annotations?per_page=100&page=2": []annotationJSON{{Level: "warning", Title: "lint", Message: "unused"}},
+	}
+	client := &Client{rest: newMockREST(responses)}
+
+	annotations, err := client.ListAnnotations(nil, "org", "repo", 7)
+	if err != nil {
+		t.Fatalf("ListAnnotations failed: %v", err)
+	}
+	if len(annotations) != 101 {
+		t.Fatalf("expected 101 annotations, got %d", len(annotations))
+	}
+	if last := annotations[100]; last.Level != "warning" || last.Title != "lint" || last.Message != "unused" {
+		t.Fatalf("unexpected annotation %+v", last)
+	}
+}
+
+func TestGetJobLogsUsesCache(t *testing.T) {
+	path := "repos/org/repo/actions/jobs/9/logs"
+	mock := newMockREST(map[string]interface{}{path: "2025-01-01T00:00:00.0000000Z hello\n"})
+
+	cacheStore, err := cache.New(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	client := &Client{rest: mock, logCache: cacheStore}
+
+	for i := 0; i < 2; i++ {
+		data, err := client.GetJobLogs(nil, "org", "repo", 9)
+		if err != nil {
+			t.Fatalf("GetJobLogs failed: %v", err)
+		}
+		if string(data) != "2025-01-01T00:00:00.0000000Z hello\n" {
+			t.Fatalf("unexpected log %q", data)
+		}
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if calls := mock.calls[path]; calls != 1 {
+		t.Fatalf("expected cached log to prevent duplicate download, got %d calls", calls)
+	}
+}
+
+func TestListArtifactsMapsWorkflowRun(t *testing.T) {
+	created := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	expires := created.Add(90 * 24 * time.Hour)
+	response := artifactsResponse{TotalCount: 1, Artifacts: []artifactJSON{{ID: 3, Name: "coverage", SizeInBytes: 2048, CreatedAt: &created, ExpiresAt: &expires}}}
+	response.Artifacts[0].WorkflowRun = &struct {
+		ID         int64  `json:"id"`
+		HeadBranch string `json:"head_branch"`
+	}{ID: 42, HeadBranch: "main"}
+	client := &Client{rest: newMockREST(map[string]interface{}{"repos/org/repo/actions/artifacts?per_page=100&page=1": response})}
+
+	artifacts, err := client.ListArtifacts(nil, "org", "repo")
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if len(artifacts) != 1 {
+		t.Fatalf("expected 1 artifact, got %d", len(artifacts))
+	}
+	got := artifacts[0]
+	if got.Name != "coverage" || got.Size != 2048 || got.RunID != 42 || got.Branch != "main" || !got.ExpiresAt.Equal(expires) {
+		t.Fatalf("unexpected artifact %+v", got)
+	}
+}
+
+func TestListDeploymentsStopsAtSince(t *testing.T) {
+	since := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	first := make([]deploymentJSON, 100)
+	for i := range first {
+		first[i] = deploymentJSON{ID: int64(200 - i), Environment: "production", CreatedAt: since.Add(time.Duration(100-i) * time.Hour)}
+	}
+	first[99].CreatedAt = since.Add(-time.Hour)
+	path := "repos/org/repo/deployments?environment=production&page=1&per_page=100"
+	mock := newMockREST(map[string]interface{}{path: first})
+	client := &Client{rest: mock}
+
+	deployments, err := client.ListDeployments(nil, "org", "repo", "production", since)
+	if err != nil {
+		t.Fatalf("ListDeployments failed: %v", err)
+	}
+	if len(deployments) != 99 {
+		t.Fatalf("expected deployments before since to be dropped, got %d", len(deployments))
+	}
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if len(mock.calls) != 1 {
+		t.Fatalf("expected paging to stop at since, got calls %v", mock.calls)
+	}
+}
+
+func TestListDeploymentStatusesOldestFirst(t *testing.T) {
+	at := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	path := "repos/org/repo/deployments/5/statuses?per_page=100&page=1"
+	client := &Client{rest: newMockREST(map[string]interface{}{path: []deploymentStatusJSON{
+		{State: "success", CreatedAt: at.Add(time.Hour)},
+		{State: "waiting", CreatedAt: at},
+	}})}
+
+	statuses, err := client.ListDeploymentStatuses(nil, "org", "repo", 5)
+	if err != nil {
+		t.Fatalf("ListDeploymentStatuses failed: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].State != "waiting" || statuses[1].State != "success" {
+		t.Fatalf("unexpected statuses %+v", statuses)
+	}
+}
+
+func TestListCommitsMapsAuthorAndMessage(t *testing.T) {
+	since := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	path := "repos/org/repo/commits?page=1&path=.github%2Fworkflows%2Fci.yml&per_page=100&since=2025-07-01T00%3A00%3A00Z&until=2025-07-02T00%3A00%3A00Z"
+	payload := `[
+		{"sha": "abc", "commit": {"message": "Cache Go modules\n\nDetails", "author": {"name": "Octo Cat"}, "committer": {"date": "2025-07-01T11:00:00+02:00"}}, "author": {"login": "octocat"}},
+		{"sha": "def", "commit": {"message": "Bump setup-go", "author": {"name": "Jane Doe"}, "committer": {"date": "2025-07-01T08:00:00Z"}}, "author": null}
+	]`
+	var raw []commitJSON
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	client := &Client{rest: newMockREST(map[string]interface{}{path: raw})}
+
+	commits, err := client.ListCommits(nil, "org", "repo", ".github/workflows/ci.yml", since, until)
+	if err != nil {
+		t.Fatalf("ListCommits failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("unexpected commits %+v", commits)
+	}
+	if first := commits[0]; first.Message != "Cache Go modules" || first.Author != "octocat" || !first.CommittedAt.Equal(since.Add(9*time.Hour)) {
+		t.Fatalf("unexpected first commit %+v", first)
+	}
+	if commits[1].Author != "Jane Doe" {
+		t.Fatalf("expected git author name without a linked account, got %q", commits[1].Author)
+	}
+}
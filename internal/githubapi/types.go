@@ -1,6 +1,9 @@
 package githubapi
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type workflowListResponse struct {
 	TotalCount int              `json:"total_count"`
@@ -14,29 +17,136 @@ type workflowRecord struct {
 	State string `json:"state"`
 }
 
+type repositoryJSON struct {
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type contentJSON struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+type runnersResponse struct {
+	TotalCount int          `json:"total_count"`
+	Runners    []runnerJSON `json:"runners"`
+}
+
+type runnerJSON struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	OS     string `json:"os"`
+	Status string `json:"status"`
+	Busy   bool   `json:"busy"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type artifactsResponse struct {
+	TotalCount int            `json:"total_count"`
+	Artifacts  []artifactJSON `json:"artifacts"`
+}
+
+type artifactJSON struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	SizeInBytes int64      `json:"size_in_bytes"`
+	Expired     bool       `json:"expired"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	WorkflowRun *struct {
+		ID         int64  `json:"id"`
+		HeadBranch string `json:"head_branch"`
+	} `json:"workflow_run"`
+}
+
+type actionsCachesResponse struct {
+	TotalCount    int                `json:"total_count"`
+	ActionsCaches []actionsCacheJSON `json:"actions_caches"`
+}
+
+type actionsCacheJSON struct {
+	ID             int64      `json:"id"`
+	Ref            string     `json:"ref"`
+	Key            string     `json:"key"`
+	SizeInBytes    int64      `json:"size_in_bytes"`
+	CreatedAt      *time.Time `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+}
+
+type deploymentJSON struct {
+	ID          int64     `json:"id"`
+	SHA         string    `json:"sha"`
+	Ref         string    `json:"ref"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type deploymentStatusJSON struct {
+	State       string    `json:"state"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
+	LogURL      string    `json:"log_url"`
+	TargetURL   string    `json:"target_url"`
+}
+
+type commitJSON struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+type annotationJSON struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	Level     string `json:"annotation_level"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+}
+
 type workflowRunsResponse struct {
 	TotalCount   int               `json:"total_count"`
 	WorkflowRuns []workflowRunJSON `json:"workflow_runs"`
 }
 
 type workflowRunJSON struct {
-	ID              int64      `json:"id"`
-	Name            string     `json:"name"`
-	DisplayTitle    string     `json:"display_title"`
-	Event           string     `json:"event"`
-	Status          string     `json:"status"`
-	Conclusion      string     `json:"conclusion"`
-	CreatedAt       time.Time  `json:"created_at"`
-	RunStartedAt    *time.Time `json:"run_started_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	RunNumber       int        `json:"run_number"`
-	RunAttempt      int        `json:"run_attempt"`
-	RunDuration     int64      `json:"run_duration_ms"`
-	WorkflowID      int64      `json:"workflow_id"`
-	HeadBranch      string     `json:"head_branch"`
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	DisplayTitle string     `json:"display_title"`
+	Event        string     `json:"event"`
+	Status       string     `json:"status"`
+	Conclusion   string     `json:"conclusion"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RunStartedAt *time.Time `json:"run_started_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	RunNumber    int        `json:"run_number"`
+	RunAttempt   int        `json:"run_attempt"`
+	RunDuration  int64      `json:"run_duration_ms"`
+	WorkflowID   int64      `json:"workflow_id"`
+	HeadBranch   string     `json:"head_branch"`
+	HeadSHA      string     `json:"head_sha"`
+	PullRequests []struct {
+		Number int `json:"number"`
+	} `json:"pull_requests"`
 	TriggeringActor *struct {
 		Login string `json:"login"`
+		Type  string `json:"type"`
 	} `json:"triggering_actor"`
+	HeadCommit *struct {
+		Timestamp *time.Time `json:"timestamp"`
+	} `json:"head_commit"`
 }
 
 type workflowJobsResponse struct {
@@ -49,11 +159,22 @@ type workflowJobJSON struct {
 	Name        string     `json:"name"`
 	Status      string     `json:"status"`
 	Conclusion  string     `json:"conclusion"`
+	CreatedAt   *time.Time `json:"created_at"`
 	StartedAt   *time.Time `json:"started_at"`
 	CompletedAt *time.Time `json:"completed_at"`
 	RunnerName  string     `json:"runner_name"`
 	RunnerID    int64      `json:"runner_id"`
 	Labels      []string   `json:"labels"`
+	Steps       []stepJSON `json:"steps"`
+}
+
+type stepJSON struct {
+	Name        string     `json:"name"`
+	Number      int        `json:"number"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
 }
 
 // Workflow is a simplified workflow descriptor.
@@ -64,23 +185,112 @@ type Workflow struct {
 	State string
 }
 
+// Repository is a simplified repository descriptor.
+type Repository struct {
+	FullName      string
+	DefaultBranch string
+}
+
+// Runner represents a self-hosted runner registered with a repository or
+// organization.
+type Runner struct {
+	ID     int64
+	Name   string
+	OS     string
+	Status string
+	Busy   bool
+	Labels []string
+}
+
 // WorkflowRun represents a workflow run.
 type WorkflowRun struct {
-	ID              int64
-	Name            string
-	DisplayTitle    string
-	Event           string
-	Status          string
-	Conclusion      string
-	CreatedAt       time.Time
-	RunStartedAt    time.Time
-	UpdatedAt       time.Time
-	RunNumber       int
-	RunAttempt      int
-	Duration        time.Duration
-	WorkflowID      int64
-	HeadBranch      string
+	ID           int64
+	Name         string
+	DisplayTitle string
+	Event        string
+	Status       string
+	Conclusion   string
+	CreatedAt    time.Time
+	RunStartedAt time.Time
+	UpdatedAt    time.Time
+	RunNumber    int
+	RunAttempt   int
+	Duration     time.Duration
+	WorkflowID   int64
+	HeadBranch   string
+	HeadSHA      string
+	// PullRequests are the numbers of the pull requests the run belongs to.
+	// GitHub leaves them empty for pull requests from forks.
+	PullRequests    []int
 	TriggeringActor string
+	// TriggeringActorType is the account type of the triggering actor, such
+	// as "User" or "Bot".
+	TriggeringActorType string
+	// HeadCommitAt is the commit timestamp of the head commit.
+	HeadCommitAt time.Time
+}
+
+// Artifact is a file uploaded by a workflow run.
+type Artifact struct {
+	ID        int64
+	Name      string
+	Size      int64
+	Expired   bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RunID     int64
+	Branch    string
+}
+
+// ActionsCache is an entry of the repository's Actions cache.
+type ActionsCache struct {
+	ID             int64
+	Ref            string
+	Key            string
+	Size           int64
+	CreatedAt      time.Time
+	LastAccessedAt time.Time
+}
+
+// Deployment is a request to deploy a commit to an environment.
+type Deployment struct {
+	ID          int64
+	SHA         string
+	Ref         string
+	Environment string
+	CreatedAt   time.Time
+}
+
+// DeploymentStatus is a state change of a deployment. Deployments created by
+// workflow jobs report waiting while protection rules await approval.
+type DeploymentStatus struct {
+	State       string
+	Environment string
+	CreatedAt   time.Time
+	LogURL      string
+	TargetURL   string
+}
+
+// Commit is a commit on the default branch of a repository.
+type Commit struct {
+	SHA string
+	// Message is the first line of the commit message.
+	Message string
+	// Author is the GitHub login of the author, or the git author name when
+	// the commit is not linked to an account.
+	Author      string
+	CommittedAt time.Time
+}
+
+// Annotation is a message a check run attached to a file location, such as
+// an error reported through a workflow command.
+type Annotation struct {
+	Path      string
+	StartLine int
+	// Level is notice, warning or failure.
+	Level   string
+	Title   string
+	Message string
 }
 
 // WorkflowJob represents a job in a workflow run.
@@ -89,11 +299,34 @@ type WorkflowJob struct {
 	Name        string
 	Status      string
 	Conclusion  string
+	CreatedAt   time.Time
 	StartedAt   time.Time
 	CompletedAt time.Time
 	RunnerName  string
 	RunnerID    int64
 	Labels      []string
+	Steps       []WorkflowStep
+}
+
+// WorkflowStep represents a step of a workflow job.
+type WorkflowStep struct {
+	Name        string
+	Number      int
+	Status      string
+	Conclusion  string
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Duration computes the step duration.
+func (s WorkflowStep) Duration() time.Duration {
+	if s.StartedAt.IsZero() || s.CompletedAt.IsZero() {
+		return 0
+	}
+	if s.CompletedAt.Before(s.StartedAt) {
+		return 0
+	}
+	return s.CompletedAt.Sub(s.StartedAt)
 }
 
 // Duration computes the job duration.
@@ -107,6 +340,17 @@ func (j WorkflowJob) Duration() time.Duration {
 	return j.CompletedAt.Sub(j.StartedAt)
 }
 
+// QueueTime computes how long the job waited for a runner.
+func (j WorkflowJob) QueueTime() time.Duration {
+	if j.CreatedAt.IsZero() || j.StartedAt.IsZero() {
+		return 0
+	}
+	if j.StartedAt.Before(j.CreatedAt) {
+		return 0
+	}
+	return j.StartedAt.Sub(j.CreatedAt)
+}
+
 func mapWorkflowRun(run workflowRunJSON) WorkflowRun {
 	var start time.Time
 	if run.RunStartedAt != nil {
@@ -117,27 +361,84 @@ func mapWorkflowRun(run workflowRunJSON) WorkflowRun {
 		duration = run.UpdatedAt.Sub(start)
 	}
 
-	var triggeringActor string
+	var pullRequests []int
+	for _, pr := range run.PullRequests {
+		pullRequests = append(pullRequests, pr.Number)
+	}
+
+	var triggeringActor, triggeringActorType string
 	if run.TriggeringActor != nil {
 		triggeringActor = run.TriggeringActor.Login
+		triggeringActorType = run.TriggeringActor.Type
+	}
+
+	var headCommitAt time.Time
+	if run.HeadCommit != nil && run.HeadCommit.Timestamp != nil {
+		headCommitAt = run.HeadCommit.Timestamp.UTC()
 	}
 
 	return WorkflowRun{
-		ID:              run.ID,
-		Name:            run.Name,
-		DisplayTitle:    run.DisplayTitle,
-		Event:           run.Event,
-		Status:          run.Status,
-		Conclusion:      run.Conclusion,
-		CreatedAt:       run.CreatedAt.UTC(),
-		RunStartedAt:    start,
-		UpdatedAt:       run.UpdatedAt.UTC(),
-		RunNumber:       run.RunNumber,
-		RunAttempt:      run.RunAttempt,
-		Duration:        duration,
-		WorkflowID:      run.WorkflowID,
-		HeadBranch:      run.HeadBranch,
-		TriggeringActor: triggeringActor,
+		ID:                  run.ID,
+		Name:                run.Name,
+		DisplayTitle:        run.DisplayTitle,
+		Event:               run.Event,
+		Status:              run.Status,
+		Conclusion:          run.Conclusion,
+		CreatedAt:           run.CreatedAt.UTC(),
+		RunStartedAt:        start,
+		UpdatedAt:           run.UpdatedAt.UTC(),
+		RunNumber:           run.RunNumber,
+		RunAttempt:          run.RunAttempt,
+		Duration:            duration,
+		WorkflowID:          run.WorkflowID,
+		HeadBranch:          run.HeadBranch,
+		HeadSHA:             run.HeadSHA,
+		PullRequests:        pullRequests,
+		TriggeringActor:     triggeringActor,
+		TriggeringActorType: triggeringActorType,
+		HeadCommitAt:        headCommitAt,
+	}
+}
+
+func mapRunner(runner runnerJSON) Runner {
+	labels := make([]string, 0, len(runner.Labels))
+	for _, label := range runner.Labels {
+		labels = append(labels, label.Name)
+	}
+	return Runner{
+		ID:     runner.ID,
+		Name:   runner.Name,
+		OS:     runner.OS,
+		Status: runner.Status,
+		Busy:   runner.Busy,
+		Labels: labels,
+	}
+}
+
+func mapArtifact(artifact artifactJSON) Artifact {
+	out := Artifact{
+		ID:        artifact.ID,
+		Name:      artifact.Name,
+		Size:      artifact.SizeInBytes,
+		Expired:   artifact.Expired,
+		CreatedAt: derefTime(artifact.CreatedAt),
+		ExpiresAt: derefTime(artifact.ExpiresAt),
+	}
+	if artifact.WorkflowRun != nil {
+		out.RunID = artifact.WorkflowRun.ID
+		out.Branch = artifact.WorkflowRun.HeadBranch
+	}
+	return out
+}
+
+func mapActionsCache(entry actionsCacheJSON) ActionsCache {
+	return ActionsCache{
+		ID:             entry.ID,
+		Ref:            entry.Ref,
+		Key:            entry.Key,
+		Size:           entry.SizeInBytes,
+		CreatedAt:      derefTime(entry.CreatedAt),
+		LastAccessedAt: derefTime(entry.LastAccessedAt),
 	}
 }
 
@@ -147,17 +448,51 @@ func mapWorkflowJob(job workflowJobJSON) WorkflowJob {
 		Name:        job.Name,
 		Status:      job.Status,
 		Conclusion:  job.Conclusion,
+		CreatedAt:   derefTime(job.CreatedAt),
 		StartedAt:   derefTime(job.StartedAt),
 		CompletedAt: derefTime(job.CompletedAt),
 		RunnerName:  job.RunnerName,
 		RunnerID:    job.RunnerID,
 		Labels:      append([]string(nil), job.Labels...),
+		Steps:       mapWorkflowSteps(job.Steps),
 	}
 }
 
+func mapWorkflowSteps(steps []stepJSON) []WorkflowStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	out := make([]WorkflowStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, WorkflowStep{
+			Name:        step.Name,
+			Number:      step.Number,
+			Status:      step.Status,
+			Conclusion:  step.Conclusion,
+			StartedAt:   derefTime(step.StartedAt),
+			CompletedAt: derefTime(step.CompletedAt),
+		})
+	}
+	return out
+}
+
 func derefTime(t *time.Time) time.Time {
 	if t == nil {
 		return time.Time{}
 	}
 	return t.UTC()
 }
+
+func mapCommit(commit commitJSON) Commit {
+	message, _, _ := strings.Cut(commit.Commit.Message, "\n")
+	author := commit.Commit.Author.Name
+	if commit.Author != nil && commit.Author.Login != "" {
+		author = commit.Author.Login
+	}
+	return Commit{
+		SHA:         commit.SHA,
+		Message:     strings.TrimSpace(message),
+		Author:      author,
+		CommittedAt: commit.Commit.Committer.Date.UTC(),
+	}
+}
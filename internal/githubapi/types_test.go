@@ -1,6 +1,7 @@
 package githubapi
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -32,21 +33,72 @@ func TestMapWorkflowRunDurationFallback(t *testing.T) {
 func TestMapWorkflowJob(t *testing.T) {
 	start := time.Date(2025, 5, 2, 10, 0, 0, 0, time.UTC)
 	end := start.Add(30 * time.Minute)
+	created := start.Add(-2 * time.Minute)
 	json := workflowJobJSON{
 		ID:          99,
 		Name:        "job",
 		Status:      "completed",
 		Conclusion:  "failure",
+		CreatedAt:   &created,
 		StartedAt:   &start,
 		CompletedAt: &end,
 		Labels:      []string{"ubuntu-latest"},
+		Steps: []stepJSON{
+			{Name: "Set up job", Number: 1, Conclusion: "success", StartedAt: &start, CompletedAt: &end},
+			{Name: "Post checkout", Number: 2, Conclusion: "skipped"},
+		},
 	}
 
 	job := mapWorkflowJob(json)
 	if job.Duration() != 30*time.Minute {
 		t.Fatalf("expected duration 30m, got %s", job.Duration())
 	}
+	if job.QueueTime() != 2*time.Minute {
+		t.Fatalf("expected queue time 2m, got %s", job.QueueTime())
+	}
 	if job.Name != "job" || job.Conclusion != "failure" {
 		t.Fatalf("unexpected job mapping %#v", job)
 	}
+	if len(job.Steps) != 2 || job.Steps[0].Duration() != 30*time.Minute || job.Steps[1].Duration() != 0 {
+		t.Fatalf("unexpected step mapping %#v", job.Steps)
+	}
+}
+
+func TestMapWorkflowRunPullRequests(t *testing.T) {
+	var raw workflowRunJSON
+	payload := `{"id": 1, "head_sha": "abc", "pull_requests": [{"number": 12, "head": {"ref": "feature"}}, {"number": 15}]}`
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	run := mapWorkflowRun(raw)
+	if run.HeadSHA != "abc" || len(run.PullRequests) != 2 || run.PullRequests[0] != 12 || run.PullRequests[1] != 15 {
+		t.Fatalf("unexpected pull requests %+v", run)
+	}
+}
+
+func TestMapWorkflowRunHeadCommit(t *testing.T) {
+	var raw workflowRunJSON
+	payload := `{"id": 1, "head_sha": "abc", "head_commit": {"timestamp": "2025-07-01T09:00:00+02:00"}}`
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	run := mapWorkflowRun(raw)
+	if !run.HeadCommitAt.Equal(time.Date(2025, 7, 1, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected head commit time %s", run.HeadCommitAt)
+	}
+}
+
+func TestMapWorkflowRunTriggeringActor(t *testing.T) {
+	var raw workflowRunJSON
+	payload := `{"id": 1, "triggering_actor": {"login": "dependabot[bot]", "type": "Bot"}}`
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	run := mapWorkflowRun(raw)
+	if run.TriggeringActor != "dependabot[bot]" || run.TriggeringActorType != "Bot" {
+		t.Fatalf("unexpected triggering actor %q (%q)", run.TriggeringActor, run.TriggeringActorType)
+	}
 }
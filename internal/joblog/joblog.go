@@ -0,0 +1,148 @@
+// Package joblog parses the plain-text logs GitHub Actions keeps for each job
+// so they can be searched and attributed to the steps that wrote them.
+package joblog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// Line is one line of a job log.
+type Line struct {
+	Number int       `json:"number"`
+	Time   time.Time `json:"time"`
+	Text   string    `json:"text"`
+}
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// Parse splits a job log into lines, removing the timestamp GitHub prefixes to
+// every line and ANSI color sequences. Lines without a timestamp inherit the
+// time of the previous line.
+func Parse(data []byte) []Line {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+
+	raw := strings.Split(text, "\n")
+	lines := make([]Line, 0, len(raw))
+	var last time.Time
+	for i, r := range raw {
+		line := Line{Number: i + 1, Time: last, Text: r}
+		stamp, rest, _ := strings.Cut(r, " ")
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			line.Time = t.UTC()
+			line.Text = rest
+			last = line.Time
+		}
+		line.Text = ansiPattern.ReplaceAllString(line.Text, "")
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// IsError reports whether the line was written by an error workflow command.
+func (l Line) IsError() bool {
+	return strings.HasPrefix(l.Text, "##[error]")
+}
+
+// Display rewrites the workflow command markers of a log line the way the
+// GitHub web UI shows them.
+func Display(text string) string {
+	switch {
+	case strings.HasPrefix(text, "##[error]"):
+		return "Error: " + strings.TrimPrefix(text, "##[error]")
+	case strings.HasPrefix(text, "##[warning]"):
+		return "Warning: " + strings.TrimPrefix(text, "##[warning]")
+	case strings.HasPrefix(text, "##[group]"):
+		return strings.TrimPrefix(text, "##[group]")
+	case text == "##[endgroup]":
+		return ""
+	}
+	return text
+}
+
+// StepAt returns the step that was running at t. Steps report whole-second
+// times, so t is truncated before comparing, and the earlier step wins when
+// one step completes in the second the next one starts.
+func StepAt(steps []githubapi.WorkflowStep, t time.Time) (githubapi.WorkflowStep, bool) {
+	if t.IsZero() {
+		return githubapi.WorkflowStep{}, false
+	}
+	t = t.Truncate(time.Second)
+	for _, step := range steps {
+		if step.StartedAt.IsZero() || step.CompletedAt.IsZero() {
+			continue
+		}
+		if !t.Before(step.StartedAt.Truncate(time.Second)) && !t.After(step.CompletedAt) {
+			return step, true
+		}
+	}
+	return githubapi.WorkflowStep{}, false
+}
+
+// StepLines returns the lines written while step was running.
+func StepLines(lines []Line, steps []githubapi.WorkflowStep, step githubapi.WorkflowStep) []Line {
+	var out []Line
+	for _, line := range lines {
+		if s, ok := StepAt(steps, line.Time); ok && s.Number == step.Number {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// ErrorTail returns up to n displayable lines ending at the last error line,
+// or at the end of lines when no error was logged.
+func ErrorTail(lines []Line, n int) []Line {
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].IsError() {
+			end = i + 1
+			break
+		}
+	}
+
+	var tail []Line
+	for i := end - 1; i >= 0 && len(tail) < n; i-- {
+		if strings.TrimSpace(Display(lines[i].Text)) == "" {
+			continue
+		}
+		tail = append(tail, lines[i])
+	}
+	for i, j := 0, len(tail)-1; i < j; i, j = i+1, j-1 {
+		tail[i], tail[j] = tail[j], tail[i]
+	}
+	return tail
+}
+
+// Match is a log line matching a search pattern with the lines around it.
+type Match struct {
+	Line   Line   `json:"line"`
+	Before []Line `json:"before,omitempty"`
+	After  []Line `json:"after,omitempty"`
+}
+
+// Grep returns the lines whose text matches re, each with up to context
+// lines before and after it.
+func Grep(lines []Line, re *regexp.Regexp, context int) []Match {
+	var matches []Match
+	for i, line := range lines {
+		if !re.MatchString(line.Text) {
+			continue
+		}
+		match := Match{Line: line}
+		if context > 0 {
+			match.Before = append([]Line(nil), lines[max(0, i-context):i]...)
+			match.After = append([]Line(nil), lines[i+1:min(len(lines), i+1+context)]...)
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
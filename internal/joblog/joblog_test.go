@@ -0,0 +1,74 @@
+package joblog
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestParseStripsTimestampsAndColors(t *testing.T) {
+	data := []byte("\ufeff2025-07-01T09:00:00.1234567Z ##[group]Run make test\r\n" +
+		"2025-07-01T09:00:01.5000000Z \x1b[31mFAIL\x1b[0m pkg\n" +
+		"continued\n")
+
+	lines := Parse(data)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	if lines[0].Text != "##[group]Run make test" || lines[0].Number != 1 {
+		t.Fatalf("unexpected first line %+v", lines[0])
+	}
+	if lines[1].Text != "FAIL pkg" || !lines[1].Time.Equal(time.Date(2025, 7, 1, 9, 0, 1, 500000000, time.UTC)) {
+		t.Fatalf("unexpected second line %+v", lines[1])
+	}
+	if lines[2].Text != "continued" || !lines[2].Time.Equal(lines[1].Time) {
+		t.Fatalf("expected untimed line to inherit the previous time, got %+v", lines[2])
+	}
+}
+
+func TestErrorTailEndsAtFailingStepError(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	steps := []githubapi.WorkflowStep{
+		{Name: "Checkout", Number: 1, StartedAt: base, CompletedAt: base.Add(2 * time.Second)},
+		{Name: "Test", Number: 2, StartedAt: base.Add(2 * time.Second), CompletedAt: base.Add(5 * time.Second)},
+		{Name: "Post job cleanup.", Number: 3, StartedAt: base.Add(5 * time.Second), CompletedAt: base.Add(6 * time.Second)},
+	}
+	line := func(offset time.Duration, text string) Line {
+		return Line{Time: base.Add(offset), Text: text}
+	}
+	lines := []Line{
+		line(time.Second, "Fetching the repository"),
+		line(3*time.Second, "##[group]Run go test ./..."),
+		line(3*time.Second, "##[endgroup]"),
+		line(4*time.Second, "--- FAIL: TestThing (0.01s)"),
+		line(5*time.Second+100*time.Millisecond, "##[error]Process completed with exit code 1."),
+		line(5*time.Second+200*time.Millisecond, "Cleaning up orphan processes"),
+	}
+
+	if step, ok := StepAt(steps, lines[4].Time); !ok || step.Name != "Test" {
+		t.Fatalf("expected error line to belong to Test, got %+v", step)
+	}
+
+	tail := ErrorTail(StepLines(lines, steps, steps[1]), 2)
+	if len(tail) != 2 || tail[0].Text != "--- FAIL: TestThing (0.01s)" || Display(tail[1].Text) != "Error: Process completed with exit code 1." {
+		t.Fatalf("unexpected tail %+v", tail)
+	}
+}
+
+func TestGrepIncludesContext(t *testing.T) {
+	lines := Parse([]byte("one\ntwo warning\nthree\nfour\nfive warning\n"))
+
+	matches := Grep(lines, regexp.MustCompile(`warn`), 1)
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	first, last := matches[0], matches[1]
+	if first.Line.Number != 2 || len(first.Before) != 1 || first.Before[0].Text != "one" || len(first.After) != 1 || first.After[0].Text != "three" {
+		t.Fatalf("unexpected first match %+v", first)
+	}
+	if last.Line.Number != 5 || len(last.Before) != 1 || len(last.After) != 0 {
+		t.Fatalf("unexpected last match %+v", last)
+	}
+}
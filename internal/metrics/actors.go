@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actor kinds reported in AttributionRow.Kind.
+const (
+	ActorKindBot     = "bot"
+	ActorKindHuman   = "human"
+	ActorKindUnknown = "unknown"
+)
+
+// UnknownActor names runs without a triggering actor.
+const UnknownActor = "(unknown)"
+
+// knownBots are automation accounts that may trigger runs under a login
+// without the [bot] suffix, such as self-hosted Renovate.
+var knownBots = map[string]struct{}{
+	"dependabot":     {},
+	"renovate":       {},
+	"renovate-bot":   {},
+	"github-actions": {},
+}
+
+// ActorKind classifies the triggering actor of a run as a bot or a human.
+// Accounts of type Bot, logins ending in [bot] and well-known automation
+// accounts are bots.
+func ActorKind(login, accountType string) string {
+	if login == "" {
+		return ActorKindUnknown
+	}
+	if strings.EqualFold(accountType, "bot") || strings.HasSuffix(login, "[bot]") {
+		return ActorKindBot
+	}
+	if _, ok := knownBots[strings.ToLower(login)]; ok {
+		return ActorKindBot
+	}
+	return ActorKindHuman
+}
+
+// AttributionRow aggregates the runs attributed to one actor, actor kind or
+// triggering event.
+type AttributionRow struct {
+	Name string `json:"name"`
+	// Kind is set on actor rows.
+	Kind        string  `json:"kind,omitempty"`
+	Runs        int     `json:"runs"`
+	Failed      int     `json:"failed"`
+	FailureRate float64 `json:"failure_rate"`
+	// CITime is the runner time of the runs; Share is its fraction of the
+	// total.
+	CITime time.Duration `json:"ci_time"`
+	Share  float64       `json:"share"`
+	// Events lists the triggering events of an actor's runs, most used first.
+	Events []string `json:"events,omitempty"`
+}
+
+// AttributionReport breaks CI usage down by who and what triggered the runs.
+type AttributionReport struct {
+	Runs    int              `json:"runs"`
+	CITime  time.Duration    `json:"ci_time"`
+	ByKind  []AttributionRow `json:"by_kind"`
+	ByActor []AttributionRow `json:"by_actor"`
+	ByEvent []AttributionRow `json:"by_event"`
+}
+
+// Attribution groups the runs started within [from, to] by triggering actor,
+// by bot versus human, and by triggering event. Rows are ordered by CI time,
+// largest first.
+func Attribution(records []RunRecord, from, to time.Time, policy FailurePolicy) AttributionReport {
+	type attrStat struct {
+		row    AttributionRow
+		events map[string]int
+	}
+	kinds := make(map[string]*attrStat)
+	actors := make(map[string]*attrStat)
+	events := make(map[string]*attrStat)
+	add := func(stats map[string]*attrStat, name string, rec RunRecord, runTime time.Duration, failed bool) *attrStat {
+		st, ok := stats[name]
+		if !ok {
+			st = &attrStat{row: AttributionRow{Name: name}, events: make(map[string]int)}
+			stats[name] = st
+		}
+		st.row.Runs++
+		st.row.CITime += runTime
+		if failed {
+			st.row.Failed++
+		}
+		st.events[rec.Run.Event]++
+		return st
+	}
+
+	var report AttributionReport
+	for _, rec := range records {
+		started := rec.Run.RunStartedAt
+		if started.IsZero() {
+			started = rec.Run.CreatedAt
+		}
+		if started.Before(from) || started.After(to) {
+			continue
+		}
+
+		runTime := runnerTime(rec)
+		failed := policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status)
+		report.Runs++
+		report.CITime += runTime
+
+		kind := ActorKind(rec.Run.TriggeringActor, rec.Run.TriggeringActorType)
+		actor := rec.Run.TriggeringActor
+		if actor == "" {
+			actor = UnknownActor
+		}
+		add(kinds, kind, rec, runTime, failed)
+		add(actors, actor, rec, runTime, failed).row.Kind = kind
+		add(events, rec.Run.Event, rec, runTime, failed)
+	}
+
+	rows := func(stats map[string]*attrStat, withEvents bool) []AttributionRow {
+		out := make([]AttributionRow, 0, len(stats))
+		for _, st := range stats {
+			row := st.row
+			if row.Runs > 0 {
+				row.FailureRate = float64(row.Failed) / float64(row.Runs)
+			}
+			if report.CITime > 0 {
+				row.Share = float64(row.CITime) / float64(report.CITime)
+			}
+			if withEvents {
+				for event := range st.events {
+					row.Events = append(row.Events, event)
+				}
+				sort.Slice(row.Events, func(i, j int) bool {
+					a, b := row.Events[i], row.Events[j]
+					if st.events[a] != st.events[b] {
+						return st.events[a] > st.events[b]
+					}
+					return a < b
+				})
+			}
+			out = append(out, row)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].CITime != out[j].CITime {
+				return out[i].CITime > out[j].CITime
+			}
+			return out[i].Name < out[j].Name
+		})
+		return out
+	}
+
+	report.ByKind = rows(kinds, false)
+	report.ByActor = rows(actors, true)
+	report.ByEvent = rows(events, false)
+	return report
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestActorKind(t *testing.T) {
+	cases := []struct {
+		login, accountType, want string
+	}{
+		{"dependabot[bot]", "Bot", ActorKindBot},
+		{"renovate[bot]", "", ActorKindBot},
+		{"github-actions", "", ActorKindBot},
+		{"ci-automation", "Bot", ActorKindBot},
+		{"octocat", "User", ActorKindHuman},
+		{"", "", ActorKindUnknown},
+	}
+	for _, tc := range cases {
+		if got := ActorKind(tc.login, tc.accountType); got != tc.want {
+			t.Errorf("ActorKind(%q, %q) = %q, want %q", tc.login, tc.accountType, got, tc.want)
+		}
+	}
+}
+
+func TestAttribution(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	record := func(actor, event, conclusion string, duration time.Duration) RunRecord {
+		return RunRecord{Run: githubapi.WorkflowRun{
+			TriggeringActor: actor,
+			Event:           event,
+			Status:          "completed",
+			Conclusion:      conclusion,
+			CreatedAt:       base,
+			Duration:        duration,
+		}}
+	}
+
+	records := []RunRecord{
+		record("dependabot[bot]", "pull_request", "success", 30*time.Minute),
+		record("dependabot[bot]", "pull_request", "failure", 20*time.Minute),
+		record("dependabot[bot]", "push", "success", 10*time.Minute),
+		record("octocat", "push", "success", 30*time.Minute),
+		record("", "schedule", "success", 10*time.Minute),
+		// Outside the window.
+		{Run: githubapi.WorkflowRun{TriggeringActor: "octocat", CreatedAt: base.Add(-48 * time.Hour), Duration: time.Hour}},
+	}
+
+	report := Attribution(records, base.Add(-time.Hour), base.Add(time.Hour), FailurePolicy{})
+	if report.Runs != 5 || report.CITime != 100*time.Minute {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+
+	if len(report.ByKind) != 3 || report.ByKind[0].Name != ActorKindBot || report.ByKind[0].Share != 0.6 {
+		t.Fatalf("unexpected kinds %+v", report.ByKind)
+	}
+
+	bot := report.ByActor[0]
+	if bot.Name != "dependabot[bot]" || bot.Kind != ActorKindBot || bot.Runs != 3 || bot.Failed != 1 || bot.CITime != time.Hour {
+		t.Fatalf("unexpected bot row %+v", bot)
+	}
+	if !reflect.DeepEqual(bot.Events, []string{"pull_request", "push"}) {
+		t.Fatalf("expected events by frequency, got %v", bot.Events)
+	}
+	if unknown := report.ByActor[2]; unknown.Name != UnknownActor || unknown.Kind != ActorKindUnknown {
+		t.Fatalf("unexpected unknown actor row %+v", unknown)
+	}
+
+	if len(report.ByEvent) != 3 || report.ByEvent[0].Name != "pull_request" || report.ByEvent[0].Runs != 2 {
+		t.Fatalf("unexpected events %+v", report.ByEvent)
+	}
+}
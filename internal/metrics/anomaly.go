@@ -0,0 +1,283 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// madScale converts a median absolute deviation into a consistent estimator of
+// the standard deviation for normally distributed data.
+const madScale = 1.4826
+
+// Alert flags a workflow or job whose recent runs deviate from its own history.
+type Alert struct {
+	Workflow   string    `json:"workflow"`
+	WorkflowID int64     `json:"workflow_id"`
+	Job        string    `json:"job,omitempty"`
+	Metric     string    `json:"metric"`
+	Baseline   float64   `json:"baseline"`
+	Recent     float64   `json:"recent"`
+	Score      float64   `json:"score,omitempty"`
+	Since      time.Time `json:"since"`
+}
+
+// Alert metrics.
+const (
+	AlertDuration    = "duration"
+	AlertFailureRate = "failure_rate"
+)
+
+// AnomalyOptions tunes anomaly detection.
+type AnomalyOptions struct {
+	// RecentRuns is the number of most recent runs compared against the
+	// preceding history.
+	RecentRuns int
+	// MinHistory is the minimum number of earlier runs required to form a
+	// baseline.
+	MinHistory int
+	// Threshold is the robust z-score (median/MAD based) at which a duration
+	// shift is flagged.
+	Threshold float64
+	// MinChange is the minimum relative change in median duration that is
+	// reported, which keeps very stable series from alerting on noise.
+	MinChange float64
+	// FailureRateIncrease is the minimum absolute increase in failure rate
+	// that is reported.
+	FailureRateIncrease float64
+	// Failure decides which conclusions count as failures.
+	Failure FailurePolicy
+}
+
+// DefaultAnomalyOptions returns the options used by the summary command.
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{
+		RecentRuns:          5,
+		MinHistory:          10,
+		Threshold:           3.5,
+		MinChange:           0.2,
+		FailureRateIncrease: 0.25,
+	}
+}
+
+type seriesPoint struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+type seriesKey struct {
+	workflowID int64
+	job        string
+}
+
+// DetectAnomalies compares the most recent runs started within [from, to] of
+// every workflow and job with their earlier runs and returns alerts for
+// significant regressions in duration or failure rate, ordered by workflow and
+// job. Only slowdowns and failure rate increases are reported.
+func DetectAnomalies(records []RunRecord, from, to time.Time, opts AnomalyOptions) []Alert {
+	if opts.RecentRuns <= 0 || opts.Threshold <= 0 {
+		return nil
+	}
+
+	series := make(map[seriesKey][]seriesPoint)
+	names := make(map[int64]string)
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+		names[rec.Workflow.ID] = rec.Workflow.Name
+		key := seriesKey{workflowID: rec.Workflow.ID}
+		series[key] = append(series[key], seriesPoint{
+			at:       runTime,
+			duration: rec.Run.Duration,
+			failed:   opts.Failure.Failed(rec, rec.Run.Conclusion, rec.Run.Status),
+		})
+
+		for _, job := range rec.Jobs {
+			name := strings.TrimSpace(job.Name)
+			if name == "" {
+				name = "(unnamed)"
+			}
+			jobKey := seriesKey{workflowID: rec.Workflow.ID, job: name}
+			series[jobKey] = append(series[jobKey], seriesPoint{
+				at:       job.StartedAt,
+				duration: job.Duration(),
+				failed:   opts.Failure.Failed(rec, job.Conclusion, job.Status),
+			})
+		}
+	}
+
+	var alerts []Alert
+	for key, points := range series {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].at.Before(points[j].at) })
+		for _, alert := range detectSeriesAnomalies(points, opts) {
+			alert.Workflow = names[key.workflowID]
+			alert.WorkflowID = key.workflowID
+			alert.Job = key.job
+			alerts = append(alerts, alert)
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Workflow != alerts[j].Workflow {
+			return alerts[i].Workflow < alerts[j].Workflow
+		}
+		if alerts[i].Job != alerts[j].Job {
+			return alerts[i].Job < alerts[j].Job
+		}
+		return alerts[i].Metric < alerts[j].Metric
+	})
+	return alerts
+}
+
+// AttachAlerts assigns alerts to the summary rows of their workflows.
+func AttachAlerts(rows []SummaryRow, alerts []Alert) {
+	for i := range rows {
+		rows[i].Alerts = nil
+		for _, alert := range alerts {
+			if alert.WorkflowID == rows[i].WorkflowID {
+				rows[i].Alerts = append(rows[i].Alerts, alert)
+			}
+		}
+	}
+}
+
+func detectSeriesAnomalies(points []seriesPoint, opts AnomalyOptions) []Alert {
+	if len(points) < opts.RecentRuns+opts.MinHistory {
+		return nil
+	}
+	split := len(points) - opts.RecentRuns
+	baseline, recent := points[:split], points[split:]
+
+	var alerts []Alert
+	if alert, ok := detectDurationShift(baseline, recent, opts); ok {
+		alerts = append(alerts, alert)
+	}
+	if alert, ok := detectFailureShift(baseline, recent, opts); ok {
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+func detectDurationShift(baseline, recent []seriesPoint, opts AnomalyOptions) (Alert, bool) {
+	history := positiveDurations(baseline)
+	latest := positiveDurations(recent)
+	if len(history) < opts.MinHistory || len(latest) == 0 {
+		return Alert{}, false
+	}
+
+	baseMedian := median(history)
+	recentMedian := median(latest)
+	if baseMedian <= 0 {
+		return Alert{}, false
+	}
+
+	spread := madScale * mad(history, baseMedian)
+	// A perfectly stable history has no spread; fall back to a small fraction
+	// of the median so a genuine shift still yields a finite score.
+	if floor := baseMedian * 0.01; spread < floor {
+		spread = floor
+	}
+
+	// Speedups are not regressions, so only increases are flagged.
+	score := (recentMedian - baseMedian) / spread
+	change := (recentMedian - baseMedian) / baseMedian
+	if score < opts.Threshold || change < opts.MinChange {
+		return Alert{}, false
+	}
+
+	since := recent[0].at
+	for _, p := range recent {
+		if p.duration > 0 && (float64(p.duration)-baseMedian)/spread >= opts.Threshold {
+			since = p.at
+			break
+		}
+	}
+
+	return Alert{
+		Metric:   AlertDuration,
+		Baseline: baseMedian,
+		Recent:   recentMedian,
+		Score:    score,
+		Since:    since,
+	}, true
+}
+
+func detectFailureShift(baseline, recent []seriesPoint, opts AnomalyOptions) (Alert, bool) {
+	if opts.FailureRateIncrease <= 0 {
+		return Alert{}, false
+	}
+
+	baseRate := failureRate(baseline)
+	recentRate := failureRate(recent)
+	recentFailures := int(math.Round(recentRate * float64(len(recent))))
+	if recentFailures < 2 || recentRate-baseRate < opts.FailureRateIncrease {
+		return Alert{}, false
+	}
+
+	since := recent[0].at
+	for _, p := range recent {
+		if p.failed {
+			since = p.at
+			break
+		}
+	}
+
+	return Alert{
+		Metric:   AlertFailureRate,
+		Baseline: baseRate,
+		Recent:   recentRate,
+		Since:    since,
+	}, true
+}
+
+func positiveDurations(points []seriesPoint) []float64 {
+	out := make([]float64, 0, len(points))
+	for _, p := range points {
+		if p.duration > 0 {
+			out = append(out, float64(p.duration))
+		}
+	}
+	return out
+}
+
+func failureRate(points []seriesPoint) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	failed := 0
+	for _, p := range points {
+		if p.failed {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(points))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mad returns the median absolute deviation of values around center.
+func mad(values []float64, center float64) float64 {
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - center)
+	}
+	return median(deviations)
+}
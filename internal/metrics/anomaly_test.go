@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func anomalySeries(workflow githubapi.Workflow, base time.Time, durations []time.Duration, conclusions []string) []RunRecord {
+	records := make([]RunRecord, 0, len(durations))
+	for i, d := range durations {
+		conclusion := "success"
+		if conclusions != nil {
+			conclusion = conclusions[i]
+		}
+		start := base.Add(time.Duration(i) * time.Hour)
+		records = append(records, RunRecord{
+			Workflow: workflow,
+			Run: githubapi.WorkflowRun{
+				ID:         int64(i + 1),
+				WorkflowID: workflow.ID,
+				Status:     "completed",
+				Conclusion: conclusion,
+				CreatedAt:  start,
+				Duration:   d,
+			},
+			Jobs: []githubapi.WorkflowJob{{
+				Name:        "test",
+				Status:      "completed",
+				Conclusion:  conclusion,
+				StartedAt:   start,
+				CompletedAt: start.Add(d),
+			}},
+		})
+	}
+	return records
+}
+
+func TestDetectAnomaliesDurationRegression(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 7, Name: "build"}
+
+	var durations []time.Duration
+	for i := 0; i < 12; i++ {
+		durations = append(durations, 10*time.Minute+time.Duration(i%3)*10*time.Second)
+	}
+	for i := 0; i < 5; i++ {
+		durations = append(durations, 20*time.Minute)
+	}
+
+	alerts := DetectAnomalies(anomalySeries(workflow, base, durations, nil), base, base.Add(24*time.Hour), DefaultAnomalyOptions())
+	if len(alerts) != 2 {
+		t.Fatalf("expected workflow and job duration alerts, got %#v", alerts)
+	}
+	if alerts[0].Job != "" || alerts[0].Metric != AlertDuration {
+		t.Fatalf("expected workflow duration alert first, got %#v", alerts[0])
+	}
+	if alerts[1].Job != "test" {
+		t.Fatalf("expected job alert second, got %#v", alerts[1])
+	}
+	if time.Duration(alerts[0].Recent) != 20*time.Minute {
+		t.Fatalf("expected recent median 20m, got %s", time.Duration(alerts[0].Recent))
+	}
+	if want := base.Add(12 * time.Hour); !alerts[0].Since.Equal(want) {
+		t.Fatalf("expected regression since %s, got %s", want, alerts[0].Since)
+	}
+
+	rows := Aggregate(anomalySeries(workflow, base, durations, nil), base, base.Add(24*time.Hour))
+	AttachAlerts(rows, alerts)
+	if len(rows[0].Alerts) != 2 {
+		t.Fatalf("expected alerts attached to workflow row, got %#v", rows[0].Alerts)
+	}
+}
+
+func TestDetectAnomaliesIgnoresSpeedups(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 7, Name: "build"}
+
+	var durations []time.Duration
+	for i := 0; i < 12; i++ {
+		durations = append(durations, 10*time.Minute+time.Duration(i%3)*10*time.Second)
+	}
+	for i := 0; i < 5; i++ {
+		durations = append(durations, 6*time.Minute)
+	}
+
+	if alerts := DetectAnomalies(anomalySeries(workflow, base, durations, nil), base, base.Add(24*time.Hour), DefaultAnomalyOptions()); len(alerts) != 0 {
+		t.Fatalf("expected no alerts for a speedup, got %#v", alerts)
+	}
+}
+
+func TestDetectAnomaliesWindow(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 7, Name: "build"}
+
+	var durations []time.Duration
+	for i := 0; i < 12; i++ {
+		durations = append(durations, 10*time.Minute)
+	}
+	for i := 0; i < 5; i++ {
+		durations = append(durations, 20*time.Minute)
+	}
+
+	// Starting the window after the sixth run leaves too little history.
+	if alerts := DetectAnomalies(anomalySeries(workflow, base, durations, nil), base.Add(6*time.Hour), base.Add(24*time.Hour), DefaultAnomalyOptions()); len(alerts) != 0 {
+		t.Fatalf("expected runs outside the window to be ignored, got %#v", alerts)
+	}
+}
+
+func TestDetectAnomaliesStableSeries(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 7, Name: "build"}
+
+	var durations []time.Duration
+	for i := 0; i < 20; i++ {
+		durations = append(durations, 10*time.Minute+time.Duration(i%4)*30*time.Second)
+	}
+
+	if alerts := DetectAnomalies(anomalySeries(workflow, base, durations, nil), base, base.Add(24*time.Hour), DefaultAnomalyOptions()); len(alerts) != 0 {
+		t.Fatalf("expected no alerts for stable series, got %#v", alerts)
+	}
+}
+
+func TestDetectAnomaliesFailureRate(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 7, Name: "build"}
+
+	durations := make([]time.Duration, 15)
+	conclusions := make([]string, 15)
+	for i := range durations {
+		durations[i] = 10 * time.Minute
+		conclusions[i] = "success"
+	}
+	conclusions[11] = "failure"
+	conclusions[12] = "failure"
+	conclusions[14] = "failure"
+
+	opts := DefaultAnomalyOptions()
+	alerts := DetectAnomalies(anomalySeries(workflow, base, durations, conclusions), base, base.Add(24*time.Hour), opts)
+	if len(alerts) != 2 {
+		t.Fatalf("expected workflow and job failure alerts, got %#v", alerts)
+	}
+	if alerts[0].Metric != AlertFailureRate || alerts[0].Recent != 0.6 {
+		t.Fatalf("unexpected failure alert %#v", alerts[0])
+	}
+	if want := base.Add(11 * time.Hour); !alerts[0].Since.Equal(want) {
+		t.Fatalf("expected failures since %s, got %s", want, alerts[0].Since)
+	}
+
+	opts.Threshold = 0
+	if alerts := DetectAnomalies(anomalySeries(workflow, base, durations, conclusions), base, base.Add(24*time.Hour), opts); alerts != nil {
+		t.Fatalf("expected zero threshold to disable alerts, got %#v", alerts)
+	}
+}
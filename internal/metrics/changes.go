@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// ChangeStats summarizes the runs of a workflow on one side of a workflow
+// file change.
+type ChangeStats struct {
+	Runs        int           `json:"runs"`
+	Failed      int           `json:"failed"`
+	FailureRate float64       `json:"failure_rate"`
+	AvgDuration time.Duration `json:"avg_duration"`
+}
+
+// WorkflowChange is a commit that edited a workflow file, with the runs of
+// the workflow before and after it.
+type WorkflowChange struct {
+	SHA       string      `json:"sha"`
+	Message   string      `json:"message"`
+	Author    string      `json:"author"`
+	ChangedAt time.Time   `json:"changed_at"`
+	Before    ChangeStats `json:"before"`
+	After     ChangeStats `json:"after"`
+	// Trend compares the runs after the change with those before it; it is
+	// zero unless both sides have runs.
+	Trend Trend `json:"trend"`
+}
+
+// AttachWorkflowChanges sets the Changes of every workflow row from the
+// commits that edited its workflow file on branch, keyed by workflow ID. Each
+// change is compared over the runs on branch started between the previous
+// change (or from) and the next change (or to), so consecutive edits are
+// judged separately. Runs on other branches are ignored, since they may not
+// include the edit yet. Changes are ordered oldest first.
+func AttachWorkflowChanges(rows []SummaryRow, records []RunRecord, commits map[int64][]githubapi.Commit, branch string, from, to time.Time, policy FailurePolicy) {
+	byWorkflow := make(map[int64][]RunRecord)
+	for _, rec := range records {
+		if rec.Run.HeadBranch != branch {
+			continue
+		}
+		byWorkflow[rec.Workflow.ID] = append(byWorkflow[rec.Workflow.ID], rec)
+	}
+
+	for i := range rows {
+		changes := workflowChanges(byWorkflow[rows[i].WorkflowID], commits[rows[i].WorkflowID], from, to, policy)
+		if len(changes) > 0 {
+			rows[i].Changes = changes
+		}
+	}
+}
+
+func workflowChanges(records []RunRecord, commits []githubapi.Commit, from, to time.Time, policy FailurePolicy) []WorkflowChange {
+	var edits []githubapi.Commit
+	for _, commit := range commits {
+		if commit.CommittedAt.Before(from) || commit.CommittedAt.After(to) {
+			continue
+		}
+		edits = append(edits, commit)
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].CommittedAt.Before(edits[j].CommittedAt) })
+
+	changes := make([]WorkflowChange, 0, len(edits))
+	for i, edit := range edits {
+		start, end := from, to
+		if i > 0 {
+			start = edits[i-1].CommittedAt
+		}
+		if i+1 < len(edits) {
+			end = edits[i+1].CommittedAt
+		}
+
+		var halves [2]halfStat
+		for _, rec := range records {
+			runTime := rec.Run.RunStartedAt
+			if runTime.IsZero() {
+				runTime = rec.Run.CreatedAt
+			}
+			// Runs at the next change already belong to it.
+			if runTime.Before(start) || runTime.After(end) || (runTime.Equal(end) && i+1 < len(edits)) {
+				continue
+			}
+			halves[halfIndex(runTime, edit.CommittedAt)].add(rec.Run.Duration, policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status))
+		}
+
+		changes = append(changes, WorkflowChange{
+			SHA:       edit.SHA,
+			Message:   edit.Message,
+			Author:    edit.Author,
+			ChangedAt: edit.CommittedAt,
+			Before:    halves[0].changeStats(),
+			After:     halves[1].changeStats(),
+			Trend:     compareHalves(halves),
+		})
+	}
+	return changes
+}
+
+func (h halfStat) changeStats() ChangeStats {
+	stats := ChangeStats{Runs: h.runs, Failed: h.failed}
+	if h.runs > 0 {
+		stats.FailureRate = float64(h.failed) / float64(h.runs)
+		stats.AvgDuration = h.duration / time.Duration(h.runs)
+	}
+	return stats
+}
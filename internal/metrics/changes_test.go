@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestAttachWorkflowChanges(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	ci := githubapi.Workflow{ID: 1, Name: "ci"}
+	record := func(started time.Duration, duration time.Duration, conclusion string) RunRecord {
+		return RunRecord{Workflow: ci, Run: githubapi.WorkflowRun{
+			HeadBranch: "main", CreatedAt: base.Add(started), Duration: duration, Status: "completed", Conclusion: conclusion,
+		}}
+	}
+
+	records := []RunRecord{
+		record(1*time.Hour, 10*time.Minute, "success"),
+		record(2*time.Hour, 10*time.Minute, "success"),
+		// After the first edit.
+		record(25*time.Hour, 20*time.Minute, "success"),
+		record(26*time.Hour, 20*time.Minute, "failure"),
+		// After the second edit.
+		record(49*time.Hour, 15*time.Minute, "success"),
+	}
+	// A feature branch created before the first edit may still run the old
+	// workflow file, so it is left out.
+	feature := record(30*time.Hour, time.Hour, "failure")
+	feature.Run.HeadBranch = "feature"
+	records = append(records, feature)
+	commits := map[int64][]githubapi.Commit{1: {
+		{SHA: "bbb", Message: "Cache dependencies", CommittedAt: base.Add(48 * time.Hour)},
+		{SHA: "aaa", Message: "Add integration tests", CommittedAt: base.Add(24 * time.Hour)},
+		{SHA: "old", CommittedAt: base.Add(-time.Hour)},
+	}}
+	rows := []SummaryRow{{Workflow: "ci", WorkflowID: 1}, {Workflow: "lint", WorkflowID: 2}}
+
+	AttachWorkflowChanges(rows, records, commits, "main", base, base.Add(72*time.Hour), FailurePolicy{})
+	if rows[1].Changes != nil {
+		t.Fatalf("expected no changes for an unedited workflow, got %+v", rows[1].Changes)
+	}
+
+	changes := rows[0].Changes
+	if len(changes) != 2 || changes[0].SHA != "aaa" || changes[1].SHA != "bbb" {
+		t.Fatalf("expected changes in the window oldest first, got %+v", changes)
+	}
+
+	first := changes[0]
+	if first.Before.Runs != 2 || first.Before.AvgDuration != 10*time.Minute || first.After.Runs != 2 || first.After.AvgDuration != 20*time.Minute {
+		t.Fatalf("unexpected first change stats %+v", first)
+	}
+	if first.Trend.DurationChange != 1 || first.Trend.FailureRateChange != 0.5 {
+		t.Fatalf("unexpected first change trend %+v", first.Trend)
+	}
+
+	second := changes[1]
+	if second.Before.Runs != 2 || second.After.Runs != 1 || second.After.AvgDuration != 15*time.Minute || second.Trend.DurationChange != -0.25 {
+		t.Fatalf("expected the second change to compare against the runs after the first, got %+v", second)
+	}
+}
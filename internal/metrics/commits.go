@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommitRow rolls up the workflow runs triggered for one head commit.
+type CommitRow struct {
+	SHA       string `json:"sha"`
+	Branch    string `json:"branch"`
+	Workflows int    `json:"workflows"`
+	Runs      int    `json:"runs"`
+	// Conclusion combines the latest run of every workflow: in_progress
+	// while any is unfinished, then failure, cancelled or success.
+	Conclusion string    `json:"conclusion"`
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	// WallClock is the time from the first run being created to the last
+	// workflow finishing: the wait a developer experiences after pushing.
+	WallClock       time.Duration `json:"wall_clock,omitempty"`
+	SlowestWorkflow string        `json:"slowest_workflow"`
+	SlowestDuration time.Duration `json:"slowest_duration"`
+}
+
+// SlowestWorkflowCount counts the commits on which a workflow finished last.
+type SlowestWorkflowCount struct {
+	Workflow string `json:"workflow"`
+	Commits  int    `json:"commits"`
+}
+
+// CommitReport summarizes commit-level CI latency.
+type CommitReport struct {
+	Commits         int                    `json:"commits"`
+	MedianWallClock time.Duration          `json:"median_wall_clock"`
+	P90WallClock    time.Duration          `json:"p90_wall_clock"`
+	Slowest         []SlowestWorkflowCount `json:"slowest"`
+	Rows            []CommitRow            `json:"rows"`
+}
+
+// Commits groups runs started within [from, to] by head commit. Each
+// workflow is judged by its latest run on the commit, so re-runs replace
+// earlier attempts. Rows are ordered newest first.
+func Commits(records []RunRecord, from, to time.Time, policy FailurePolicy) CommitReport {
+	type commitStat struct {
+		row    CommitRow
+		latest map[int64]RunRecord
+	}
+	stats := make(map[string]*commitStat)
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) || rec.Run.HeadSHA == "" {
+			continue
+		}
+
+		st, ok := stats[rec.Run.HeadSHA]
+		if !ok {
+			st = &commitStat{
+				row:    CommitRow{SHA: rec.Run.HeadSHA, Branch: rec.Run.HeadBranch},
+				latest: make(map[int64]RunRecord),
+			}
+			stats[rec.Run.HeadSHA] = st
+		}
+		st.row.Runs++
+		if st.row.CreatedAt.IsZero() || rec.Run.CreatedAt.Before(st.row.CreatedAt) {
+			st.row.CreatedAt = rec.Run.CreatedAt
+		}
+		current, ok := st.latest[rec.Workflow.ID]
+		if !ok || rec.Run.CreatedAt.After(current.Run.CreatedAt) ||
+			(rec.Run.CreatedAt.Equal(current.Run.CreatedAt) && rec.Run.RunAttempt > current.Run.RunAttempt) {
+			st.latest[rec.Workflow.ID] = rec
+		}
+	}
+
+	report := CommitReport{Commits: len(stats)}
+	slowest := make(map[string]int)
+	var wallClocks []time.Duration
+	for _, st := range stats {
+		row := st.row
+		row.Workflows = len(st.latest)
+
+		finished := true
+		failed, cancelled := false, false
+		for _, rec := range st.latest {
+			if !strings.EqualFold(rec.Run.Status, "completed") {
+				finished = false
+				continue
+			}
+			if policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status) {
+				failed = true
+			} else if strings.EqualFold(rec.Run.Conclusion, "cancelled") {
+				cancelled = true
+			}
+
+			end := runFinishedAt(rec)
+			if end.After(row.FinishedAt) {
+				row.FinishedAt = end
+			}
+			if took := end.Sub(rec.Run.CreatedAt); took > row.SlowestDuration ||
+				(took == row.SlowestDuration && rec.Workflow.Name < row.SlowestWorkflow) {
+				row.SlowestDuration = took
+				row.SlowestWorkflow = rec.Workflow.Name
+			}
+		}
+
+		switch {
+		case !finished:
+			row.Conclusion = "in_progress"
+			row.FinishedAt = time.Time{}
+		case failed:
+			row.Conclusion = "failure"
+		case cancelled:
+			row.Conclusion = "cancelled"
+		default:
+			row.Conclusion = "success"
+		}
+
+		if finished {
+			row.WallClock = row.FinishedAt.Sub(row.CreatedAt)
+			wallClocks = append(wallClocks, row.WallClock)
+			if row.SlowestWorkflow != "" {
+				slowest[row.SlowestWorkflow]++
+			}
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	sort.Slice(wallClocks, func(i, j int) bool { return wallClocks[i] < wallClocks[j] })
+	report.MedianWallClock = Percentile(wallClocks, 0.5)
+	report.P90WallClock = Percentile(wallClocks, 0.9)
+
+	for workflow, commits := range slowest {
+		report.Slowest = append(report.Slowest, SlowestWorkflowCount{Workflow: workflow, Commits: commits})
+	}
+	sort.Slice(report.Slowest, func(i, j int) bool {
+		if report.Slowest[i].Commits != report.Slowest[j].Commits {
+			return report.Slowest[i].Commits > report.Slowest[j].Commits
+		}
+		return report.Slowest[i].Workflow < report.Slowest[j].Workflow
+	})
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if !report.Rows[i].CreatedAt.Equal(report.Rows[j].CreatedAt) {
+			return report.Rows[i].CreatedAt.After(report.Rows[j].CreatedAt)
+		}
+		return report.Rows[i].SHA < report.Rows[j].SHA
+	})
+	return report
+}
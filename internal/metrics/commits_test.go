@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestCommits(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	ci := githubapi.Workflow{ID: 1, Name: "ci"}
+	docs := githubapi.Workflow{ID: 2, Name: "docs"}
+	run := func(wf githubapi.Workflow, sha, status, conclusion string, created time.Duration, minutes, attempt int) RunRecord {
+		start := base.Add(created)
+		return RunRecord{
+			Workflow: wf,
+			Run: githubapi.WorkflowRun{
+				ID:         int64(created) + wf.ID,
+				HeadBranch: "main",
+				HeadSHA:    sha,
+				Status:     status,
+				Conclusion: conclusion,
+				CreatedAt:  start,
+				UpdatedAt:  start.Add(time.Duration(minutes) * time.Minute),
+				RunAttempt: attempt,
+			},
+		}
+	}
+
+	records := []RunRecord{
+		run(ci, "a", "completed", "failure", 0, 20, 1),
+		// The re-run of ci replaces the failed attempt.
+		run(ci, "a", "completed", "success", 30*time.Minute, 20, 2),
+		run(docs, "a", "completed", "success", time.Minute, 5, 1),
+		run(ci, "b", "completed", "success", 2*time.Hour, 15, 1),
+		run(docs, "b", "completed", "cancelled", 2*time.Hour, 25, 1),
+		run(ci, "c", "in_progress", "", 3*time.Hour, 0, 1),
+	}
+
+	report := Commits(records, base.Add(-time.Hour), base.Add(4*time.Hour), FailurePolicy{Conclusions: []string{"failure"}})
+	if report.Commits != 3 || len(report.Rows) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	c, b, a := report.Rows[0], report.Rows[1], report.Rows[2]
+	if c.SHA != "c" || c.Conclusion != "in_progress" || c.WallClock != 0 {
+		t.Fatalf("unexpected in-progress commit %+v", c)
+	}
+	if b.Conclusion != "cancelled" || b.WallClock != 25*time.Minute || b.SlowestWorkflow != "docs" {
+		t.Fatalf("unexpected commit b %+v", b)
+	}
+	if a.Conclusion != "success" || a.Runs != 3 || a.Workflows != 2 || a.WallClock != 50*time.Minute {
+		t.Fatalf("unexpected commit a %+v", a)
+	}
+	if a.SlowestWorkflow != "ci" || a.SlowestDuration != 20*time.Minute {
+		t.Fatalf("unexpected slowest workflow for a %+v", a)
+	}
+
+	if report.MedianWallClock != 25*time.Minute || report.P90WallClock != 50*time.Minute {
+		t.Fatalf("unexpected wall clock percentiles %+v", report)
+	}
+	if len(report.Slowest) != 2 || report.Slowest[0].Workflow != "ci" || report.Slowest[0].Commits != 1 {
+		t.Fatalf("unexpected slowest counts %+v", report.Slowest)
+	}
+}
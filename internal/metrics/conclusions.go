@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultFailureConclusions are the conclusions counted as failures unless a
+// FailurePolicy says otherwise.
+var DefaultFailureConclusions = []string{"failure", "cancelled", "timed_out", "action_required", "stale"}
+
+// knownConclusions are the conclusions GitHub reports for runs and jobs.
+var knownConclusions = []string{"success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required", "stale", "startup_failure"}
+
+// supersedeTolerance allows for the delay between a new run being created and
+// the run it replaces being marked cancelled.
+const supersedeTolerance = 10 * time.Second
+
+// FailurePolicy decides which conclusions count as failures. The zero value
+// uses DefaultFailureConclusions. Runs cancelled because a newer run
+// superseded them are never failures.
+type FailurePolicy struct {
+	Conclusions []string
+}
+
+// ParseFailurePolicy builds a policy from a list of conclusions, rejecting
+// conclusions GitHub does not report.
+func ParseFailurePolicy(conclusions []string) (FailurePolicy, error) {
+	var policy FailurePolicy
+	for _, c := range conclusions {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		known := false
+		for _, k := range knownConclusions {
+			if c == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return FailurePolicy{}, fmt.Errorf("unknown conclusion %q (expected one of %s)", c, strings.Join(knownConclusions, ", "))
+		}
+		policy.Conclusions = append(policy.Conclusions, c)
+	}
+	return policy, nil
+}
+
+// IsFailure reports whether a run or job with the given conclusion counts as
+// failed. Unfinished runs without a conclusion fall back to their status.
+func (p FailurePolicy) IsFailure(conclusion, status string) bool {
+	v := strings.ToLower(conclusion)
+	if v == "" {
+		return strings.ToLower(status) == "failure"
+	}
+	if v == "failed" {
+		v = "failure"
+	}
+	conclusions := p.Conclusions
+	if len(conclusions) == 0 {
+		conclusions = DefaultFailureConclusions
+	}
+	for _, c := range conclusions {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed classifies a run or job of rec, never counting the cancellation of a
+// superseded run as a failure.
+func (p FailurePolicy) Failed(rec RunRecord, conclusion, status string) bool {
+	if rec.Superseded && strings.EqualFold(conclusion, "cancelled") {
+		return false
+	}
+	return p.IsFailure(conclusion, status)
+}
+
+// conclusionKey is the histogram bucket for a run or job: its conclusion, or
+// its status while it has not concluded.
+func conclusionKey(conclusion, status string) string {
+	if conclusion != "" {
+		return strings.ToLower(conclusion)
+	}
+	if status != "" {
+		return strings.ToLower(status)
+	}
+	return "unknown"
+}
+
+// MarkSuperseded flags cancelled runs that were replaced by a newer run of the
+// same workflow on the same branch, as happens with concurrency groups that
+// use cancel-in-progress: a newer run was created before the cancelled run
+// finished.
+func MarkSuperseded(records []RunRecord) {
+	type key struct {
+		workflowID int64
+		branch     string
+	}
+	groups := make(map[key][]int)
+	for i, rec := range records {
+		k := key{workflowID: rec.Workflow.ID, branch: rec.Run.HeadBranch}
+		groups[k] = append(groups[k], i)
+	}
+
+	for _, idx := range groups {
+		sort.SliceStable(idx, func(a, b int) bool {
+			return records[idx[a]].Run.CreatedAt.Before(records[idx[b]].Run.CreatedAt)
+		})
+		for pos, i := range idx {
+			run := records[i].Run
+			if !strings.EqualFold(run.Conclusion, "cancelled") || pos == len(idx)-1 {
+				continue
+			}
+			next := records[idx[pos+1]].Run
+			if next.CreatedAt.After(run.CreatedAt) && !next.CreatedAt.After(runFinishedAt(records[i]).Add(supersedeTolerance)) {
+				records[i].Superseded = true
+			}
+		}
+	}
+}
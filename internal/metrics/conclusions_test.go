@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestMarkSuperseded(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "ci"}
+	run := func(id int64, branch, conclusion string, created time.Duration, duration time.Duration) RunRecord {
+		return RunRecord{
+			Workflow: workflow,
+			Run: githubapi.WorkflowRun{
+				ID:         id,
+				HeadBranch: branch,
+				Status:     "completed",
+				Conclusion: conclusion,
+				CreatedAt:  base.Add(created),
+				UpdatedAt:  base.Add(created + duration),
+				Duration:   duration,
+			},
+		}
+	}
+
+	records := []RunRecord{
+		// Replaced by run 2 while still running.
+		run(1, "main", "cancelled", 0, 5*time.Minute),
+		run(2, "main", "success", 4*time.Minute, 5*time.Minute),
+		// Cancelled by hand; the next run came much later.
+		run(3, "main", "cancelled", time.Hour, 2*time.Minute),
+		run(4, "main", "success", 2*time.Hour, 5*time.Minute),
+		// Newer run on another branch does not supersede it.
+		run(5, "feature", "cancelled", 0, 5*time.Minute),
+		run(6, "other", "success", time.Minute, 5*time.Minute),
+	}
+
+	MarkSuperseded(records)
+
+	want := map[int64]bool{1: true}
+	for _, rec := range records {
+		if rec.Superseded != want[rec.Run.ID] {
+			t.Fatalf("run %d: expected superseded=%v", rec.Run.ID, want[rec.Run.ID])
+		}
+	}
+}
+
+func TestAggregateWithPolicy(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "ci"}
+	run := func(id int64, conclusion string, superseded bool) RunRecord {
+		return RunRecord{
+			Workflow:   workflow,
+			Run:        githubapi.WorkflowRun{ID: id, Status: "completed", Conclusion: conclusion, CreatedAt: base, Duration: time.Minute},
+			Superseded: superseded,
+		}
+	}
+	records := []RunRecord{
+		run(1, "success", false),
+		run(2, "failure", false),
+		run(3, "cancelled", true),
+		run(4, "cancelled", false),
+		run(5, "skipped", false),
+	}
+	from, to := base.Add(-time.Hour), base.Add(time.Hour)
+
+	rows := Aggregate(records, from, to)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.Failed != 2 || row.Superseded != 1 {
+		t.Fatalf("expected 2 failures and 1 superseded run, got %+v", row)
+	}
+	if row.Conclusions["cancelled"] != 2 || row.Conclusions["skipped"] != 1 || row.Conclusions["success"] != 1 {
+		t.Fatalf("unexpected conclusions %v", row.Conclusions)
+	}
+
+	policy, err := ParseFailurePolicy([]string{"failure"})
+	if err != nil {
+		t.Fatalf("ParseFailurePolicy: %v", err)
+	}
+	if rows := AggregateWithPolicy(records, from, to, policy); rows[0].Failed != 1 {
+		t.Fatalf("expected only the failure to count, got %d", rows[0].Failed)
+	}
+
+	if _, err := ParseFailurePolicy([]string{"broken"}); err == nil {
+		t.Fatal("expected an error for an unknown conclusion")
+	}
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+)
+
+// AllLabels names the concurrency series that counts every job regardless of
+// its runner labels.
+const AllLabels = "(all)"
+
+// ConcurrencyPoint is the concurrency within one bucket of the time series.
+type ConcurrencyPoint struct {
+	Start time.Time `json:"start"`
+	Max   int       `json:"max"`
+	// Average is weighted by time within the bucket.
+	Average float64 `json:"average"`
+}
+
+// ConcurrencySeries is the number of jobs running at once for one runner
+// label over the window. P95 and Average are weighted by time.
+type ConcurrencySeries struct {
+	Label   string    `json:"label"`
+	Jobs    int       `json:"jobs"`
+	Max     int       `json:"max"`
+	P95     int       `json:"p95"`
+	Average float64   `json:"average"`
+	PeakAt  time.Time `json:"peak_at"`
+	// AtLimit is the time spent at or above the concurrency limit, when one
+	// was given.
+	AtLimit time.Duration      `json:"at_limit,omitempty"`
+	Points  []ConcurrencyPoint `json:"points"`
+}
+
+// step is a stretch of time with a constant number of running jobs.
+type step struct {
+	span
+	level int
+}
+
+// concurrencySteps turns job spans into a step function covering
+// [from, to], clipping spans to that range.
+func concurrencySteps(spans []span, from, to time.Time) []step {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(spans))
+	for _, s := range spans {
+		start, end := s.start, s.end
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+		events = append(events, event{at: start, delta: 1}, event{at: end, delta: -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	var steps []step
+	level, at := 0, from
+	for _, e := range events {
+		if e.at.After(at) {
+			steps = append(steps, step{span: span{start: at, end: e.at}, level: level})
+			at = e.at
+		}
+		level += e.delta
+	}
+	if to.After(at) {
+		steps = append(steps, step{span: span{start: at, end: to}, level: level})
+	}
+	return steps
+}
+
+// Concurrency computes, for every runner label requested by the jobs of
+// records started within [from, to], how many of those jobs were running at
+// once, plus an AllLabels series across every job. The series are bucketed
+// by bucket starting at from; the window extends to the end of the last job.
+// A positive limit, such as the organization's concurrent job limit, also
+// reports the time spent at or above it. Series are ordered by peak
+// concurrency, AllLabels first.
+func Concurrency(records []RunRecord, from, to time.Time, bucket time.Duration, limit int) []ConcurrencySeries {
+	spans := make(map[string][]span)
+	end := to
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, job := range rec.Jobs {
+			if job.StartedAt.IsZero() || job.CompletedAt.IsZero() || !job.CompletedAt.After(job.StartedAt) {
+				continue
+			}
+			s := span{start: job.StartedAt, end: job.CompletedAt}
+			spans[AllLabels] = append(spans[AllLabels], s)
+			for label := range labelSet(job.Labels) {
+				spans[label] = append(spans[label], s)
+			}
+			if job.CompletedAt.After(end) {
+				end = job.CompletedAt
+			}
+		}
+	}
+
+	series := make([]ConcurrencySeries, 0, len(spans))
+	for label, labelSpans := range spans {
+		series = append(series, concurrencySeries(label, labelSpans, from, end, bucket, limit))
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if (series[i].Label == AllLabels) != (series[j].Label == AllLabels) {
+			return series[i].Label == AllLabels
+		}
+		if series[i].Max != series[j].Max {
+			return series[i].Max > series[j].Max
+		}
+		return series[i].Label < series[j].Label
+	})
+	return series
+}
+
+func concurrencySeries(label string, spans []span, from, to time.Time, bucket time.Duration, limit int) ConcurrencySeries {
+	s := ConcurrencySeries{Label: label, Jobs: len(spans)}
+	steps := concurrencySteps(spans, from, to)
+
+	window := to.Sub(from)
+	levels := make(map[int]time.Duration)
+	var weighted float64
+	for _, st := range steps {
+		d := st.end.Sub(st.start)
+		levels[st.level] += d
+		weighted += float64(st.level) * float64(d)
+		if st.level > s.Max {
+			s.Max = st.level
+			s.PeakAt = st.start
+		}
+		if limit > 0 && st.level >= limit {
+			s.AtLimit += d
+		}
+	}
+	if window > 0 {
+		s.Average = weighted / float64(window)
+		s.P95 = timeWeightedPercentile(levels, window, 0.95)
+	}
+
+	if bucket <= 0 {
+		return s
+	}
+	i := 0
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		bucketEnd := start.Add(bucket)
+		if bucketEnd.After(to) {
+			bucketEnd = to
+		}
+		point := ConcurrencyPoint{Start: start}
+		var bucketWeighted float64
+		for ; i < len(steps) && steps[i].start.Before(bucketEnd); i++ {
+			st := steps[i]
+			overlapEnd := st.end
+			if overlapEnd.After(bucketEnd) {
+				overlapEnd = bucketEnd
+			}
+			overlapStart := st.start
+			if overlapStart.Before(start) {
+				overlapStart = start
+			}
+			bucketWeighted += float64(st.level) * float64(overlapEnd.Sub(overlapStart))
+			if st.level > point.Max {
+				point.Max = st.level
+			}
+			if st.end.After(bucketEnd) {
+				// The step continues into the next bucket.
+				break
+			}
+		}
+		point.Average = bucketWeighted / float64(bucketEnd.Sub(start))
+		s.Points = append(s.Points, point)
+	}
+	return s
+}
+
+// timeWeightedPercentile returns the lowest level at or below which the
+// series spent at least fraction p of the window.
+func timeWeightedPercentile(levels map[int]time.Duration, window time.Duration, p float64) int {
+	keys := make([]int, 0, len(levels))
+	for level := range levels {
+		keys = append(keys, level)
+	}
+	sort.Ints(keys)
+
+	var covered time.Duration
+	for _, level := range keys {
+		covered += levels[level]
+		if float64(covered) >= p*float64(window) {
+			return level
+		}
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+	return keys[len(keys)-1]
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestConcurrency(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	job := func(start, end int, labels ...string) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{Name: "build", StartedAt: at(start), CompletedAt: at(end), Labels: labels}
+	}
+
+	records := []RunRecord{{
+		Workflow: githubapi.Workflow{ID: 1, Name: "ci"},
+		Run:      githubapi.WorkflowRun{ID: 1, CreatedAt: base},
+		Jobs: []githubapi.WorkflowJob{
+			job(0, 60, "ubuntu-latest"),
+			job(30, 90, "ubuntu-latest"),
+			job(60, 120, "macos-latest"),
+		},
+	}}
+
+	series := Concurrency(records, base, at(120), time.Hour, 2)
+	if len(series) != 3 {
+		t.Fatalf("expected 3 series, got %+v", series)
+	}
+
+	all := series[0]
+	if all.Label != AllLabels || all.Jobs != 3 || all.Max != 2 || !all.PeakAt.Equal(at(30)) {
+		t.Fatalf("unexpected all series %+v", all)
+	}
+	// 1 job for 30m, 2 for 60m, 1 for 30m.
+	if all.Average != 1.5 || all.P95 != 2 || all.AtLimit != time.Hour {
+		t.Fatalf("unexpected all series stats %+v", all)
+	}
+	if len(all.Points) != 2 {
+		t.Fatalf("expected 2 hourly points, got %+v", all.Points)
+	}
+	if all.Points[0].Max != 2 || all.Points[0].Average != 1.5 || all.Points[1].Max != 2 || all.Points[1].Average != 1.5 {
+		t.Fatalf("unexpected points %+v", all.Points)
+	}
+
+	ubuntu := series[1]
+	if ubuntu.Label != "ubuntu-latest" || ubuntu.Max != 2 || ubuntu.Jobs != 2 {
+		t.Fatalf("unexpected ubuntu series %+v", ubuntu)
+	}
+	if ubuntu.Points[1].Max != 1 || ubuntu.Points[1].Average != 0.5 {
+		t.Fatalf("unexpected ubuntu second hour %+v", ubuntu.Points[1])
+	}
+	if series[2].Label != "macos-latest" || series[2].Max != 1 || series[2].P95 != 1 {
+		t.Fatalf("unexpected macos series %+v", series[2])
+	}
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "github.com/JohnTitor/gh-actrics/internal/workflowfile"
+
+// AttachJobConfig joins the declared configuration of each job, looked up in
+// the workflow files keyed by workflow ID, onto the summary rows. Jobs that do
+// not match a declared job are left without configuration.
+func AttachJobConfig(rows []SummaryRow, files map[int64]*workflowfile.Workflow) {
+	for i := range rows {
+		wf := files[rows[i].WorkflowID]
+		if wf == nil {
+			continue
+		}
+		for j := range rows[i].Jobs {
+			rows[i].Jobs[j].Config = wf.MatchJob(rows[i].Jobs[j].Job)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+)
+
+// dependencyTolerance is how long after a job completes GitHub may take to
+// create the jobs that need it.
+const dependencyTolerance = 10 * time.Second
+
+// JobNeeds resolves the dependency graph of a run from its workflow file:
+// needs[i] lists the indices of the jobs that jobs[i] declares in needs,
+// including every cell of a needed matrix job. It returns nil when wf is nil
+// or a job cannot be matched to a declared job, such as the jobs of a called
+// reusable workflow, so that dependencies are inferred from timing instead.
+func JobNeeds(jobs []githubapi.WorkflowJob, wf *workflowfile.Workflow) [][]int {
+	if wf == nil {
+		return nil
+	}
+	declared := make([]*workflowfile.Job, len(jobs))
+	for i, job := range jobs {
+		// The jobs of a called reusable workflow match the caller, whose
+		// needs say nothing about the order of the called jobs.
+		if declared[i] = wf.MatchJob(strings.TrimSpace(job.Name)); declared[i] == nil || declared[i].Uses != "" {
+			return nil
+		}
+	}
+
+	needs := make([][]int, len(jobs))
+	for i := range jobs {
+		needs[i] = []int{}
+		for _, id := range declared[i].Needs {
+			for j := range jobs {
+				if declared[j].ID == id {
+					needs[i] = append(needs[i], j)
+				}
+			}
+		}
+	}
+	return needs
+}
+
+// CriticalPath infers the chain of jobs that determined the wall-clock
+// duration of a run and returns their indices in execution order. Starting
+// from the job that finished last, it repeatedly steps back to the job the
+// current job waited for: with needs (see JobNeeds), the needed job that
+// completed last; without it, the job that completed most recently before the
+// current job became ready (was created, or started when the creation time is
+// unknown). The chain ends at a job that waited for no other job.
+func CriticalPath(jobs []githubapi.WorkflowJob, needs [][]int) []int {
+	last := -1
+	for i, job := range jobs {
+		if job.CompletedAt.IsZero() || job.StartedAt.IsZero() {
+			continue
+		}
+		if last < 0 || job.CompletedAt.After(jobs[last].CompletedAt) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+
+	path := []int{last}
+	visited := map[int]bool{last: true}
+	for cur := last; ; {
+		prev := predecessor(jobs, needs, cur, visited)
+		if prev < 0 {
+			break
+		}
+		path = append(path, prev)
+		visited[prev] = true
+		cur = prev
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// predecessor returns the index of the job that most plausibly blocked
+// jobs[cur], or -1 when none did.
+func predecessor(jobs []githubapi.WorkflowJob, needs [][]int, cur int, visited map[int]bool) int {
+	if needs != nil {
+		best := -1
+		for _, i := range needs[cur] {
+			if visited[i] || jobs[i].CompletedAt.IsZero() || jobs[i].StartedAt.IsZero() {
+				continue
+			}
+			if best < 0 || jobs[i].CompletedAt.After(jobs[best].CompletedAt) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	ready := jobs[cur].CreatedAt
+	if ready.IsZero() {
+		ready = jobs[cur].StartedAt
+	}
+	if ready.IsZero() {
+		return -1
+	}
+
+	best := -1
+	for i, job := range jobs {
+		if visited[i] || job.CompletedAt.IsZero() || job.StartedAt.IsZero() {
+			continue
+		}
+		if job.CompletedAt.After(ready.Add(dependencyTolerance)) || !job.CompletedAt.Before(jobs[cur].CompletedAt) {
+			continue
+		}
+		if best < 0 || job.CompletedAt.After(jobs[best].CompletedAt) {
+			best = i
+		}
+	}
+	return best
+}
+
+// JobSlack returns, for every job, how much longer it could have run without
+// delaying the end of the run. Without needs (see JobNeeds) the estimate is
+// conservative: a job is assumed to be needed by every job that became ready
+// after it completed. Jobs without timing get zero slack.
+func JobSlack(jobs []githubapi.WorkflowJob, needs [][]int) []time.Duration {
+	slack := make([]time.Duration, len(jobs))
+
+	var (
+		order  []int
+		runEnd time.Time
+	)
+	for i, job := range jobs {
+		if job.CompletedAt.IsZero() || job.StartedAt.IsZero() {
+			continue
+		}
+		order = append(order, i)
+		if job.CompletedAt.After(runEnd) {
+			runEnd = job.CompletedAt
+		}
+	}
+	// Dependents complete after the jobs they need, so walking backwards from
+	// the last job to complete resolves every dependent before its needs.
+	sort.SliceStable(order, func(a, b int) bool {
+		return jobs[order[a]].CompletedAt.After(jobs[order[b]].CompletedAt)
+	})
+
+	for pos, i := range order {
+		best := runEnd.Sub(jobs[i].CompletedAt)
+		for _, j := range order[:pos] {
+			if needs != nil && !slices.Contains(needs[j], i) {
+				continue
+			}
+			ready := jobs[j].CreatedAt
+			if ready.IsZero() {
+				ready = jobs[j].StartedAt
+			}
+			gap := ready.Sub(jobs[i].CompletedAt)
+			if needs == nil && gap < 0 {
+				continue
+			}
+			if gap <= dependencyTolerance {
+				// Scheduling latency, not time the job could have used.
+				gap = 0
+			}
+			if gap+slack[j] < best {
+				best = gap + slack[j]
+			}
+		}
+		slack[i] = best
+	}
+	return slack
+}
+
+// CriticalPathJob summarizes how a job relates to the critical path across the
+// runs of a workflow.
+type CriticalPathJob struct {
+	Job         string        `json:"job"`
+	Runs        int           `json:"runs"`
+	OnPath      int           `json:"on_path"`
+	OnPathRate  float64       `json:"on_path_rate"`
+	AvgDuration time.Duration `json:"avg_duration"`
+	// MedianSlack is the median slack over the runs where the job was off
+	// the critical path.
+	MedianSlack time.Duration `json:"median_slack"`
+}
+
+// CriticalPathRow aggregates critical paths for one workflow.
+type CriticalPathRow struct {
+	Workflow        string            `json:"workflow"`
+	WorkflowID      int64             `json:"workflow_id"`
+	Runs            int               `json:"runs"`
+	AvgPathDuration time.Duration     `json:"avg_path_duration"`
+	CommonPath      []string          `json:"common_path"`
+	CommonPathRuns  int               `json:"common_path_runs"`
+	Jobs            []CriticalPathJob `json:"jobs"`
+}
+
+type criticalJobStat struct {
+	runs     int
+	onPath   int
+	duration time.Duration
+	slack    []time.Duration
+}
+
+type criticalStat struct {
+	row   CriticalPathRow
+	total time.Duration
+	paths map[string]int
+	jobs  map[string]*criticalJobStat
+}
+
+// CriticalPaths computes the critical path of every run in the window that has
+// job data and aggregates, per workflow, how often each job sits on the path
+// and how much slack it has when it does not. Dependencies are taken from the
+// workflow files keyed by workflow ID where available. Jobs are ordered by how
+// often they are on the path, then by average duration.
+func CriticalPaths(records []RunRecord, from, to time.Time, files map[int64]*workflowfile.Workflow) []CriticalPathRow {
+	stats := make(map[int64]*criticalStat)
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		needs := JobNeeds(rec.Jobs, files[rec.Workflow.ID])
+		path := CriticalPath(rec.Jobs, needs)
+		if len(path) == 0 {
+			continue
+		}
+
+		stat, ok := stats[rec.Workflow.ID]
+		if !ok {
+			stat = &criticalStat{
+				row:   CriticalPathRow{Workflow: rec.Workflow.Name, WorkflowID: rec.Workflow.ID},
+				paths: make(map[string]int),
+				jobs:  make(map[string]*criticalJobStat),
+			}
+			stats[rec.Workflow.ID] = stat
+		}
+		stat.row.Runs++
+
+		first, last := rec.Jobs[path[0]], rec.Jobs[path[len(path)-1]]
+		start := first.CreatedAt
+		if start.IsZero() {
+			start = first.StartedAt
+		}
+		stat.total += last.CompletedAt.Sub(start)
+
+		onPath := make(map[int]bool, len(path))
+		names := make([]string, 0, len(path))
+		for _, idx := range path {
+			onPath[idx] = true
+			names = append(names, rec.Jobs[idx].Name)
+		}
+		stat.paths[strings.Join(names, "\x00")]++
+
+		slack := JobSlack(rec.Jobs, needs)
+		for i, job := range rec.Jobs {
+			if job.CompletedAt.IsZero() || job.StartedAt.IsZero() {
+				continue
+			}
+			js, ok := stat.jobs[job.Name]
+			if !ok {
+				js = &criticalJobStat{}
+				stat.jobs[job.Name] = js
+			}
+			js.runs++
+			js.duration += job.Duration()
+			if onPath[i] {
+				js.onPath++
+				continue
+			}
+			js.slack = append(js.slack, slack[i])
+		}
+	}
+
+	rows := make([]CriticalPathRow, 0, len(stats))
+	for _, stat := range stats {
+		row := stat.row
+		row.AvgPathDuration = time.Duration(int64(stat.total) / int64(row.Runs))
+
+		var common string
+		for key, count := range stat.paths {
+			if count > row.CommonPathRuns || (count == row.CommonPathRuns && key < common) {
+				common, row.CommonPathRuns = key, count
+			}
+		}
+		row.CommonPath = strings.Split(common, "\x00")
+
+		row.Jobs = make([]CriticalPathJob, 0, len(stat.jobs))
+		for name, js := range stat.jobs {
+			job := CriticalPathJob{
+				Job:         name,
+				Runs:        js.runs,
+				OnPath:      js.onPath,
+				OnPathRate:  float64(js.onPath) / float64(js.runs),
+				AvgDuration: time.Duration(int64(js.duration) / int64(js.runs)),
+			}
+			if len(js.slack) > 0 {
+				sort.Slice(js.slack, func(i, j int) bool { return js.slack[i] < js.slack[j] })
+				job.MedianSlack = Percentile(js.slack, 0.5)
+			}
+			row.Jobs = append(row.Jobs, job)
+		}
+		sort.Slice(row.Jobs, func(i, j int) bool {
+			if row.Jobs[i].OnPathRate != row.Jobs[j].OnPathRate {
+				return row.Jobs[i].OnPathRate > row.Jobs[j].OnPathRate
+			}
+			if row.Jobs[i].AvgDuration != row.Jobs[j].AvgDuration {
+				return row.Jobs[i].AvgDuration > row.Jobs[j].AvgDuration
+			}
+			return row.Jobs[i].Job < row.Jobs[j].Job
+		})
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Workflow < rows[j].Workflow
+	})
+	return rows
+}
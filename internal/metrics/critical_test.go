@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+)
+
+func TestCriticalPath(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	job := func(name string, created, started, completed int) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        name,
+			CreatedAt:   base.Add(time.Duration(created) * time.Second),
+			StartedAt:   base.Add(time.Duration(started) * time.Second),
+			CompletedAt: base.Add(time.Duration(completed) * time.Second),
+		}
+	}
+
+	jobs := []githubapi.WorkflowJob{
+		job("lint", 0, 5, 60),
+		job("build", 0, 5, 300),
+		job("unit", 302, 310, 500),
+		job("docs", 0, 10, 120),
+		job("deploy", 503, 510, 600),
+	}
+
+	got := CriticalPath(jobs, nil)
+	want := []int{1, 2, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected critical path %v, got %v", want, got)
+	}
+
+	if got := CriticalPath(nil, nil); got != nil {
+		t.Fatalf("expected nil path for no jobs, got %v", got)
+	}
+	pending := []githubapi.WorkflowJob{{Name: "queued", CreatedAt: base}}
+	if got := CriticalPath(pending, nil); got != nil {
+		t.Fatalf("expected nil path for unfinished jobs, got %v", got)
+	}
+}
+
+func TestJobSlack(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	job := func(name string, created, started, completed int) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        name,
+			CreatedAt:   base.Add(time.Duration(created) * time.Second),
+			StartedAt:   base.Add(time.Duration(started) * time.Second),
+			CompletedAt: base.Add(time.Duration(completed) * time.Second),
+		}
+	}
+
+	jobs := []githubapi.WorkflowJob{
+		job("build", 0, 5, 300),
+		job("lint", 0, 5, 60),
+		job("unit", 302, 310, 500),
+		job("e2e", 302, 310, 400),
+		job("deploy", 503, 510, 600),
+		{Name: "pending", CreatedAt: base},
+	}
+
+	got := JobSlack(jobs, nil)
+	want := []time.Duration{0, 242 * time.Second, 0, 103 * time.Second, 0, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected slack %v, got %v", want, got)
+	}
+}
+
+func TestCriticalPathWithNeeds(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	job := func(name string, created, started, completed int) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        name,
+			CreatedAt:   base.Add(time.Duration(created) * time.Second),
+			StartedAt:   base.Add(time.Duration(started) * time.Second),
+			CompletedAt: base.Add(time.Duration(completed) * time.Second),
+		}
+	}
+
+	// lint finishes just before deploy becomes ready, but deploy only needs
+	// the test matrix, which needs build.
+	jobs := []githubapi.WorkflowJob{
+		job("build", 0, 5, 100),
+		job("test (linux)", 101, 105, 250),
+		job("test (windows)", 101, 105, 290),
+		job("lint", 0, 5, 295),
+		job("deploy", 296, 300, 400),
+	}
+	wf := &workflowfile.Workflow{Jobs: []workflowfile.Job{
+		{ID: "build"},
+		{ID: "test", Needs: []string{"build"}},
+		{ID: "lint"},
+		{ID: "deploy", Needs: []string{"test"}},
+	}}
+
+	needs := JobNeeds(jobs, wf)
+	if !reflect.DeepEqual(needs, [][]int{{}, {0}, {0}, {}, {1, 2}}) {
+		t.Fatalf("unexpected needs %v", needs)
+	}
+	if got := CriticalPath(jobs, nil); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Fatalf("expected timing alone to blame lint, got %v", got)
+	}
+	if got := CriticalPath(jobs, needs); !reflect.DeepEqual(got, []int{0, 2, 4}) {
+		t.Fatalf("expected the path through the needed jobs, got %v", got)
+	}
+
+	got := JobSlack(jobs, needs)
+	want := []time.Duration{0, 46 * time.Second, 0, 105 * time.Second, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected slack %v, got %v", want, got)
+	}
+
+	reusable := &workflowfile.Workflow{Jobs: []workflowfile.Job{{ID: "build", Uses: "./.github/workflows/build.yml"}}}
+	if needs := JobNeeds([]githubapi.WorkflowJob{job("build / compile", 0, 5, 100)}, reusable); needs != nil {
+		t.Fatalf("expected jobs of a reusable workflow to fall back to timing, got %v", needs)
+	}
+}
+
+func TestCriticalPaths(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "ci"}
+
+	run := func(id int64, start time.Time, testLonger bool) RunRecord {
+		at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+		build, test := 200, 100
+		if testLonger {
+			build, test = 100, 200
+		}
+		return RunRecord{
+			Workflow: workflow,
+			Run:      githubapi.WorkflowRun{ID: id, CreatedAt: start},
+			Jobs: []githubapi.WorkflowJob{
+				{Name: "build", CreatedAt: at(0), StartedAt: at(0), CompletedAt: at(build)},
+				{Name: "test", CreatedAt: at(0), StartedAt: at(0), CompletedAt: at(test)},
+				{Name: "deploy", CreatedAt: at(205), StartedAt: at(210), CompletedAt: at(270)},
+			},
+		}
+	}
+
+	records := []RunRecord{
+		run(1, base, false),
+		run(2, base.Add(time.Hour), false),
+		run(3, base.Add(2*time.Hour), true),
+		{Workflow: workflow, Run: githubapi.WorkflowRun{ID: 4, CreatedAt: base}},
+	}
+
+	rows := CriticalPaths(records, base, base.Add(3*time.Hour), nil)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 workflow, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.Runs != 3 {
+		t.Fatalf("expected 3 analyzed runs, got %d", row.Runs)
+	}
+	if !reflect.DeepEqual(row.CommonPath, []string{"build", "deploy"}) || row.CommonPathRuns != 2 {
+		t.Fatalf("unexpected common path %v (%d runs)", row.CommonPath, row.CommonPathRuns)
+	}
+	if row.AvgPathDuration != 270*time.Second {
+		t.Fatalf("unexpected path duration %v", row.AvgPathDuration)
+	}
+
+	if len(row.Jobs) != 3 {
+		t.Fatalf("expected 3 jobs, got %d", len(row.Jobs))
+	}
+	deploy, build, test := row.Jobs[0], row.Jobs[1], row.Jobs[2]
+	if deploy.Job != "deploy" || deploy.OnPath != 3 || deploy.OnPathRate != 1 {
+		t.Fatalf("unexpected deploy stats: %+v", deploy)
+	}
+	if build.Job != "build" || build.OnPath != 2 || build.MedianSlack != 105*time.Second {
+		t.Fatalf("unexpected build stats: %+v", build)
+	}
+	if test.Job != "test" || test.OnPath != 1 || test.MedianSlack != 105*time.Second {
+		t.Fatalf("unexpected test stats: %+v", test)
+	}
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+)
+
+// DailyStat summarizes the runs of a workflow, or the runs of a job, that
+// started on one UTC day.
+type DailyStat struct {
+	Day         time.Time     `json:"day"`
+	Runs        int           `json:"runs"`
+	Failed      int           `json:"failed"`
+	AvgDuration time.Duration `json:"avg_duration"`
+}
+
+// dailySeries accumulates a DailyStat per day of the window.
+type dailySeries struct {
+	days      []DailyStat
+	durations []time.Duration
+}
+
+func newDailySeries(from, to time.Time) *dailySeries {
+	s := &dailySeries{}
+	if from.IsZero() {
+		return s
+	}
+	start := from.UTC().Truncate(24 * time.Hour)
+	for day := start; !day.After(to); day = day.Add(24 * time.Hour) {
+		s.days = append(s.days, DailyStat{Day: day})
+	}
+	s.durations = make([]time.Duration, len(s.days))
+	return s
+}
+
+func (s *dailySeries) add(at time.Time, duration time.Duration, failed bool) {
+	if len(s.days) == 0 {
+		return
+	}
+	i := int(at.Sub(s.days[0].Day) / (24 * time.Hour))
+	if i < 0 || i >= len(s.days) {
+		return
+	}
+	s.days[i].Runs++
+	if failed {
+		s.days[i].Failed++
+	}
+	s.durations[i] += duration
+}
+
+func (s *dailySeries) stats() []DailyStat {
+	out := append([]DailyStat(nil), s.days...)
+	for i := range out {
+		if out[i].Runs > 0 {
+			out[i].AvgDuration = s.durations[i] / time.Duration(out[i].Runs)
+		}
+	}
+	return out
+}
+
+// AttachDailyTrends sets the Daily series of every workflow row, job and
+// matrix cell from the records started within [from, to], so tables can show
+// the direction of travel rather than only the window average.
+func AttachDailyTrends(rows []SummaryRow, records []RunRecord, from, to time.Time, policy FailurePolicy) {
+	workflows := make(map[int64]*dailySeries)
+	jobs := make(map[int64]map[string]*dailySeries)
+	series := func(m map[string]*dailySeries, key string) *dailySeries {
+		s, ok := m[key]
+		if !ok {
+			s = newDailySeries(from, to)
+			m[key] = s
+		}
+		return s
+	}
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		ws, ok := workflows[rec.Workflow.ID]
+		if !ok {
+			ws = newDailySeries(from, to)
+			workflows[rec.Workflow.ID] = ws
+			jobs[rec.Workflow.ID] = make(map[string]*dailySeries)
+		}
+		ws.add(runTime, rec.Run.Duration, policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status))
+
+		for _, job := range rec.Jobs {
+			name := strings.TrimSpace(job.Name)
+			if name == "" {
+				name = "(unnamed)"
+			}
+			at := job.StartedAt
+			if at.IsZero() {
+				at = runTime
+			}
+			failed := policy.Failed(rec, job.Conclusion, job.Status)
+			// Track both the matrix group and the cell; a group with a single
+			// cell is reported under the cell's full name.
+			base, _ := SplitMatrixJobName(name)
+			series(jobs[rec.Workflow.ID], base).add(at, job.Duration(), failed)
+			if base != name {
+				series(jobs[rec.Workflow.ID], name).add(at, job.Duration(), failed)
+			}
+		}
+	}
+
+	for i := range rows {
+		if ws := workflows[rows[i].WorkflowID]; ws != nil {
+			rows[i].Daily = ws.stats()
+		}
+		jobSeries := jobs[rows[i].WorkflowID]
+		for j := range rows[i].Jobs {
+			job := &rows[i].Jobs[j]
+			if s := jobSeries[job.Job]; s != nil {
+				job.Daily = s.stats()
+			}
+			for k := range job.Matrix {
+				if s := jobSeries[job.Matrix[k].Job]; s != nil {
+					job.Matrix[k].Daily = s.stats()
+				}
+			}
+		}
+	}
+}
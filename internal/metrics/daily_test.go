@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestAttachDailyTrends(t *testing.T) {
+	day := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	run := func(id int64, at time.Time, conclusion string, minutes int, jobName string) RunRecord {
+		duration := time.Duration(minutes) * time.Minute
+		return RunRecord{
+			Workflow: githubapi.Workflow{ID: 1, Name: "ci"},
+			Run:      githubapi.WorkflowRun{ID: id, Status: "completed", Conclusion: conclusion, RunStartedAt: at, Duration: duration},
+			Jobs: []githubapi.WorkflowJob{{
+				Name: jobName, Status: "completed", Conclusion: conclusion, StartedAt: at, CompletedAt: at.Add(duration),
+			}},
+		}
+	}
+	records := []RunRecord{
+		run(1, day.Add(9*time.Hour), "success", 4, "test (ubuntu)"),
+		run(2, day.Add(15*time.Hour), "failure", 8, "test (windows)"),
+		run(3, day.Add(50*time.Hour), "success", 10, "test (ubuntu)"),
+	}
+	from, to := day.Add(6*time.Hour), day.Add(60*time.Hour)
+
+	rows := Aggregate(records, from, to)
+	AttachDailyTrends(rows, records, from, to, FailurePolicy{})
+
+	daily := rows[0].Daily
+	if len(daily) != 3 || !daily[0].Day.Equal(day) {
+		t.Fatalf("expected 3 days starting %v, got %+v", day, daily)
+	}
+	if daily[0].Runs != 2 || daily[0].Failed != 1 || daily[0].AvgDuration != 6*time.Minute {
+		t.Fatalf("unexpected first day %+v", daily[0])
+	}
+	if daily[1].Runs != 0 || daily[2].Runs != 1 || daily[2].AvgDuration != 10*time.Minute {
+		t.Fatalf("unexpected later days %+v", daily)
+	}
+
+	job := rows[0].Jobs[0]
+	if job.Job != "test" || len(job.Daily) != 3 || job.Daily[0].Runs != 2 {
+		t.Fatalf("unexpected job trend %+v", job)
+	}
+	if len(job.Matrix) != 2 || job.Matrix[0].Daily == nil {
+		t.Fatalf("expected matrix cells with trends, got %+v", job.Matrix)
+	}
+}
@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// Deployment states reported in DeploymentRow.State.
+const (
+	DeploymentSucceeded = "success"
+	DeploymentFailed    = "failure"
+	DeploymentPending   = "pending"
+)
+
+// DeploymentHistory is a deployment with its statuses, oldest first.
+type DeploymentHistory struct {
+	Deployment githubapi.Deployment
+	Statuses   []githubapi.DeploymentStatus
+}
+
+// DeploymentRow describes one deployment made by a workflow run.
+type DeploymentRow struct {
+	ID          int64     `json:"id"`
+	Environment string    `json:"environment"`
+	Workflow    string    `json:"workflow"`
+	RunID       int64     `json:"run_id"`
+	SHA         string    `json:"sha"`
+	Ref         string    `json:"ref"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+	// ApprovalWait is how long the deployment waited for protection rules
+	// to be approved.
+	ApprovalWait time.Duration `json:"approval_wait"`
+	// Execution is the run duration without the approval wait.
+	Execution time.Duration `json:"execution"`
+	// LeadTime is the time from the head commit to a successful deployment.
+	LeadTime time.Duration `json:"lead_time,omitempty"`
+}
+
+// EnvironmentRow aggregates the deployments to one environment.
+type EnvironmentRow struct {
+	Environment string `json:"environment"`
+	Deployments int    `json:"deployments"`
+	Succeeded   int    `json:"succeeded"`
+	Failed      int    `json:"failed"`
+	// ChangeFailureRate is the share of finished deployments that failed.
+	ChangeFailureRate float64 `json:"change_failure_rate"`
+	// PerDay is the number of successful deployments per day of the window.
+	PerDay             float64       `json:"per_day"`
+	Approvals          int           `json:"approvals"`
+	MedianApprovalWait time.Duration `json:"median_approval_wait"`
+	P90ApprovalWait    time.Duration `json:"p90_approval_wait"`
+	MedianExecution    time.Duration `json:"median_execution"`
+	MedianLeadTime     time.Duration `json:"median_lead_time"`
+	P90LeadTime        time.Duration `json:"p90_lead_time"`
+	LastDeployedAt     time.Time     `json:"last_deployed_at,omitempty"`
+}
+
+// DeploymentReport holds deployment metrics per environment, the DORA
+// deployment frequency, lead time and change failure rate among them.
+type DeploymentReport struct {
+	From         time.Time        `json:"from"`
+	To           time.Time        `json:"to"`
+	Environments []EnvironmentRow `json:"environments"`
+	Deployments  []DeploymentRow  `json:"deployments"`
+}
+
+var runURLPattern = regexp.MustCompile(`/actions/runs/(\d+)`)
+
+// Deployments builds deployment metrics for the deployments created within
+// [from, to] by the runs in records. A deployment is matched to its run
+// through the run URL its statuses link to; deployments that no collected run
+// made are skipped. Rows are ordered newest first.
+func Deployments(records []RunRecord, histories []DeploymentHistory, from, to time.Time) DeploymentReport {
+	runs := make(map[int64]RunRecord, len(records))
+	for _, rec := range records {
+		runs[rec.Run.ID] = rec
+	}
+
+	report := DeploymentReport{From: from, To: to}
+	for _, history := range histories {
+		d := history.Deployment
+		if d.CreatedAt.Before(from) || d.CreatedAt.After(to) {
+			continue
+		}
+		rec, ok := runs[deploymentRunID(history.Statuses)]
+		if !ok {
+			continue
+		}
+
+		row := DeploymentRow{
+			ID:          d.ID,
+			Environment: d.Environment,
+			Workflow:    rec.Workflow.Name,
+			RunID:       rec.Run.ID,
+			SHA:         d.SHA,
+			Ref:         d.Ref,
+			State:       DeploymentPending,
+			CreatedAt:   d.CreatedAt,
+		}
+
+		var waitingSince time.Time
+		for _, status := range history.Statuses {
+			state := strings.ToLower(status.State)
+			if state == "waiting" {
+				if waitingSince.IsZero() {
+					waitingSince = status.CreatedAt
+				}
+				continue
+			}
+			if !waitingSince.IsZero() && row.ApprovalWait == 0 && status.CreatedAt.After(waitingSince) {
+				row.ApprovalWait = status.CreatedAt.Sub(waitingSince)
+			}
+			switch state {
+			case "success":
+				row.State = DeploymentSucceeded
+				row.FinishedAt = status.CreatedAt
+			case "failure", "error":
+				row.State = DeploymentFailed
+				row.FinishedAt = status.CreatedAt
+			}
+		}
+
+		if rec.Run.Duration > row.ApprovalWait {
+			row.Execution = rec.Run.Duration - row.ApprovalWait
+		}
+		if row.State == DeploymentSucceeded {
+			committed := rec.Run.HeadCommitAt
+			if committed.IsZero() {
+				committed = rec.Run.CreatedAt
+			}
+			if row.FinishedAt.After(committed) {
+				row.LeadTime = row.FinishedAt.Sub(committed)
+			}
+		}
+		report.Deployments = append(report.Deployments, row)
+	}
+
+	sort.SliceStable(report.Deployments, func(i, j int) bool {
+		return report.Deployments[i].CreatedAt.After(report.Deployments[j].CreatedAt)
+	})
+	report.Environments = environmentRows(report.Deployments, from, to)
+	return report
+}
+
+// deploymentRunID returns the ID of the workflow run a deployment status
+// links to, or zero when none does.
+func deploymentRunID(statuses []githubapi.DeploymentStatus) int64 {
+	for _, status := range statuses {
+		for _, link := range []string{status.LogURL, status.TargetURL} {
+			if m := runURLPattern.FindStringSubmatch(link); m != nil {
+				if id, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+					return id
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func environmentRows(deployments []DeploymentRow, from, to time.Time) []EnvironmentRow {
+	type envStat struct {
+		row                      EnvironmentRow
+		waits, executions, leads []time.Duration
+	}
+	stats := make(map[string]*envStat)
+	for _, d := range deployments {
+		st, ok := stats[d.Environment]
+		if !ok {
+			st = &envStat{row: EnvironmentRow{Environment: d.Environment}}
+			stats[d.Environment] = st
+		}
+		st.row.Deployments++
+		switch d.State {
+		case DeploymentSucceeded:
+			st.row.Succeeded++
+			st.leads = append(st.leads, d.LeadTime)
+			if d.FinishedAt.After(st.row.LastDeployedAt) {
+				st.row.LastDeployedAt = d.FinishedAt
+			}
+		case DeploymentFailed:
+			st.row.Failed++
+		}
+		if d.ApprovalWait > 0 {
+			st.row.Approvals++
+			st.waits = append(st.waits, d.ApprovalWait)
+		}
+		if d.Execution > 0 {
+			st.executions = append(st.executions, d.Execution)
+		}
+	}
+
+	days := to.Sub(from).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	rows := make([]EnvironmentRow, 0, len(stats))
+	for _, st := range stats {
+		row := st.row
+		if finished := row.Succeeded + row.Failed; finished > 0 {
+			row.ChangeFailureRate = float64(row.Failed) / float64(finished)
+		}
+		row.PerDay = float64(row.Succeeded) / days
+		for _, values := range [][]time.Duration{st.waits, st.executions, st.leads} {
+			sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		}
+		row.MedianApprovalWait = Percentile(st.waits, 0.5)
+		row.P90ApprovalWait = Percentile(st.waits, 0.9)
+		row.MedianExecution = Percentile(st.executions, 0.5)
+		row.MedianLeadTime = Percentile(st.leads, 0.5)
+		row.P90LeadTime = Percentile(st.leads, 0.9)
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Deployments != rows[j].Deployments {
+			return rows[i].Deployments > rows[j].Deployments
+		}
+		return rows[i].Environment < rows[j].Environment
+	})
+	return rows
+}
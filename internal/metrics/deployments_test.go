@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestDeployments(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	deploy := githubapi.Workflow{ID: 1, Name: "deploy"}
+	record := func(id int64, created time.Duration, duration time.Duration) RunRecord {
+		at := base.Add(created)
+		return RunRecord{Workflow: deploy, Run: githubapi.WorkflowRun{
+			ID: id, CreatedAt: at, Duration: duration, HeadCommitAt: at.Add(-30 * time.Minute),
+		}}
+	}
+	status := func(state string, runID int64, at time.Duration) githubapi.DeploymentStatus {
+		return githubapi.DeploymentStatus{
+			State:     state,
+			CreatedAt: base.Add(at),
+			LogURL:    fmt.Sprintf("https://github.com/org/repo/actions/runs/%d/job/9", runID),
+		}
+	}
+	history := func(id int64, env string, created time.Duration, statuses ...githubapi.DeploymentStatus) DeploymentHistory {
+		return DeploymentHistory{
+			Deployment: githubapi.Deployment{ID: id, Environment: env, SHA: "abc", CreatedAt: base.Add(created)},
+			Statuses:   statuses,
+		}
+	}
+
+	records := []RunRecord{
+		record(1, 0, 50*time.Minute),
+		record(2, 24*time.Hour, 20*time.Minute),
+		record(3, 48*time.Hour, 10*time.Minute),
+	}
+	histories := []DeploymentHistory{
+		// Waited 30 minutes for approval, then deployed.
+		history(10, "production", 5*time.Minute,
+			status("waiting", 1, 5*time.Minute),
+			status("queued", 1, 35*time.Minute),
+			status("in_progress", 1, 36*time.Minute),
+			status("success", 1, 50*time.Minute)),
+		history(11, "production", 24*time.Hour+time.Minute,
+			status("in_progress", 2, 24*time.Hour+time.Minute),
+			status("failure", 2, 24*time.Hour+20*time.Minute)),
+		history(12, "staging", 48*time.Hour,
+			status("in_progress", 3, 48*time.Hour),
+			status("success", 3, 48*time.Hour+10*time.Minute)),
+		// Not made by a collected run.
+		history(13, "production", time.Hour, status("success", 99, time.Hour)),
+	}
+
+	report := Deployments(records, histories, base, base.Add(4*24*time.Hour))
+	if len(report.Deployments) != 3 || report.Deployments[0].ID != 12 {
+		t.Fatalf("unexpected deployments %+v", report.Deployments)
+	}
+
+	first := report.Deployments[2]
+	if first.State != DeploymentSucceeded || first.ApprovalWait != 30*time.Minute || first.Execution != 20*time.Minute {
+		t.Fatalf("unexpected approval split %+v", first)
+	}
+	if first.LeadTime != 80*time.Minute {
+		t.Fatalf("expected lead time from commit to success, got %s", first.LeadTime)
+	}
+
+	if len(report.Environments) != 2 {
+		t.Fatalf("unexpected environments %+v", report.Environments)
+	}
+	prod := report.Environments[0]
+	if prod.Environment != "production" || prod.Deployments != 2 || prod.Succeeded != 1 || prod.Failed != 1 || prod.ChangeFailureRate != 0.5 {
+		t.Fatalf("unexpected production row %+v", prod)
+	}
+	if prod.PerDay != 0.25 || prod.Approvals != 1 || prod.MedianApprovalWait != 30*time.Minute || prod.MedianLeadTime != 80*time.Minute {
+		t.Fatalf("unexpected production metrics %+v", prod)
+	}
+}
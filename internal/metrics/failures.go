@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// Sources of failure samples.
+const (
+	FailureSourceAnnotation = "annotation"
+	FailureSourceLog        = "log"
+)
+
+// FailedJob is a job that failed under the failure policy, with the first
+// step that failed when the job reports one.
+type FailedJob struct {
+	Workflow string
+	Run      githubapi.WorkflowRun
+	Job      githubapi.WorkflowJob
+	Step     githubapi.WorkflowStep
+}
+
+// FailureSample is one error message observed for a failed job.
+type FailureSample struct {
+	Workflow string    `json:"workflow"`
+	Job      string    `json:"job"`
+	Step     string    `json:"step,omitempty"`
+	RunID    int64     `json:"run_id"`
+	JobID    int64     `json:"job_id"`
+	At       time.Time `json:"at"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+}
+
+// FailureCluster groups samples whose messages are identical once
+// timestamps, paths and numbers are normalized away.
+type FailureCluster struct {
+	Signature string `json:"signature"`
+	Source    string `json:"source"`
+	// Message is the most recent raw message of the cluster.
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	Jobs      []string  `json:"jobs"`
+	Runs      []int64   `json:"runs"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// FailureReport summarizes the errors behind failed jobs.
+type FailureReport struct {
+	FailedJobs int `json:"failed_jobs"`
+	// Distinct counts every cluster, including those cut from Clusters by
+	// --top.
+	Distinct int              `json:"distinct_errors"`
+	Clusters []FailureCluster `json:"clusters"`
+}
+
+// FailedJobs returns the jobs of runs started within [from, to] that failed
+// under policy, most recent first.
+func FailedJobs(records []RunRecord, from, to time.Time, policy FailurePolicy) []FailedJob {
+	var jobs []FailedJob
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, job := range rec.Jobs {
+			if !policy.Failed(rec, job.Conclusion, job.Status) {
+				continue
+			}
+			failed := FailedJob{Workflow: rec.Workflow.Name, Run: rec.Run, Job: job}
+			for _, step := range job.Steps {
+				if policy.IsFailure(step.Conclusion, step.Status) {
+					failed.Step = step
+					break
+				}
+			}
+			jobs = append(jobs, failed)
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].At().After(jobs[j].At())
+	})
+	return jobs
+}
+
+// At returns when the job failed, falling back to the run creation time.
+func (f FailedJob) At() time.Time {
+	if !f.Job.CompletedAt.IsZero() {
+		return f.Job.CompletedAt
+	}
+	return f.Run.CreatedAt
+}
+
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`)
+	pathPattern      = regexp.MustCompile(`(?:[A-Za-z]:)?(?:[\w.@~-]*[/\\])+[\w.@-]+`)
+	hexPattern       = regexp.MustCompile(`\b[0-9a-fA-F]{7,}\b`)
+	numberPattern    = regexp.MustCompile(`\d+(?:\.\d+)?`)
+	spacePattern     = regexp.MustCompile(`[ \t]+`)
+)
+
+// NormalizeFailureMessage reduces an error message to a signature by
+// replacing timestamps, file paths, hashes and numbers with placeholders, so
+// the same error reported by different runs compares equal.
+func NormalizeFailureMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		line = timestampPattern.ReplaceAllString(line, "<time>")
+		line = pathPattern.ReplaceAllString(line, "<path>")
+		line = hexPattern.ReplaceAllStringFunc(line, func(s string) string {
+			if strings.ContainsAny(s, "0123456789") {
+				return "<hex>"
+			}
+			return s
+		})
+		line = numberPattern.ReplaceAllString(line, "<n>")
+		line = strings.TrimSpace(spacePattern.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ClusterFailures groups samples by source and normalized message, largest
+// cluster first.
+func ClusterFailures(samples []FailureSample) []FailureCluster {
+	type clusterStat struct {
+		cluster FailureCluster
+		jobs    map[string]struct{}
+		runs    map[int64]struct{}
+	}
+	stats := make(map[string]*clusterStat)
+	var order []string
+
+	for _, sample := range samples {
+		signature := NormalizeFailureMessage(sample.Message)
+		if signature == "" {
+			continue
+		}
+		key := sample.Source + "\x00" + signature
+		st, ok := stats[key]
+		if !ok {
+			st = &clusterStat{
+				cluster: FailureCluster{Signature: signature, Source: sample.Source},
+				jobs:    make(map[string]struct{}),
+				runs:    make(map[int64]struct{}),
+			}
+			stats[key] = st
+			order = append(order, key)
+		}
+
+		c := &st.cluster
+		c.Count++
+		st.jobs[fmt.Sprintf("%s / %s", sample.Workflow, sample.Job)] = struct{}{}
+		st.runs[sample.RunID] = struct{}{}
+		if c.FirstSeen.IsZero() || sample.At.Before(c.FirstSeen) {
+			c.FirstSeen = sample.At
+		}
+		if c.Message == "" || !sample.At.Before(c.LastSeen) {
+			c.LastSeen = sample.At
+			c.Message = sample.Message
+		}
+	}
+
+	clusters := make([]FailureCluster, 0, len(order))
+	for _, key := range order {
+		st := stats[key]
+		c := st.cluster
+		for job := range st.jobs {
+			c.Jobs = append(c.Jobs, job)
+		}
+		sort.Strings(c.Jobs)
+		for run := range st.runs {
+			c.Runs = append(c.Runs, run)
+		}
+		sort.Slice(c.Runs, func(i, j int) bool { return c.Runs[i] < c.Runs[j] })
+		clusters = append(clusters, c)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].LastSeen.After(clusters[j].LastSeen)
+	})
+	return clusters
+}
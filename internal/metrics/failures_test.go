@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestNormalizeFailureMessage(t *testing.T) {
+	got := NormalizeFailureMessage("2025-07-01T09:00:00Z  panic at /home/runner/work/app/main.go:42  (commit 3f2a9c1d)\n\n  took 1.5s")
+	want := "<time> panic at <path>:<n> (commit <hex>)\ntook <n>s"
+	if got != want {
+		t.Fatalf("unexpected signature:\nwant %q\ngot  %q", want, got)
+	}
+	if got := NormalizeFailureMessage("deadline exceeded"); got != "deadline exceeded" {
+		t.Fatalf("expected plain words to survive normalization, got %q", got)
+	}
+}
+
+func TestFailedJobsAndClusters(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	ci := githubapi.Workflow{ID: 1, Name: "ci"}
+	record := func(id int64, offset time.Duration, conclusion string) RunRecord {
+		at := base.Add(offset)
+		return RunRecord{
+			Workflow: ci,
+			Run:      githubapi.WorkflowRun{ID: id, CreatedAt: at, Status: "completed", Conclusion: conclusion},
+			Jobs: []githubapi.WorkflowJob{
+				{ID: id * 10, Name: "test", Status: "completed", Conclusion: conclusion, CompletedAt: at.Add(time.Minute), Steps: []githubapi.WorkflowStep{
+					{Name: "Checkout", Number: 1, Conclusion: "success"},
+					{Name: "Test", Number: 2, Conclusion: conclusion},
+				}},
+				{ID: id*10 + 1, Name: "lint", Status: "completed", Conclusion: "success"},
+			},
+		}
+	}
+	records := []RunRecord{
+		record(1, 0, "failure"),
+		record(2, time.Hour, "failure"),
+		record(3, 2*time.Hour, "success"),
+	}
+
+	jobs := FailedJobs(records, base.Add(-time.Hour), base.Add(3*time.Hour), FailurePolicy{})
+	if len(jobs) != 2 || jobs[0].Run.ID != 2 || jobs[0].Step.Name != "Test" {
+		t.Fatalf("unexpected failed jobs %+v", jobs)
+	}
+
+	var samples []FailureSample
+	for i, job := range jobs {
+		samples = append(samples, FailureSample{
+			Workflow: job.Workflow, Job: job.Job.Name, RunID: job.Run.ID, JobID: job.Job.ID, At: job.At(),
+			Source:  FailureSourceAnnotation,
+			Message: []string{"expected 3 got 4", "expected 1 got 2"}[i],
+		})
+	}
+	samples = append(samples, FailureSample{Workflow: "ci", Job: "test", RunID: 2, At: jobs[0].At(), Source: FailureSourceLog, Message: "Error: Process completed with exit code 1."})
+
+	clusters := ClusterFailures(samples)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", clusters)
+	}
+	top := clusters[0]
+	if top.Count != 2 || top.Signature != "expected <n> got <n>" || top.Message != "expected 3 got 4" {
+		t.Fatalf("unexpected top cluster %+v", top)
+	}
+	if len(top.Jobs) != 1 || top.Jobs[0] != "ci / test" || len(top.Runs) != 2 {
+		t.Fatalf("unexpected cluster membership %+v", top)
+	}
+	if !top.FirstSeen.Equal(base.Add(time.Minute)) || !top.LastSeen.Equal(base.Add(time.Hour+time.Minute)) {
+		t.Fatalf("unexpected first/last seen %+v", top)
+	}
+}
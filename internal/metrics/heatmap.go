@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+)
+
+// HeatmapWeekdays lists the heatmap rows, starting on Monday.
+var HeatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// HeatmapCell aggregates the runs that started in one hour of one weekday.
+type HeatmapCell struct {
+	Weekday     string        `json:"weekday"`
+	Hour        int           `json:"hour"`
+	Runs        int           `json:"runs"`
+	Failed      int           `json:"failed"`
+	FailureRate float64       `json:"failure_rate"`
+	QueueP50    time.Duration `json:"queue_p50"`
+}
+
+// Heatmap holds run counts, failure rates and queue times by weekday and
+// hour of day. Cells are ordered by weekday, Monday first, then by hour.
+type Heatmap struct {
+	Timezone string        `json:"timezone"`
+	Cells    []HeatmapCell `json:"cells"`
+}
+
+// Cell returns the cell for a weekday and hour.
+func (h Heatmap) Cell(weekday time.Weekday, hour int) HeatmapCell {
+	return h.Cells[heatmapIndex(weekday, hour)]
+}
+
+func heatmapIndex(weekday time.Weekday, hour int) int {
+	row := (int(weekday) + 6) % 7 // Monday is row 0.
+	return row*24 + hour
+}
+
+// BuildHeatmap buckets runs started within [from, to] by the weekday and hour
+// of their start time in loc. The queue time of a cell is the median queue
+// time of the jobs of its runs.
+func BuildHeatmap(records []RunRecord, from, to time.Time, loc *time.Location, policy FailurePolicy) Heatmap {
+	heatmap := Heatmap{Timezone: loc.String(), Cells: make([]HeatmapCell, 7*24)}
+	for _, weekday := range HeatmapWeekdays {
+		for hour := 0; hour < 24; hour++ {
+			heatmap.Cells[heatmapIndex(weekday, hour)] = HeatmapCell{Weekday: weekday.String(), Hour: hour}
+		}
+	}
+
+	queues := make([][]time.Duration, len(heatmap.Cells))
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		local := runTime.In(loc)
+		i := heatmapIndex(local.Weekday(), local.Hour())
+		cell := &heatmap.Cells[i]
+		cell.Runs++
+		if policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status) {
+			cell.Failed++
+		}
+		queues[i] = append(queues[i], jobQueueTimes(rec.Jobs)...)
+	}
+
+	for i := range heatmap.Cells {
+		cell := &heatmap.Cells[i]
+		if cell.Runs > 0 {
+			cell.FailureRate = float64(cell.Failed) / float64(cell.Runs)
+		}
+		if len(queues[i]) > 0 {
+			sort.Slice(queues[i], func(a, b int) bool { return queues[i][a] < queues[i][b] })
+			cell.QueueP50 = Percentile(queues[i], 0.5)
+		}
+	}
+	return heatmap
+}
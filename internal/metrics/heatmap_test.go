@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestBuildHeatmap(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	// Tuesday 23:30 UTC is Wednesday 01:30 in Berlin.
+	late := time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC)
+	job := func(queue time.Duration) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{Name: "build", CreatedAt: late, StartedAt: late.Add(queue), CompletedAt: late.Add(queue + time.Minute)}
+	}
+
+	records := []RunRecord{
+		{
+			Workflow: githubapi.Workflow{ID: 1, Name: "nightly"},
+			Run:      githubapi.WorkflowRun{ID: 1, Status: "completed", Conclusion: "failure", RunStartedAt: late},
+			Jobs:     []githubapi.WorkflowJob{job(time.Minute), job(3 * time.Minute)},
+		},
+		{
+			Workflow: githubapi.Workflow{ID: 1, Name: "nightly"},
+			Run:      githubapi.WorkflowRun{ID: 2, Status: "completed", Conclusion: "success", RunStartedAt: late.Add(10 * time.Minute)},
+			Jobs:     []githubapi.WorkflowJob{job(2 * time.Minute)},
+		},
+		{
+			// Outside the window.
+			Workflow: githubapi.Workflow{ID: 1, Name: "nightly"},
+			Run:      githubapi.WorkflowRun{ID: 3, Status: "completed", Conclusion: "failure", RunStartedAt: late.Add(-48 * time.Hour)},
+		},
+	}
+
+	heatmap := BuildHeatmap(records, late.Add(-time.Hour), late.Add(time.Hour), berlin, FailurePolicy{})
+	if heatmap.Timezone != "CEST" || len(heatmap.Cells) != 7*24 {
+		t.Fatalf("unexpected heatmap %s with %d cells", heatmap.Timezone, len(heatmap.Cells))
+	}
+	if first := heatmap.Cells[0]; first.Weekday != "Monday" || first.Hour != 0 {
+		t.Fatalf("expected Monday 00 first, got %+v", first)
+	}
+
+	cell := heatmap.Cell(time.Wednesday, 1)
+	if cell.Runs != 2 || cell.Failed != 1 || cell.FailureRate != 0.5 || cell.QueueP50 != 2*time.Minute {
+		t.Fatalf("unexpected Wednesday 01:00 cell %+v", cell)
+	}
+	if other := heatmap.Cell(time.Tuesday, 23); other.Runs != 0 {
+		t.Fatalf("expected no runs on Tuesday 23:00 in Berlin, got %+v", other)
+	}
+}
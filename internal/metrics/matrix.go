@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MatrixAxisRow aggregates the cells of a matrix job that share one value of
+// a matrix axis, e.g. every os=windows-latest cell of a test job.
+type MatrixAxisRow struct {
+	Workflow            string        `json:"workflow"`
+	WorkflowID          int64         `json:"workflow_id"`
+	Job                 string        `json:"job"`
+	Axis                string        `json:"axis"`
+	Value               string        `json:"value"`
+	Cells               int           `json:"cells"`
+	Runs                int           `json:"runs"`
+	Failed              int           `json:"failed"`
+	FailureRate         float64       `json:"failure_rate"`
+	AvgDuration         time.Duration `json:"avg_duration"`
+	DurationPercentiles Percentiles   `json:"duration_percentiles"`
+	QueuePercentiles    Percentiles   `json:"queue_percentiles"`
+}
+
+// MatrixAxisFunc returns the value of the analyzed axis for a matrix cell of
+// the given workflow and base job name, or false when the cell has no such
+// axis.
+type MatrixAxisFunc func(workflowID int64, job string, values []string) (string, bool)
+
+type axisKey struct {
+	workflowID int64
+	job        string
+	value      string
+}
+
+// AggregateMatrixAxis aggregates matrix job runs in the window by the value of
+// a single matrix axis. Rows are grouped by workflow and job, slowest value
+// first.
+func AggregateMatrixAxis(records []RunRecord, from, to time.Time, axis string, valueOf MatrixAxisFunc, policy FailurePolicy) []MatrixAxisRow {
+	type axisStat struct {
+		row   MatrixAxisRow
+		stat  *jobStat
+		cells map[string]struct{}
+	}
+	stats := make(map[axisKey]*axisStat)
+	midpoint := from.Add(to.Sub(from) / 2)
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, job := range rec.Jobs {
+			name := strings.TrimSpace(job.Name)
+			base, values := SplitMatrixJobName(name)
+			if len(values) == 0 {
+				continue
+			}
+			value, ok := valueOf(rec.Workflow.ID, base, values)
+			if !ok {
+				continue
+			}
+
+			key := axisKey{workflowID: rec.Workflow.ID, job: base, value: value}
+			st, ok := stats[key]
+			if !ok {
+				st = &axisStat{
+					row:   MatrixAxisRow{Workflow: rec.Workflow.Name, WorkflowID: rec.Workflow.ID, Job: base, Axis: axis, Value: value},
+					stat:  newJobStat(base),
+					cells: make(map[string]struct{}),
+				}
+				stats[key] = st
+			}
+			st.stat.add(job, runTime, midpoint, policy.Failed(rec, job.Conclusion, job.Status))
+			st.cells[name] = struct{}{}
+		}
+	}
+
+	rows := make([]MatrixAxisRow, 0, len(stats))
+	for _, st := range stats {
+		row := st.row
+		row.Cells = len(st.cells)
+		row.Runs = st.stat.runs
+		row.Failed = st.stat.failed
+		if row.Runs > 0 {
+			row.FailureRate = float64(row.Failed) / float64(row.Runs)
+			row.AvgDuration = time.Duration(int64(st.stat.duration) / int64(row.Runs))
+		}
+		row.DurationPercentiles = computePercentiles(st.stat.durations)
+		row.QueuePercentiles = computePercentiles(st.stat.queues)
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Workflow != rows[j].Workflow {
+			return rows[i].Workflow < rows[j].Workflow
+		}
+		if rows[i].Job != rows[j].Job {
+			return rows[i].Job < rows[j].Job
+		}
+		if rows[i].AvgDuration != rows[j].AvgDuration {
+			return rows[i].AvgDuration > rows[j].AvgDuration
+		}
+		return rows[i].Value < rows[j].Value
+	})
+	return rows
+}
+
+// AttachMatrixAxis assigns matrix axis rows to their workflow's summary row.
+func AttachMatrixAxis(rows []SummaryRow, axisRows []MatrixAxisRow) {
+	byWorkflow := make(map[int64][]MatrixAxisRow)
+	for _, row := range axisRows {
+		byWorkflow[row.WorkflowID] = append(byWorkflow[row.WorkflowID], row)
+	}
+	for i := range rows {
+		rows[i].MatrixAxis = byWorkflow[rows[i].WorkflowID]
+	}
+}
+
+// SplitMatrixJobName splits a job name such as "test (ubuntu-latest, 1.22)"
+// into its base name and matrix values. Names without a trailing
+// parenthesized list are returned unchanged with no values.
+func SplitMatrixJobName(name string) (string, []string) {
+	if !strings.HasSuffix(name, ")") {
+		return name, nil
+	}
+	open := strings.LastIndex(name, " (")
+	if open <= 0 {
+		return name, nil
+	}
+	inner := name[open+2 : len(name)-1]
+	if strings.TrimSpace(inner) == "" {
+		return name, nil
+	}
+	values := strings.Split(inner, ", ")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return name[:open], values
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestSplitMatrixJobName(t *testing.T) {
+	cases := []struct {
+		name   string
+		base   string
+		values []string
+	}{
+		{"test (ubuntu-latest, 1.22)", "test", []string{"ubuntu-latest", "1.22"}},
+		{"build", "build", nil},
+		{"deploy / publish (linux)", "deploy / publish", []string{"linux"}},
+		{"odd ()", "odd ()", nil},
+		{"(weird)", "(weird)", nil},
+	}
+	for _, tc := range cases {
+		base, values := SplitMatrixJobName(tc.name)
+		if base != tc.base || !reflect.DeepEqual(values, tc.values) {
+			t.Errorf("SplitMatrixJobName(%q) = %q, %v; want %q, %v", tc.name, base, values, tc.base, tc.values)
+		}
+	}
+}
+
+func matrixRecords(base time.Time) []RunRecord {
+	workflow := githubapi.Workflow{ID: 1, Name: "ci"}
+	job := func(name string, minutes int, conclusion string) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        name,
+			Status:      "completed",
+			Conclusion:  conclusion,
+			StartedAt:   base,
+			CompletedAt: base.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+
+	var records []RunRecord
+	for i := 0; i < 2; i++ {
+		records = append(records, RunRecord{
+			Workflow: workflow,
+			Run:      githubapi.WorkflowRun{ID: int64(i + 1), CreatedAt: base},
+			Jobs: []githubapi.WorkflowJob{
+				job("test (ubuntu-latest, 1.24)", 4, "success"),
+				job("test (ubuntu-latest, 1.25)", 6, "success"),
+				job("test (windows-latest, 1.24)", 10, "failure"),
+				job("test (windows-latest, 1.25)", 12, "success"),
+				job("lint (strict)", 1, "success"),
+			},
+		})
+	}
+	return records
+}
+
+func TestAggregateGroupsMatrixJobs(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	rows := Aggregate(matrixRecords(base), base.Add(-time.Hour), base.Add(time.Hour))
+	if len(rows) != 1 || len(rows[0].Jobs) != 2 {
+		t.Fatalf("expected 1 workflow with 2 jobs, got %+v", rows)
+	}
+
+	test, lint := rows[0].Jobs[0], rows[0].Jobs[1]
+	if test.Job != "test" || test.Runs != 8 || test.Failed != 2 || len(test.Matrix) != 4 {
+		t.Fatalf("unexpected matrix group %+v", test)
+	}
+	slowest := test.Matrix[0]
+	if slowest.Job != "test (windows-latest, 1.25)" || !reflect.DeepEqual(slowest.MatrixValues, []string{"windows-latest", "1.25"}) || slowest.Runs != 2 {
+		t.Fatalf("unexpected slowest cell %+v", slowest)
+	}
+
+	// A lone parenthesized name is not treated as a matrix.
+	if lint.Job != "lint (strict)" || lint.Matrix != nil || lint.MatrixValues != nil {
+		t.Fatalf("unexpected non-matrix job %+v", lint)
+	}
+}
+
+func TestAggregateMatrixAxis(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	first := func(_ int64, _ string, values []string) (string, bool) {
+		return values[0], true
+	}
+
+	rows := AggregateMatrixAxis(matrixRecords(base), base.Add(-time.Hour), base.Add(time.Hour), "os", first, FailurePolicy{})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 axis rows, got %+v", rows)
+	}
+
+	strict, windows, ubuntu := rows[0], rows[1], rows[2]
+	if strict.Job != "lint" || strict.Value != "strict" {
+		t.Fatalf("unexpected lint row %+v", strict)
+	}
+	if windows.Job != "test" || windows.Value != "windows-latest" || windows.Cells != 2 || windows.Runs != 4 || windows.Failed != 2 {
+		t.Fatalf("unexpected windows row %+v", windows)
+	}
+	if windows.AvgDuration != 11*time.Minute || windows.FailureRate != 0.5 {
+		t.Fatalf("unexpected windows stats %+v", windows)
+	}
+	if ubuntu.Value != "ubuntu-latest" || ubuntu.AvgDuration != 5*time.Minute || ubuntu.Axis != "os" {
+		t.Fatalf("unexpected ubuntu row %+v", ubuntu)
+	}
+
+	summary := []SummaryRow{{WorkflowID: 1}, {WorkflowID: 2}}
+	AttachMatrixAxis(summary, rows)
+	if len(summary[0].MatrixAxis) != 3 || summary[1].MatrixAxis != nil {
+		t.Fatalf("unexpected attached axis rows %+v", summary)
+	}
+}
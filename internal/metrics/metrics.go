@@ -1,11 +1,13 @@
 package metrics
 
 import (
+	"math"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
 )
 
 // RunRecord bundles a workflow run with metadata needed for aggregation.
@@ -13,6 +15,9 @@ type RunRecord struct {
 	Workflow githubapi.Workflow
 	Run      githubapi.WorkflowRun
 	Jobs     []githubapi.WorkflowJob
+	// Superseded is set on cancelled runs that a newer run replaced; see
+	// MarkSuperseded.
+	Superseded bool
 }
 
 // RunnerUsage summarizes usage per runner label.
@@ -22,33 +27,80 @@ type RunnerUsage struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// Percentiles holds selected percentiles of a duration distribution.
+type Percentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+}
+
+// Trend compares the later half of the reporting window with the earlier half.
+// DurationChange is the relative change in average duration (0.1 = 10% slower)
+// and FailureRateChange is the absolute change in failure rate.
+type Trend struct {
+	DurationChange    float64 `json:"duration_change"`
+	FailureRateChange float64 `json:"failure_rate_change"`
+}
+
 // SummaryRow represents aggregated metrics for a workflow.
 type SummaryRow struct {
-	Workflow      string          `json:"workflow"`
-	WorkflowID    int64           `json:"workflow_id"`
-	Runs          int             `json:"runs"`
-	Failed        int             `json:"failed"`
-	FailureRate   float64         `json:"failure_rate"`
-	AvgDuration   time.Duration   `json:"avg_duration"`
-	TotalDuration time.Duration   `json:"total_duration"`
-	RunnerSummary []RunnerUsage   `json:"runner_summary"`
-	Jobs          []JobSummaryRow `json:"jobs"`
+	Workflow            string          `json:"workflow"`
+	WorkflowID          int64           `json:"workflow_id"`
+	Runs                int             `json:"runs"`
+	Failed              int             `json:"failed"`
+	FailureRate         float64         `json:"failure_rate"`
+	AvgDuration         time.Duration   `json:"avg_duration"`
+	TotalDuration       time.Duration   `json:"total_duration"`
+	DurationPercentiles Percentiles     `json:"duration_percentiles"`
+	QueuePercentiles    Percentiles     `json:"queue_percentiles"`
+	Trend               Trend           `json:"trend"`
+	Conclusions         map[string]int  `json:"conclusions,omitempty"`
+	Superseded          int             `json:"superseded"`
+	RunnerSummary       []RunnerUsage   `json:"runner_summary"`
+	Jobs                []JobSummaryRow `json:"jobs"`
+	Alerts              []Alert         `json:"alerts,omitempty"`
+	MatrixAxis          []MatrixAxisRow `json:"matrix_axis,omitempty"`
+	// Daily is set by AttachDailyTrends.
+	Daily []DailyStat `json:"daily,omitempty"`
+	// Changes is set by AttachWorkflowChanges.
+	Changes []WorkflowChange `json:"changes,omitempty"`
 }
 
 // JobSummaryRow represents aggregated metrics for a workflow job.
 type JobSummaryRow struct {
-	Job           string        `json:"job"`
-	Runs          int           `json:"runs"`
-	Failed        int           `json:"failed"`
-	FailureRate   float64       `json:"failure_rate"`
-	AvgDuration   time.Duration `json:"avg_duration"`
-	TotalDuration time.Duration `json:"total_duration"`
-	RunnerSummary []RunnerUsage `json:"runner_summary"`
+	Job                 string         `json:"job"`
+	Runs                int            `json:"runs"`
+	Failed              int            `json:"failed"`
+	FailureRate         float64        `json:"failure_rate"`
+	AvgDuration         time.Duration  `json:"avg_duration"`
+	TotalDuration       time.Duration  `json:"total_duration"`
+	DurationPercentiles Percentiles    `json:"duration_percentiles"`
+	QueuePercentiles    Percentiles    `json:"queue_percentiles"`
+	Trend               Trend          `json:"trend"`
+	Conclusions         map[string]int `json:"conclusions,omitempty"`
+	RunnerSummary       []RunnerUsage  `json:"runner_summary"`
+	// Config is the job as declared in the workflow file, when known.
+	Config *workflowfile.Job `json:"config,omitempty"`
+	// MatrixValues are the matrix values of a single matrix cell.
+	MatrixValues []string `json:"matrix_values,omitempty"`
+	// Matrix breaks a matrix job down per cell; the row itself aggregates
+	// every cell.
+	Matrix []JobSummaryRow `json:"matrix,omitempty"`
+	// Daily is set by AttachDailyTrends.
+	Daily []DailyStat `json:"daily,omitempty"`
 }
 
 // Aggregate computes summary rows for the provided records, grouped by workflow.
 func Aggregate(records []RunRecord, from, to time.Time) []SummaryRow {
+	return AggregateWithPolicy(records, from, to, FailurePolicy{})
+}
+
+// AggregateWithPolicy is Aggregate with a custom rule for which conclusions
+// count as failures.
+func AggregateWithPolicy(records []RunRecord, from, to time.Time, policy FailurePolicy) []SummaryRow {
 	workflowStats := make(map[int64]*workflowStat)
+	midpoint := from.Add(to.Sub(from) / 2)
 
 	for _, rec := range records {
 		runTime := rec.Run.RunStartedAt
@@ -62,26 +114,35 @@ func Aggregate(records []RunRecord, from, to time.Time) []SummaryRow {
 		stat, ok := workflowStats[rec.Workflow.ID]
 		if !ok {
 			stat = &workflowStat{
-				workflow:   rec.Workflow.Name,
-				workflowID: rec.Workflow.ID,
-				runner:     make(map[string]*runnerStat),
-				jobs:       make(map[string]*jobStat),
+				workflow:    rec.Workflow.Name,
+				workflowID:  rec.Workflow.ID,
+				runner:      make(map[string]*runnerStat),
+				jobs:        make(map[string]*jobStat),
+				conclusions: make(map[string]int),
 			}
 			workflowStats[rec.Workflow.ID] = stat
 		}
 
 		stat.runs++
+		stat.conclusions[conclusionKey(rec.Run.Conclusion, rec.Run.Status)]++
+		if rec.Superseded {
+			stat.superseded++
+		}
 
-		if isFailure(rec.Run.Conclusion, rec.Run.Status) {
+		failed := policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status)
+		if failed {
 			stat.failed++
 		}
 
 		duration := rec.Run.Duration
 		stat.duration += duration
+		stat.durations = append(stat.durations, duration)
+		stat.halves[halfIndex(runTime, midpoint)].add(duration, failed)
 
 		if len(rec.Jobs) > 0 {
+			stat.queues = append(stat.queues, jobQueueTimes(rec.Jobs)...)
 			accumulateRunnerStats(stat.runner, rec.Jobs)
-			accumulateJobStats(stat.jobs, rec.Jobs)
+			accumulateJobStats(stat.jobs, rec, midpoint, policy)
 		}
 	}
 
@@ -100,6 +161,11 @@ func Aggregate(records []RunRecord, from, to time.Time) []SummaryRow {
 			row.FailureRate = float64(stat.failed) / float64(stat.runs)
 		}
 
+		row.DurationPercentiles = computePercentiles(stat.durations)
+		row.QueuePercentiles = computePercentiles(stat.queues)
+		row.Trend = compareHalves(stat.halves)
+		row.Conclusions = stat.conclusions
+		row.Superseded = stat.superseded
 		row.RunnerSummary = flattenRunnerStats(stat.runner)
 		row.Jobs = flattenJobStats(stat.jobs)
 		rows = append(rows, row)
@@ -113,13 +179,33 @@ func Aggregate(records []RunRecord, from, to time.Time) []SummaryRow {
 }
 
 type workflowStat struct {
-	workflow   string
-	workflowID int64
-	runs       int
-	failed     int
-	duration   time.Duration
-	runner     map[string]*runnerStat
-	jobs       map[string]*jobStat
+	workflow    string
+	workflowID  int64
+	runs        int
+	failed      int
+	superseded  int
+	duration    time.Duration
+	durations   []time.Duration
+	queues      []time.Duration
+	halves      [2]halfStat
+	conclusions map[string]int
+	runner      map[string]*runnerStat
+	jobs        map[string]*jobStat
+}
+
+// halfStat accumulates runs falling into one half of the reporting window.
+type halfStat struct {
+	runs     int
+	failed   int
+	duration time.Duration
+}
+
+func (h *halfStat) add(duration time.Duration, failed bool) {
+	h.runs++
+	h.duration += duration
+	if failed {
+		h.failed++
+	}
 }
 
 type runnerStat struct {
@@ -128,11 +214,47 @@ type runnerStat struct {
 }
 
 type jobStat struct {
-	name     string
-	runs     int
-	failed   int
-	duration time.Duration
-	runner   map[string]*runnerStat
+	name        string
+	runs        int
+	failed      int
+	duration    time.Duration
+	durations   []time.Duration
+	queues      []time.Duration
+	halves      [2]halfStat
+	conclusions map[string]int
+	runner      map[string]*runnerStat
+	values      []string
+	cells       map[string]*jobStat
+}
+
+func newJobStat(name string) *jobStat {
+	return &jobStat{
+		name:        name,
+		conclusions: make(map[string]int),
+		runner:      make(map[string]*runnerStat),
+	}
+}
+
+// add records a job run. runTime places jobs that never started, such as
+// skipped or cancelled ones, in the half of the window their run belongs to.
+func (s *jobStat) add(job githubapi.WorkflowJob, runTime, midpoint time.Time, failed bool) {
+	s.runs++
+	s.conclusions[conclusionKey(job.Conclusion, job.Status)]++
+	if failed {
+		s.failed++
+	}
+
+	duration := job.Duration()
+	s.duration += duration
+	s.durations = append(s.durations, duration)
+	started := job.StartedAt
+	if started.IsZero() {
+		started = runTime
+	}
+	s.halves[halfIndex(started, midpoint)].add(duration, failed)
+	s.queues = append(s.queues, jobQueueTimes([]githubapi.WorkflowJob{job})...)
+
+	accumulateRunnerStats(s.runner, []githubapi.WorkflowJob{job})
 }
 
 func accumulateRunnerStats(stats map[string]*runnerStat, jobs []githubapi.WorkflowJob) {
@@ -167,32 +289,54 @@ func accumulateRunnerStats(stats map[string]*runnerStat, jobs []githubapi.Workfl
 	}
 }
 
-func accumulateJobStats(stats map[string]*jobStat, jobs []githubapi.WorkflowJob) {
-	for _, job := range jobs {
+// accumulateJobStats groups the jobs of a run by name. Matrix jobs are
+// grouped under their base name and additionally tracked per cell.
+func accumulateJobStats(stats map[string]*jobStat, rec RunRecord, midpoint time.Time, policy FailurePolicy) {
+	runTime := rec.Run.RunStartedAt
+	if runTime.IsZero() {
+		runTime = rec.Run.CreatedAt
+	}
+	for _, job := range rec.Jobs {
+		failed := policy.Failed(rec, job.Conclusion, job.Status)
 		name := strings.TrimSpace(job.Name)
 		if name == "" {
 			name = "(unnamed)"
 		}
+		base, values := SplitMatrixJobName(name)
 
-		stat, ok := stats[name]
+		stat, ok := stats[base]
 		if !ok {
-			stat = &jobStat{
-				name:   name,
-				runner: make(map[string]*runnerStat),
-			}
-			stats[name] = stat
+			stat = newJobStat(base)
+			stats[base] = stat
 		}
+		stat.add(job, runTime, midpoint, failed)
 
-		stat.runs++
-		if isFailure(job.Conclusion, job.Status) {
-			stat.failed++
+		if len(values) == 0 {
+			continue
 		}
+		if stat.cells == nil {
+			stat.cells = make(map[string]*jobStat)
+		}
+		cell, ok := stat.cells[name]
+		if !ok {
+			cell = newJobStat(name)
+			cell.values = values
+			stat.cells[name] = cell
+		}
+		cell.add(job, runTime, midpoint, failed)
+	}
+}
 
-		duration := job.Duration()
-		stat.duration += duration
-
-		accumulateRunnerStats(stat.runner, []githubapi.WorkflowJob{job})
+// jobQueueTimes returns the queue times of jobs that actually started.
+func jobQueueTimes(jobs []githubapi.WorkflowJob) []time.Duration {
+	out := make([]time.Duration, 0, len(jobs))
+	for _, job := range jobs {
+		if job.CreatedAt.IsZero() || job.StartedAt.IsZero() {
+			continue
+		}
+		out = append(out, job.QueueTime())
 	}
+	return out
 }
 
 func flattenRunnerStats(stats map[string]*runnerStat) []RunnerUsage {
@@ -226,11 +370,23 @@ func flattenJobStats(stats map[string]*jobStat) []JobSummaryRow {
 
 	out := make([]JobSummaryRow, 0, len(stats))
 	for _, stat := range stats {
+		// A single cell that accounts for every run is just a job whose name
+		// happens to end in parentheses.
+		if len(stat.cells) == 1 {
+			for _, cell := range stat.cells {
+				if cell.runs == stat.runs {
+					stat = cell
+					stat.values = nil
+				}
+			}
+		}
+
 		row := JobSummaryRow{
 			Job:           stat.name,
 			Runs:          stat.runs,
 			Failed:        stat.failed,
 			TotalDuration: stat.duration,
+			MatrixValues:  stat.values,
 		}
 
 		if stat.runs > 0 {
@@ -238,7 +394,14 @@ func flattenJobStats(stats map[string]*jobStat) []JobSummaryRow {
 			row.FailureRate = float64(stat.failed) / float64(stat.runs)
 		}
 
+		row.DurationPercentiles = computePercentiles(stat.durations)
+		row.QueuePercentiles = computePercentiles(stat.queues)
+		row.Trend = compareHalves(stat.halves)
+		row.Conclusions = stat.conclusions
 		row.RunnerSummary = flattenRunnerStats(stat.runner)
+		if len(stat.cells) > 0 {
+			row.Matrix = flattenJobStats(stat.cells)
+		}
 		out = append(out, row)
 	}
 
@@ -252,16 +415,67 @@ func flattenJobStats(stats map[string]*jobStat) []JobSummaryRow {
 	return out
 }
 
-func isFailure(conclusion, status string) bool {
-	v := strings.ToLower(conclusion)
-	switch v {
-	case "failure", "failed", "cancelled", "timed_out", "action_required", "stale":
-		return true
-	case "":
-		// fall back to status when conclusion is empty (in-progress runs)
-		s := strings.ToLower(status)
-		return s == "failure"
-	default:
-		return false
+// Overall aggregates every record into a single row regardless of workflow.
+func Overall(records []RunRecord, from, to time.Time, policy FailurePolicy) SummaryRow {
+	merged := make([]RunRecord, len(records))
+	for i, rec := range records {
+		merged[i] = RunRecord{Run: rec.Run, Superseded: rec.Superseded}
+	}
+	rows := AggregateWithPolicy(merged, from, to, policy)
+	if len(rows) == 0 {
+		return SummaryRow{}
+	}
+	row := rows[0]
+	row.Workflow = ""
+	return row
+}
+
+// Percentile returns the p-th percentile (0 < p <= 1) of values using the
+// nearest-rank method. values must be sorted in ascending order.
+func Percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(values) {
+		rank = len(values) - 1
+	}
+	return values[rank]
+}
+
+func computePercentiles(durations []time.Duration) Percentiles {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return Percentiles{
+		P50: Percentile(sorted, 0.50),
+		P90: Percentile(sorted, 0.90),
+		P95: Percentile(sorted, 0.95),
+		P99: Percentile(sorted, 0.99),
+	}
+}
+
+func halfIndex(t, midpoint time.Time) int {
+	if t.Before(midpoint) {
+		return 0
+	}
+	return 1
+}
+
+func compareHalves(halves [2]halfStat) Trend {
+	earlier, later := halves[0], halves[1]
+	if earlier.runs == 0 || later.runs == 0 {
+		return Trend{}
+	}
+
+	var trend Trend
+	earlierAvg := float64(earlier.duration) / float64(earlier.runs)
+	laterAvg := float64(later.duration) / float64(later.runs)
+	if earlierAvg > 0 {
+		trend.DurationChange = (laterAvg - earlierAvg) / earlierAvg
 	}
+	trend.FailureRateChange = float64(later.failed)/float64(later.runs) - float64(earlier.failed)/float64(earlier.runs)
+	return trend
 }
@@ -181,3 +181,88 @@ func TestAggregateMultipleRuns(t *testing.T) {
 		t.Fatalf("expected total 6h, got %s", rows[0].TotalDuration)
 	}
 }
+
+func TestAggregatePercentilesAndTrend(t *testing.T) {
+	base := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "build"}
+
+	var records []RunRecord
+	for i, minutes := range []int{10, 10, 10, 20, 20, 20} {
+		conclusion := "success"
+		if i == 5 {
+			conclusion = "failure"
+		}
+		records = append(records, RunRecord{Workflow: workflow, Run: githubapi.WorkflowRun{
+			ID:         int64(i + 1),
+			WorkflowID: 1,
+			Status:     "completed",
+			Conclusion: conclusion,
+			CreatedAt:  base.Add(time.Duration(i) * 24 * time.Hour),
+			Duration:   time.Duration(minutes) * time.Minute,
+		}})
+	}
+
+	rows := Aggregate(records, base, base.Add(6*24*time.Hour))
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.DurationPercentiles.P50 != 10*time.Minute {
+		t.Fatalf("expected p50 10m, got %s", row.DurationPercentiles.P50)
+	}
+	if row.DurationPercentiles.P95 != 20*time.Minute {
+		t.Fatalf("expected p95 20m, got %s", row.DurationPercentiles.P95)
+	}
+	if row.Trend.DurationChange != 1 {
+		t.Fatalf("expected duration to double, got %v", row.Trend.DurationChange)
+	}
+	if want := 1.0 / 3; row.Trend.FailureRateChange != want {
+		t.Fatalf("expected failure rate change %v, got %v", want, row.Trend.FailureRateChange)
+	}
+
+	overall := Overall(records, base, base.Add(6*24*time.Hour), FailurePolicy{})
+	if overall.Runs != 6 || overall.Failed != 1 {
+		t.Fatalf("unexpected overall row %#v", overall)
+	}
+}
+
+func TestAggregateJobTrendUnstartedJobs(t *testing.T) {
+	base := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "build"}
+
+	var records []RunRecord
+	for i := range 4 {
+		created := base.Add(time.Duration(i) * 24 * time.Hour)
+		job := githubapi.WorkflowJob{Name: "deploy", Status: "completed", Conclusion: "success", StartedAt: created, CompletedAt: created.Add(time.Minute)}
+		if i >= 2 {
+			// Cancelled before a runner picked the job up.
+			job = githubapi.WorkflowJob{Name: "deploy", Status: "completed", Conclusion: "cancelled"}
+		}
+		records = append(records, RunRecord{
+			Workflow: workflow,
+			Run:      githubapi.WorkflowRun{ID: int64(i + 1), WorkflowID: 1, Status: "completed", Conclusion: "success", CreatedAt: created},
+			Jobs:     []githubapi.WorkflowJob{job},
+		})
+	}
+
+	rows := Aggregate(records, base, base.Add(4*24*time.Hour))
+	if len(rows) != 1 || len(rows[0].Jobs) != 1 {
+		t.Fatalf("expected one workflow with one job, got %#v", rows)
+	}
+	if got := rows[0].Jobs[0].Trend.FailureRateChange; got != 1 {
+		t.Fatalf("expected unstarted jobs to count in the later half, got failure rate change %v", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got := Percentile(values, 0.9); got != 9 {
+		t.Fatalf("expected p90=9, got %d", got)
+	}
+	if got := Percentile(values, 1); got != 10 {
+		t.Fatalf("expected p100=10, got %d", got)
+	}
+	if got := Percentile(nil, 0.5); got != 0 {
+		t.Fatalf("expected 0 for empty input, got %d", got)
+	}
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// PullRequestRow reports the CI cost of one pull request.
+type PullRequestRow struct {
+	Number int    `json:"number"`
+	Branch string `json:"branch"`
+	// Runs counts workflow runs; Reruns counts the extra attempts made by
+	// re-running them.
+	Runs   int `json:"runs"`
+	Reruns int `json:"reruns"`
+	// Pushes counts the distinct head commits CI ran on.
+	Pushes int `json:"pushes"`
+	Failed int `json:"failed"`
+	// CITime is the runner time spent on the pull request: the sum of its
+	// job durations, or of run durations for runs without job data.
+	CITime    time.Duration `json:"ci_time"`
+	FirstPush time.Time     `json:"first_push"`
+	// GreenAt is when every workflow run for a head commit first finished
+	// successfully; zero if that never happened in the window.
+	GreenAt     time.Time     `json:"green_at,omitempty"`
+	TimeToGreen time.Duration `json:"time_to_green,omitempty"`
+}
+
+// PullRequestReport summarizes the CI cost of pull requests.
+type PullRequestReport struct {
+	PullRequests      int              `json:"pull_requests"`
+	CITime            time.Duration    `json:"ci_time"`
+	MedianCITime      time.Duration    `json:"median_ci_time"`
+	Green             int              `json:"green"`
+	MedianTimeToGreen time.Duration    `json:"median_time_to_green"`
+	P90TimeToGreen    time.Duration    `json:"p90_time_to_green"`
+	Rows              []PullRequestRow `json:"rows"`
+}
+
+// runnerTime is the runner time a run consumed: the sum of its job durations,
+// or the run duration when no job data is available.
+func runnerTime(rec RunRecord) time.Duration {
+	var total time.Duration
+	for _, job := range rec.Jobs {
+		total += job.Duration()
+	}
+	if total == 0 {
+		return rec.Run.Duration
+	}
+	return total
+}
+
+// greenConclusion reports whether a run concluded without anything to fix.
+func greenConclusion(conclusion string) bool {
+	switch strings.ToLower(conclusion) {
+	case "success", "neutral", "skipped":
+		return true
+	}
+	return false
+}
+
+// PullRequests groups runs started within [from, to] by the pull requests
+// they belong to. Time to green runs from the first push CI saw to the moment
+// the runs of every workflow for one head commit had all finished green,
+// judging each workflow by its latest run on that commit. Rows are ordered by
+// CI time, largest first.
+func PullRequests(records []RunRecord, from, to time.Time, policy FailurePolicy) PullRequestReport {
+	type prStat struct {
+		row   PullRequestRow
+		shas  map[string][]RunRecord
+		order []string
+	}
+	stats := make(map[int]*prStat)
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, number := range rec.Run.PullRequests {
+			st, ok := stats[number]
+			if !ok {
+				st = &prStat{
+					row:  PullRequestRow{Number: number, Branch: rec.Run.HeadBranch},
+					shas: make(map[string][]RunRecord),
+				}
+				stats[number] = st
+			}
+
+			st.row.Runs++
+			if rec.Run.RunAttempt > 1 {
+				st.row.Reruns += rec.Run.RunAttempt - 1
+			}
+			if policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status) {
+				st.row.Failed++
+			}
+			st.row.CITime += runnerTime(rec)
+			if st.row.FirstPush.IsZero() || rec.Run.CreatedAt.Before(st.row.FirstPush) {
+				st.row.FirstPush = rec.Run.CreatedAt
+			}
+			if _, seen := st.shas[rec.Run.HeadSHA]; !seen {
+				st.order = append(st.order, rec.Run.HeadSHA)
+			}
+			st.shas[rec.Run.HeadSHA] = append(st.shas[rec.Run.HeadSHA], rec)
+		}
+	}
+
+	report := PullRequestReport{PullRequests: len(stats)}
+	var ciTimes, toGreen []time.Duration
+	for _, st := range stats {
+		row := st.row
+		row.Pushes = len(st.shas)
+		for _, sha := range st.order {
+			greenAt, ok := commitGreenAt(st.shas[sha])
+			if ok && (row.GreenAt.IsZero() || greenAt.Before(row.GreenAt)) {
+				row.GreenAt = greenAt
+			}
+		}
+		if !row.GreenAt.IsZero() {
+			row.TimeToGreen = row.GreenAt.Sub(row.FirstPush)
+			report.Green++
+			toGreen = append(toGreen, row.TimeToGreen)
+		}
+		report.CITime += row.CITime
+		ciTimes = append(ciTimes, row.CITime)
+		report.Rows = append(report.Rows, row)
+	}
+
+	sort.Slice(ciTimes, func(i, j int) bool { return ciTimes[i] < ciTimes[j] })
+	sort.Slice(toGreen, func(i, j int) bool { return toGreen[i] < toGreen[j] })
+	report.MedianCITime = Percentile(ciTimes, 0.5)
+	report.MedianTimeToGreen = Percentile(toGreen, 0.5)
+	report.P90TimeToGreen = Percentile(toGreen, 0.9)
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].CITime != report.Rows[j].CITime {
+			return report.Rows[i].CITime > report.Rows[j].CITime
+		}
+		return report.Rows[i].Number > report.Rows[j].Number
+	})
+	return report
+}
+
+// commitGreenAt returns when the runs of one head commit were all green,
+// judging each workflow by its latest run.
+func commitGreenAt(runs []RunRecord) (time.Time, bool) {
+	latest := make(map[int64]RunRecord)
+	for _, rec := range runs {
+		current, ok := latest[rec.Workflow.ID]
+		if !ok || rec.Run.CreatedAt.After(current.Run.CreatedAt) ||
+			(rec.Run.CreatedAt.Equal(current.Run.CreatedAt) && rec.Run.RunAttempt > current.Run.RunAttempt) {
+			latest[rec.Workflow.ID] = rec
+		}
+	}
+
+	var greenAt time.Time
+	for _, rec := range latest {
+		if !strings.EqualFold(rec.Run.Status, "completed") || !greenConclusion(rec.Run.Conclusion) {
+			return time.Time{}, false
+		}
+		if finished := runFinishedAt(rec); finished.After(greenAt) {
+			greenAt = finished
+		}
+	}
+	return greenAt, !greenAt.IsZero()
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestPullRequests(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	ci := githubapi.Workflow{ID: 1, Name: "ci"}
+	lint := githubapi.Workflow{ID: 2, Name: "lint"}
+	run := func(wf githubapi.Workflow, pr int, sha, conclusion string, created time.Duration, minutes, attempt int) RunRecord {
+		start := base.Add(created)
+		duration := time.Duration(minutes) * time.Minute
+		return RunRecord{
+			Workflow: wf,
+			Run: githubapi.WorkflowRun{
+				ID:           int64(created),
+				HeadBranch:   "feature",
+				HeadSHA:      sha,
+				Status:       "completed",
+				Conclusion:   conclusion,
+				CreatedAt:    start,
+				RunStartedAt: start,
+				UpdatedAt:    start.Add(duration),
+				Duration:     duration,
+				RunAttempt:   attempt,
+				PullRequests: []int{pr},
+			},
+			Jobs: []githubapi.WorkflowJob{{Name: "build", StartedAt: start, CompletedAt: start.Add(duration)}},
+		}
+	}
+
+	records := []RunRecord{
+		// First push fails ci; the fix turns everything green after a re-run of lint.
+		run(ci, 7, "a", "failure", 0, 10, 1),
+		run(lint, 7, "a", "success", 0, 2, 1),
+		run(ci, 7, "b", "success", time.Hour, 10, 1),
+		run(lint, 7, "b", "success", time.Hour, 5, 2),
+		// A pull request that never went green.
+		run(ci, 8, "c", "failure", 0, 30, 1),
+		// Runs without a pull request are ignored.
+		{Workflow: ci, Run: githubapi.WorkflowRun{ID: 99, CreatedAt: base, Duration: time.Hour}},
+	}
+
+	report := PullRequests(records, base.Add(-time.Hour), base.Add(2*time.Hour), FailurePolicy{})
+	if report.PullRequests != 2 || len(report.Rows) != 2 || report.Green != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	never, green := report.Rows[0], report.Rows[1]
+	if never.Number != 8 || !never.GreenAt.IsZero() || never.CITime != 30*time.Minute {
+		t.Fatalf("unexpected never-green row %+v", never)
+	}
+	if green.Number != 7 || green.Runs != 4 || green.Pushes != 2 || green.Reruns != 1 || green.Failed != 1 {
+		t.Fatalf("unexpected green row %+v", green)
+	}
+	if green.CITime != 27*time.Minute || green.TimeToGreen != 70*time.Minute {
+		t.Fatalf("unexpected CI time or time to green %+v", green)
+	}
+	if report.CITime != 57*time.Minute || report.MedianTimeToGreen != 70*time.Minute {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+}
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Outage is a streak of consecutive failing runs of a workflow on a branch,
+// from the first failing run until the next successful run completed.
+type Outage struct {
+	Workflow      string        `json:"workflow"`
+	WorkflowID    int64         `json:"workflow_id"`
+	Branch        string        `json:"branch"`
+	Start         time.Time     `json:"start"`
+	End           time.Time     `json:"end"`
+	Duration      time.Duration `json:"duration"`
+	FailedRuns    int           `json:"failed_runs"`
+	FirstRunID    int64         `json:"first_run_id"`
+	RecoveryRunID int64         `json:"recovery_run_id,omitempty"`
+	Recovered     bool          `json:"recovered"`
+}
+
+// RecoveryRow summarizes red streaks and time to recovery for a workflow on a
+// branch.
+type RecoveryRow struct {
+	Workflow          string        `json:"workflow"`
+	WorkflowID        int64         `json:"workflow_id"`
+	Branch            string        `json:"branch"`
+	Runs              int           `json:"runs"`
+	Outages           int           `json:"outages"`
+	Recovered         int           `json:"recovered"`
+	MeanTimeToRecover time.Duration `json:"mttr"`
+	MTTRPercentiles   Percentiles   `json:"mttr_percentiles"`
+	LongestOutage     time.Duration `json:"longest_outage"`
+	LongestStreak     int           `json:"longest_streak"`
+	Red               bool          `json:"red"`
+}
+
+type recoveryKey struct {
+	workflowID int64
+	branch     string
+}
+
+// Recovery reconstructs red streaks from the run history of every workflow and
+// branch. A streak starts with the first failing run after a success (or at
+// the start of the history) and ends when the next run succeeds. Runs that
+// policy does not count as failures, such as skipped, in-progress and
+// superseded runs, neither start nor end a streak. Streaks still
+// open at the end of the history are measured up to `to` and reported as not
+// recovered. Rows are ordered by workflow and branch; outages are returned
+// longest first.
+func Recovery(records []RunRecord, to time.Time, policy FailurePolicy) ([]RecoveryRow, []Outage) {
+	byKey := make(map[recoveryKey][]RunRecord)
+	for _, rec := range records {
+		key := recoveryKey{workflowID: rec.Workflow.ID, branch: rec.Run.HeadBranch}
+		byKey[key] = append(byKey[key], rec)
+	}
+
+	var (
+		rows    []RecoveryRow
+		outages []Outage
+	)
+	for key, recs := range byKey {
+		sort.SliceStable(recs, func(i, j int) bool {
+			return recs[i].Run.CreatedAt.Before(recs[j].Run.CreatedAt)
+		})
+
+		row := RecoveryRow{
+			Workflow:   recs[0].Workflow.Name,
+			WorkflowID: key.workflowID,
+			Branch:     key.branch,
+			Runs:       len(recs),
+		}
+
+		var (
+			current   *Outage
+			recovered []time.Duration
+			total     time.Duration
+		)
+		for _, rec := range recs {
+			switch runOutcome(rec, policy) {
+			case outcomeRed:
+				if current == nil {
+					current = &Outage{
+						Workflow:   row.Workflow,
+						WorkflowID: row.WorkflowID,
+						Branch:     row.Branch,
+						Start:      rec.Run.CreatedAt,
+						FirstRunID: rec.Run.ID,
+					}
+				}
+				current.FailedRuns++
+			case outcomeGreen:
+				if current == nil {
+					continue
+				}
+				current.End = runFinishedAt(rec)
+				current.Duration = current.End.Sub(current.Start)
+				current.RecoveryRunID = rec.Run.ID
+				current.Recovered = true
+				recovered = append(recovered, current.Duration)
+				total += current.Duration
+				outages = append(outages, *current)
+				row.addOutage(*current)
+				current = nil
+			}
+		}
+		if current != nil {
+			if to.After(current.Start) {
+				current.Duration = to.Sub(current.Start)
+			}
+			outages = append(outages, *current)
+			row.addOutage(*current)
+			row.Red = true
+		}
+
+		row.Recovered = len(recovered)
+		if len(recovered) > 0 {
+			row.MeanTimeToRecover = total / time.Duration(len(recovered))
+		}
+		row.MTTRPercentiles = computePercentiles(recovered)
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Workflow != rows[j].Workflow {
+			return rows[i].Workflow < rows[j].Workflow
+		}
+		return rows[i].Branch < rows[j].Branch
+	})
+	sort.SliceStable(outages, func(i, j int) bool {
+		if outages[i].Duration != outages[j].Duration {
+			return outages[i].Duration > outages[j].Duration
+		}
+		return outages[i].Start.Before(outages[j].Start)
+	})
+
+	return rows, outages
+}
+
+func (r *RecoveryRow) addOutage(o Outage) {
+	r.Outages++
+	if o.Duration > r.LongestOutage {
+		r.LongestOutage = o.Duration
+	}
+	if o.FailedRuns > r.LongestStreak {
+		r.LongestStreak = o.FailedRuns
+	}
+}
+
+type outcome int
+
+const (
+	outcomeNeutral outcome = iota
+	outcomeGreen
+	outcomeRed
+)
+
+// runOutcome classifies a run for streak analysis. Runs that neither succeeded
+// nor failed under policy are neutral.
+func runOutcome(rec RunRecord, policy FailurePolicy) outcome {
+	if strings.EqualFold(rec.Run.Conclusion, "success") {
+		return outcomeGreen
+	}
+	if policy.Failed(rec, rec.Run.Conclusion, rec.Run.Status) {
+		return outcomeRed
+	}
+	return outcomeNeutral
+}
+
+// runFinishedAt approximates when a run completed.
+func runFinishedAt(rec RunRecord) time.Time {
+	if !rec.Run.UpdatedAt.IsZero() {
+		return rec.Run.UpdatedAt
+	}
+	start := rec.Run.RunStartedAt
+	if start.IsZero() {
+		start = rec.Run.CreatedAt
+	}
+	return start.Add(rec.Run.Duration)
+}
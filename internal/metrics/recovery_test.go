@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestRecovery(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 3, Name: "ci"}
+
+	conclusions := []string{"success", "failure", "cancelled", "failure", "success", "success", "failure", "timed_out", "success", "failure"}
+	var records []RunRecord
+	for i, conclusion := range conclusions {
+		created := base.Add(time.Duration(i) * time.Hour)
+		records = append(records, RunRecord{Workflow: workflow, Run: githubapi.WorkflowRun{
+			ID:         int64(i + 1),
+			WorkflowID: workflow.ID,
+			Status:     "completed",
+			Conclusion: conclusion,
+			HeadBranch: "main",
+			CreatedAt:  created,
+			UpdatedAt:  created.Add(30 * time.Minute),
+		}})
+	}
+	// The cancellation inside the first outage was superseded, so it neither
+	// extends nor ends the streak.
+	records[2].Superseded = true
+	// A failing run on another branch is tracked separately.
+	records = append(records, RunRecord{Workflow: workflow, Run: githubapi.WorkflowRun{
+		ID: 99, WorkflowID: workflow.ID, Status: "completed", Conclusion: "failure", HeadBranch: "feature", CreatedAt: base,
+	}})
+
+	to := base.Add(12 * time.Hour)
+	rows, outages := Recovery(records, to, FailurePolicy{})
+	if len(rows) != 2 {
+		t.Fatalf("expected rows for 2 branches, got %d", len(rows))
+	}
+
+	feature, main := rows[0], rows[1]
+	if feature.Branch != "feature" || !feature.Red || feature.Recovered != 0 {
+		t.Fatalf("unexpected feature row %#v", feature)
+	}
+
+	if main.Branch != "main" || main.Runs != 10 {
+		t.Fatalf("unexpected main row %#v", main)
+	}
+	if main.Outages != 3 || main.Recovered != 2 || !main.Red {
+		t.Fatalf("expected 3 outages with 2 recoveries on a red branch, got %#v", main)
+	}
+	// Outages: runs 2-5 (1h → 4h30m = 3h30m) and runs 7-9 (6h → 8h30m = 2h30m).
+	if main.MeanTimeToRecover != 3*time.Hour {
+		t.Fatalf("expected mean time to recover 3h, got %s", main.MeanTimeToRecover)
+	}
+	if main.MTTRPercentiles.P50 != 150*time.Minute || main.MTTRPercentiles.P90 != 210*time.Minute {
+		t.Fatalf("unexpected MTTR percentiles %#v", main.MTTRPercentiles)
+	}
+	if main.LongestStreak != 2 {
+		t.Fatalf("expected longest streak 2, got %d", main.LongestStreak)
+	}
+
+	if len(outages) != 4 {
+		t.Fatalf("expected 4 outages, got %d", len(outages))
+	}
+	longest := outages[0]
+	if longest.Branch != "feature" || longest.Recovered || longest.Duration != 12*time.Hour {
+		t.Fatalf("expected ongoing feature outage first, got %#v", longest)
+	}
+	if second := outages[1]; second.FirstRunID != 2 || second.RecoveryRunID != 5 || second.FailedRuns != 2 {
+		t.Fatalf("unexpected second outage %#v", second)
+	}
+
+	// Without timed_out in the policy the timed out run is neutral.
+	_, outages = Recovery(records, to, FailurePolicy{Conclusions: []string{"failure"}})
+	for _, outage := range outages {
+		if outage.FirstRunID == 7 && outage.FailedRuns != 1 {
+			t.Fatalf("expected the timed out run to be neutral, got %#v", outage)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+)
+
+// ReusableSource is the input of Reusable for one repository: its runs, the
+// window they were collected for, and its workflow files keyed by workflow ID.
+type ReusableSource struct {
+	Repo    string
+	Records []RunRecord
+	Files   map[int64]*workflowfile.Workflow
+	From    time.Time
+	To      time.Time
+}
+
+// ReusableWorkflowRow aggregates the calls of one reusable workflow across
+// its callers.
+type ReusableWorkflowRow struct {
+	// Workflow is the called workflow without its ref, such as
+	// org/shared/.github/workflows/build.yml.
+	Workflow string   `json:"workflow"`
+	Refs     []string `json:"refs,omitempty"`
+	// Callers lists the calling workflows as "name (owner/repo)".
+	Callers []string `json:"callers"`
+	Calls   int      `json:"calls"`
+	// FailedCalls counts calls in which any job failed.
+	FailedCalls int     `json:"failed_calls"`
+	FailureRate float64 `json:"failure_rate"`
+	Jobs        int     `json:"jobs"`
+	// CITime is the runner time of the called jobs; Share is its fraction of
+	// the runner time of all collected runs.
+	CITime time.Duration `json:"ci_time"`
+	Share  float64       `json:"share"`
+	// MedianCall and P90Call measure a call from its first job starting to
+	// its last job finishing.
+	MedianCall time.Duration `json:"median_call"`
+	P90Call    time.Duration `json:"p90_call"`
+}
+
+// ActionRow aggregates the steps that ran one action.
+type ActionRow struct {
+	// Action is the action without its ref, such as actions/checkout or
+	// owner/repo/.github/actions/setup for local actions.
+	Action      string        `json:"action"`
+	Refs        []string      `json:"refs,omitempty"`
+	Steps       int           `json:"steps"`
+	Failed      int           `json:"failed"`
+	FailureRate float64       `json:"failure_rate"`
+	TotalTime   time.Duration `json:"total_time"`
+	AvgDuration time.Duration `json:"avg_duration"`
+}
+
+// ReusableReport attributes CI usage to the reusable workflows and actions
+// that workflows call.
+type ReusableReport struct {
+	Workflows []ReusableWorkflowRow `json:"workflows"`
+	Actions   []ActionRow           `json:"actions"`
+}
+
+// actionStepPattern matches the default name GitHub gives steps that run an
+// action: "Run owner/repo@ref" or "Run ./.github/actions/name". Other local
+// paths are not matched, since "Run ./script.sh" is also the default name of
+// a run step.
+var actionStepPattern = regexp.MustCompile(`^Run ([\w.-]+/\S*@\S+|\./\.github/\S+)$`)
+
+// UsesTarget splits a uses: reference into the called workflow or action and
+// its ref. Local references starting with ./ are qualified with repo so they
+// aggregate across repositories.
+func UsesTarget(uses, repo string) (string, string) {
+	uses = strings.TrimSpace(uses)
+	if local, ok := strings.CutPrefix(uses, "./"); ok {
+		return repo + "/" + local, ""
+	}
+	target, ref, _ := strings.Cut(uses, "@")
+	return target, ref
+}
+
+// Reusable aggregates, across all sources, the jobs run by reusable workflows
+// and the steps that ran actions. Jobs of a reusable workflow are named
+// "caller / job"; the caller is looked up in the workflow files to find the
+// workflow it calls, so jobs of workflows without a file are not attributed.
+// Actions are recognized by the default step name, so steps given a custom
+// name are not attributed. Rows are ordered by runner time, largest first.
+func Reusable(sources []ReusableSource, policy FailurePolicy) ReusableReport {
+	type call struct {
+		started, finished time.Time
+		failed            bool
+	}
+	type workflowStat struct {
+		row     ReusableWorkflowRow
+		refs    map[string]struct{}
+		callers map[string]struct{}
+		calls   map[string]*call
+	}
+	type actionStat struct {
+		row  ActionRow
+		refs map[string]struct{}
+	}
+	workflows := make(map[string]*workflowStat)
+	actions := make(map[string]*actionStat)
+	var total time.Duration
+
+	for _, src := range sources {
+		for _, rec := range src.Records {
+			runTime := rec.Run.RunStartedAt
+			if runTime.IsZero() {
+				runTime = rec.Run.CreatedAt
+			}
+			if runTime.Before(src.From) || runTime.After(src.To) {
+				continue
+			}
+			total += runnerTime(rec)
+
+			file := src.Files[rec.Workflow.ID]
+			for _, job := range rec.Jobs {
+				for _, step := range job.Steps {
+					m := actionStepPattern.FindStringSubmatch(step.Name)
+					if m == nil {
+						continue
+					}
+					target, ref := UsesTarget(m[1], src.Repo)
+					st, ok := actions[target]
+					if !ok {
+						st = &actionStat{row: ActionRow{Action: target}, refs: make(map[string]struct{})}
+						actions[target] = st
+					}
+					st.row.Steps++
+					st.row.TotalTime += step.Duration()
+					if policy.Failed(rec, step.Conclusion, step.Status) {
+						st.row.Failed++
+					}
+					if ref != "" {
+						st.refs[ref] = struct{}{}
+					}
+				}
+
+				if file == nil {
+					continue
+				}
+				caller, _, ok := strings.Cut(job.Name, " / ")
+				if !ok {
+					continue
+				}
+				declared := file.MatchJob(caller)
+				if declared == nil || declared.Uses == "" {
+					continue
+				}
+
+				target, ref := UsesTarget(declared.Uses, src.Repo)
+				st, ok := workflows[target]
+				if !ok {
+					st = &workflowStat{
+						row:     ReusableWorkflowRow{Workflow: target},
+						refs:    make(map[string]struct{}),
+						callers: make(map[string]struct{}),
+						calls:   make(map[string]*call),
+					}
+					workflows[target] = st
+				}
+				if ref != "" {
+					st.refs[ref] = struct{}{}
+				}
+				st.callers[fmt.Sprintf("%s (%s)", rec.Workflow.Name, src.Repo)] = struct{}{}
+				st.row.Jobs++
+				st.row.CITime += job.Duration()
+
+				key := fmt.Sprintf("%s#%d/%s", src.Repo, rec.Run.ID, caller)
+				c, ok := st.calls[key]
+				if !ok {
+					c = &call{}
+					st.calls[key] = c
+				}
+				if !job.StartedAt.IsZero() && (c.started.IsZero() || job.StartedAt.Before(c.started)) {
+					c.started = job.StartedAt
+				}
+				if job.CompletedAt.After(c.finished) {
+					c.finished = job.CompletedAt
+				}
+				if policy.Failed(rec, job.Conclusion, job.Status) {
+					c.failed = true
+				}
+			}
+		}
+	}
+
+	var report ReusableReport
+	for _, st := range workflows {
+		row := st.row
+		row.Refs = sortedKeys(st.refs)
+		row.Callers = sortedKeys(st.callers)
+		row.Calls = len(st.calls)
+		var durations []time.Duration
+		for _, c := range st.calls {
+			if c.failed {
+				row.FailedCalls++
+			}
+			if !c.started.IsZero() && c.finished.After(c.started) {
+				durations = append(durations, c.finished.Sub(c.started))
+			}
+		}
+		if row.Calls > 0 {
+			row.FailureRate = float64(row.FailedCalls) / float64(row.Calls)
+		}
+		if total > 0 {
+			row.Share = float64(row.CITime) / float64(total)
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		row.MedianCall = Percentile(durations, 0.5)
+		row.P90Call = Percentile(durations, 0.9)
+		report.Workflows = append(report.Workflows, row)
+	}
+	for _, st := range actions {
+		row := st.row
+		row.Refs = sortedKeys(st.refs)
+		if row.Steps > 0 {
+			row.FailureRate = float64(row.Failed) / float64(row.Steps)
+			row.AvgDuration = row.TotalTime / time.Duration(row.Steps)
+		}
+		report.Actions = append(report.Actions, row)
+	}
+
+	sort.Slice(report.Workflows, func(i, j int) bool {
+		a, b := report.Workflows[i], report.Workflows[j]
+		if a.CITime != b.CITime {
+			return a.CITime > b.CITime
+		}
+		return a.Workflow < b.Workflow
+	})
+	sort.Slice(report.Actions, func(i, j int) bool {
+		a, b := report.Actions[i], report.Actions[j]
+		if a.TotalTime != b.TotalTime {
+			return a.TotalTime > b.TotalTime
+		}
+		return a.Action < b.Action
+	})
+	return report
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
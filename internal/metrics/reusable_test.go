@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/workflowfile"
+)
+
+func TestUsesTarget(t *testing.T) {
+	cases := []struct {
+		uses, target, ref string
+	}{
+		{"org/shared/.github/workflows/build.yml@v2", "org/shared/.github/workflows/build.yml", "v2"},
+		{"./.github/workflows/test.yml", "org/app/.github/workflows/test.yml", ""},
+		{"actions/checkout@v4", "actions/checkout", "v4"},
+	}
+	for _, tc := range cases {
+		target, ref := UsesTarget(tc.uses, "org/app")
+		if target != tc.target || ref != tc.ref {
+			t.Errorf("UsesTarget(%q) = %q, %q, want %q, %q", tc.uses, target, ref, tc.target, tc.ref)
+		}
+	}
+}
+
+func TestReusable(t *testing.T) {
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	job := func(name, conclusion string, start, length time.Duration, steps ...githubapi.WorkflowStep) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name: name, Status: "completed", Conclusion: conclusion,
+			StartedAt: base.Add(start), CompletedAt: base.Add(start + length), Steps: steps,
+		}
+	}
+	step := func(name, conclusion string, length time.Duration) githubapi.WorkflowStep {
+		return githubapi.WorkflowStep{Name: name, Status: "completed", Conclusion: conclusion, StartedAt: base, CompletedAt: base.Add(length)}
+	}
+	shared := &workflowfile.Workflow{Jobs: []workflowfile.Job{
+		{ID: "build", Uses: "org/shared/.github/workflows/build.yml@v2"},
+		{ID: "lint"},
+	}}
+
+	app := ReusableSource{
+		Repo:  "org/app",
+		Files: map[int64]*workflowfile.Workflow{1: shared},
+		From:  base.Add(-time.Hour),
+		To:    base.Add(time.Hour),
+		Records: []RunRecord{
+			{Workflow: githubapi.Workflow{ID: 1, Name: "CI"}, Run: githubapi.WorkflowRun{ID: 10, CreatedAt: base}, Jobs: []githubapi.WorkflowJob{
+				job("build / compile", "success", 0, 10*time.Minute, step("Run actions/checkout@v4", "success", time.Minute)),
+				job("build / test", "failure", 10*time.Minute, 20*time.Minute),
+				job("lint", "success", 0, 10*time.Minute, step("Run ./scripts/lint.sh", "success", time.Minute)),
+			}},
+		},
+	}
+	api := ReusableSource{
+		Repo:  "org/api",
+		Files: map[int64]*workflowfile.Workflow{2: shared},
+		From:  base.Add(-time.Hour),
+		To:    base.Add(time.Hour),
+		Records: []RunRecord{
+			{Workflow: githubapi.Workflow{ID: 2, Name: "Build"}, Run: githubapi.WorkflowRun{ID: 20, CreatedAt: base}, Jobs: []githubapi.WorkflowJob{
+				job("build / compile", "success", 0, 20*time.Minute, step("Run actions/checkout@v3", "failure", 3*time.Minute)),
+			}},
+			// Without a workflow file the caller cannot be resolved.
+			{Workflow: githubapi.Workflow{ID: 3, Name: "Nightly"}, Run: githubapi.WorkflowRun{ID: 21, CreatedAt: base}, Jobs: []githubapi.WorkflowJob{
+				job("build / compile", "success", 0, 20*time.Minute),
+			}},
+		},
+	}
+
+	report := Reusable([]ReusableSource{app, api}, FailurePolicy{})
+	if len(report.Workflows) != 1 {
+		t.Fatalf("unexpected reusable workflows %+v", report.Workflows)
+	}
+	row := report.Workflows[0]
+	if row.Workflow != "org/shared/.github/workflows/build.yml" || !reflect.DeepEqual(row.Refs, []string{"v2"}) {
+		t.Fatalf("unexpected reusable workflow %+v", row)
+	}
+	if !reflect.DeepEqual(row.Callers, []string{"Build (org/api)", "CI (org/app)"}) {
+		t.Fatalf("unexpected callers %v", row.Callers)
+	}
+	if row.Calls != 2 || row.FailedCalls != 1 || row.FailureRate != 0.5 || row.Jobs != 3 || row.CITime != 50*time.Minute {
+		t.Fatalf("unexpected call totals %+v", row)
+	}
+	if row.Share != 0.625 || row.MedianCall != 20*time.Minute || row.P90Call != 30*time.Minute {
+		t.Fatalf("unexpected share or call duration %+v", row)
+	}
+
+	if len(report.Actions) != 1 {
+		t.Fatalf("expected only action steps to be attributed, got %+v", report.Actions)
+	}
+	checkout := report.Actions[0]
+	if checkout.Action != "actions/checkout" || checkout.Steps != 2 || checkout.Failed != 1 || checkout.AvgDuration != 2*time.Minute || !reflect.DeepEqual(checkout.Refs, []string{"v3", "v4"}) {
+		t.Fatalf("unexpected action row %+v", checkout)
+	}
+}
@@ -0,0 +1,298 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// selfHostedLabel is the label GitHub attaches to every self-hosted runner.
+const selfHostedLabel = "self-hosted"
+
+// RunnerRow reports how busy one self-hosted runner was over the window.
+type RunnerRow struct {
+	Runner   string   `json:"runner"`
+	RunnerID int64    `json:"runner_id,omitempty"`
+	OS       string   `json:"os,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Labels   []string `json:"labels"`
+	// Registered is false for runners that picked up jobs but are no longer
+	// registered, such as ephemeral runners of an autoscaling group.
+	Registered      bool          `json:"registered"`
+	Jobs            int           `json:"jobs"`
+	Busy            time.Duration `json:"busy"`
+	Idle            time.Duration `json:"idle"`
+	Utilization     float64       `json:"utilization"`
+	PeakConcurrency int           `json:"peak_concurrency"`
+}
+
+// RunnerLabelRow reports how busy the self-hosted runners carrying a label
+// were over the window. Busy and Idle are summed over those runners.
+type RunnerLabelRow struct {
+	Label           string        `json:"label"`
+	Runners         int           `json:"runners"`
+	Jobs            int           `json:"jobs"`
+	Busy            time.Duration `json:"busy"`
+	Idle            time.Duration `json:"idle"`
+	Utilization     float64       `json:"utilization"`
+	PeakConcurrency int           `json:"peak_concurrency"`
+}
+
+// RunnerReport is the self-hosted runner utilization over a window.
+type RunnerReport struct {
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Runners []RunnerRow      `json:"runners"`
+	Labels  []RunnerLabelRow `json:"labels"`
+}
+
+// span is the time a job occupied a runner.
+type span struct {
+	start time.Time
+	end   time.Time
+}
+
+// busyTime returns the time covered by at least one span.
+func busyTime(spans []span) time.Duration {
+	sorted := append([]span(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	var total time.Duration
+	var current span
+	for i, s := range sorted {
+		if i == 0 || s.start.After(current.end) {
+			total += current.end.Sub(current.start)
+			current = s
+			continue
+		}
+		if s.end.After(current.end) {
+			current.end = s.end
+		}
+	}
+	return total + current.end.Sub(current.start)
+}
+
+// lifetime returns the time from the earliest span start to the latest span
+// end.
+func lifetime(spans []span) time.Duration {
+	if len(spans) == 0 {
+		return 0
+	}
+	first, last := spans[0].start, spans[0].end
+	for _, s := range spans[1:] {
+		if s.start.Before(first) {
+			first = s.start
+		}
+		if s.end.After(last) {
+			last = s.end
+		}
+	}
+	return last.Sub(first)
+}
+
+// peakConcurrency returns the largest number of overlapping spans.
+func peakConcurrency(spans []span) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(spans))
+	for _, s := range spans {
+		events = append(events, event{at: s.start, delta: 1}, event{at: s.end, delta: -1})
+	}
+	// Ends sort before starts at the same instant so back-to-back jobs do not
+	// overlap.
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	peak, current := 0, 0
+	for _, e := range events {
+		current += e.delta
+		if current > peak {
+			peak = current
+		}
+	}
+	return peak
+}
+
+// RunnerUtilization joins the jobs of records started within [from, to] to
+// the registered self-hosted runners by runner ID, falling back to the runner
+// name. Jobs on runners that are no longer registered are still attributed
+// when they requested the self-hosted label. The window is extended to the
+// end of the last job so that jobs running past to are not cut short.
+// Registered runners that ran no jobs are reported as fully idle. Runners that
+// are no longer registered only existed while they had work, so their
+// capacity is the span from their first job start to their last job end
+// rather than the whole window.
+func RunnerUtilization(records []RunRecord, runners []githubapi.Runner, from, to time.Time) RunnerReport {
+	type runnerStat struct {
+		row    RunnerRow
+		labels map[string]bool
+		spans  []span
+	}
+	type labelStat struct {
+		runners map[string]bool
+		spans   []span
+	}
+
+	stats := make(map[string]*runnerStat)
+	byID := make(map[int64]string)
+	byName := make(map[string]string)
+	for _, r := range runners {
+		key := fmt.Sprintf("id:%d", r.ID)
+		stats[key] = &runnerStat{
+			row: RunnerRow{
+				Runner:     r.Name,
+				RunnerID:   r.ID,
+				OS:         r.OS,
+				Status:     r.Status,
+				Registered: true,
+			},
+			labels: labelSet(r.Labels),
+		}
+		byID[r.ID] = key
+		byName[r.Name] = key
+	}
+
+	end := to
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, job := range rec.Jobs {
+			if job.StartedAt.IsZero() || job.CompletedAt.IsZero() {
+				continue
+			}
+			key, ok := byID[job.RunnerID]
+			if !ok || job.RunnerID == 0 {
+				key, ok = byName[job.RunnerName]
+			}
+			if !ok {
+				if job.RunnerName == "" || !labelSet(job.Labels)[selfHostedLabel] {
+					continue
+				}
+				key = "name:" + job.RunnerName
+				if _, seen := stats[key]; !seen {
+					stats[key] = &runnerStat{
+						row:    RunnerRow{Runner: job.RunnerName, RunnerID: job.RunnerID},
+						labels: make(map[string]bool),
+					}
+				}
+				for label := range labelSet(job.Labels) {
+					stats[key].labels[label] = true
+				}
+			}
+
+			st := stats[key]
+			st.row.Jobs++
+			st.spans = append(st.spans, span{start: job.StartedAt, end: job.CompletedAt})
+			if job.CompletedAt.After(end) {
+				end = job.CompletedAt
+			}
+		}
+	}
+
+	window := end.Sub(from)
+	if window < 0 {
+		window = 0
+	}
+
+	report := RunnerReport{From: from, To: end}
+	labels := make(map[string]*labelStat)
+	capacity := make(map[string]time.Duration, len(stats))
+	for key, st := range stats {
+		row := st.row
+		row.Labels = sortedLabels(st.labels)
+		row.Busy = busyTime(st.spans)
+		capacity[key] = window
+		if !row.Registered {
+			capacity[key] = lifetime(st.spans)
+		}
+		row.Idle = capacity[key] - row.Busy
+		if row.Idle < 0 {
+			row.Idle = 0
+		}
+		if capacity[key] > 0 {
+			row.Utilization = float64(row.Busy) / float64(capacity[key])
+		}
+		row.PeakConcurrency = peakConcurrency(st.spans)
+		report.Runners = append(report.Runners, row)
+
+		for _, label := range row.Labels {
+			ls, ok := labels[label]
+			if !ok {
+				ls = &labelStat{runners: make(map[string]bool)}
+				labels[label] = ls
+			}
+			ls.runners[key] = true
+			ls.spans = append(ls.spans, st.spans...)
+		}
+	}
+
+	for label, ls := range labels {
+		row := RunnerLabelRow{
+			Label:           label,
+			Runners:         len(ls.runners),
+			Jobs:            len(ls.spans),
+			PeakConcurrency: peakConcurrency(ls.spans),
+		}
+		var labelCapacity time.Duration
+		for key := range ls.runners {
+			row.Busy += busyTime(stats[key].spans)
+			labelCapacity += capacity[key]
+		}
+		row.Idle = labelCapacity - row.Busy
+		if row.Idle < 0 {
+			row.Idle = 0
+		}
+		if labelCapacity > 0 {
+			row.Utilization = float64(row.Busy) / float64(labelCapacity)
+		}
+		report.Labels = append(report.Labels, row)
+	}
+
+	sort.Slice(report.Runners, func(i, j int) bool {
+		if report.Runners[i].Busy != report.Runners[j].Busy {
+			return report.Runners[i].Busy > report.Runners[j].Busy
+		}
+		return report.Runners[i].Runner < report.Runners[j].Runner
+	})
+	sort.Slice(report.Labels, func(i, j int) bool {
+		if report.Labels[i].Busy != report.Labels[j].Busy {
+			return report.Labels[i].Busy > report.Labels[j].Busy
+		}
+		return report.Labels[i].Label < report.Labels[j].Label
+	})
+	return report
+}
+
+func labelSet(labels []string) map[string]bool {
+	set := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if label = strings.ToLower(strings.TrimSpace(label)); label != "" {
+			set[label] = true
+		}
+	}
+	return set
+}
+
+func sortedLabels(set map[string]bool) []string {
+	labels := make([]string, 0, len(set))
+	for label := range set {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
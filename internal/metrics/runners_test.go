@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestRunnerUtilization(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	job := func(runnerID int64, runner string, start, end int, labels ...string) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        "build",
+			Status:      "completed",
+			Conclusion:  "success",
+			StartedAt:   at(start),
+			CompletedAt: at(end),
+			RunnerID:    runnerID,
+			RunnerName:  runner,
+			Labels:      labels,
+		}
+	}
+
+	runners := []githubapi.Runner{
+		{ID: 1, Name: "linux-1", OS: "Linux", Status: "online", Labels: []string{"self-hosted", "linux"}},
+		{ID: 2, Name: "linux-2", OS: "Linux", Status: "online", Labels: []string{"self-hosted", "linux"}},
+		{ID: 3, Name: "mac-1", OS: "macOS", Status: "offline", Labels: []string{"self-hosted", "macos"}},
+	}
+	records := []RunRecord{{
+		Workflow: githubapi.Workflow{ID: 1, Name: "ci"},
+		Run:      githubapi.WorkflowRun{ID: 1, CreatedAt: base},
+		Jobs: []githubapi.WorkflowJob{
+			job(1, "linux-1", 0, 30),
+			job(1, "linux-1", 30, 60),
+			job(0, "linux-2", 10, 40),
+			// Ephemeral runner that has since been removed.
+			job(99, "ephemeral-abc", 0, 20, "self-hosted", "linux"),
+			// GitHub-hosted jobs are ignored.
+			job(7, "GitHub Actions 7", 0, 100, "ubuntu-latest"),
+		},
+	}}
+
+	report := RunnerUtilization(records, runners, base, at(100))
+
+	if len(report.Runners) != 4 {
+		t.Fatalf("expected 4 runners, got %+v", report.Runners)
+	}
+	byName := make(map[string]RunnerRow)
+	for _, row := range report.Runners {
+		byName[row.Runner] = row
+	}
+
+	linux1 := byName["linux-1"]
+	if linux1.Jobs != 2 || linux1.Busy != time.Hour || linux1.Idle != 40*time.Minute || linux1.PeakConcurrency != 1 {
+		t.Fatalf("unexpected linux-1 row %+v", linux1)
+	}
+	if linux1.Utilization != 0.6 {
+		t.Fatalf("expected 60%% utilization, got %v", linux1.Utilization)
+	}
+	if byName["linux-2"].Jobs != 1 {
+		t.Fatalf("expected linux-2 to be joined by name, got %+v", byName["linux-2"])
+	}
+	if mac := byName["mac-1"]; mac.Jobs != 0 || mac.Idle != 100*time.Minute {
+		t.Fatalf("expected idle mac-1, got %+v", mac)
+	}
+	if eph := byName["ephemeral-abc"]; eph.Registered || eph.Busy != 20*time.Minute {
+		t.Fatalf("unexpected ephemeral row %+v", eph)
+	}
+
+	var linux RunnerLabelRow
+	for _, row := range report.Labels {
+		if row.Label == "linux" {
+			linux = row
+		}
+	}
+	if linux.Runners != 3 || linux.Jobs != 4 || linux.Busy != 110*time.Minute || linux.PeakConcurrency != 3 {
+		t.Fatalf("unexpected linux label row %+v", linux)
+	}
+	// The ephemeral runner only adds the 20 minutes it existed.
+	if linux.Idle != 110*time.Minute {
+		t.Fatalf("expected 110m idle capacity, got %v", linux.Idle)
+	}
+}
+
+func TestRunnerUtilizationEphemeralFleet(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	var jobs []githubapi.WorkflowJob
+	for i := 0; i < 50; i++ {
+		start := base.Add(time.Duration(i) * time.Hour)
+		jobs = append(jobs, githubapi.WorkflowJob{
+			Name:        "build",
+			StartedAt:   start,
+			CompletedAt: start.Add(10 * time.Minute),
+			RunnerID:    int64(1000 + i),
+			RunnerName:  fmt.Sprintf("ephemeral-%d", i),
+			Labels:      []string{"self-hosted", "linux"},
+		})
+	}
+	records := []RunRecord{{
+		Workflow: githubapi.Workflow{ID: 1, Name: "ci"},
+		Run:      githubapi.WorkflowRun{ID: 1, CreatedAt: base},
+		Jobs:     jobs,
+	}}
+
+	report := RunnerUtilization(records, nil, base, base.Add(50*time.Hour))
+	if len(report.Runners) != 50 {
+		t.Fatalf("expected 50 ephemeral runners, got %d", len(report.Runners))
+	}
+	for _, row := range report.Runners {
+		if row.Utilization != 1 || row.Idle != 0 {
+			t.Fatalf("expected a one-job runner to be fully utilized, got %+v", row)
+		}
+	}
+	for _, row := range report.Labels {
+		if row.Utilization != 1 || row.Idle != 0 || row.Busy != 500*time.Minute {
+			t.Fatalf("expected the fleet to be fully utilized, got %+v", row)
+		}
+	}
+}
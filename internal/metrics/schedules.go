@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/cron"
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// ScheduledWorkflow is a workflow with the cron schedules parsed from its
+// workflow file.
+type ScheduledWorkflow struct {
+	Workflow  githubapi.Workflow
+	Schedules []*cron.Schedule
+}
+
+// ScheduleRow compares the expected fire times of a scheduled workflow with
+// the runs the schedule event actually created.
+type ScheduleRow struct {
+	Workflow   string   `json:"workflow"`
+	WorkflowID int64    `json:"workflow_id"`
+	State      string   `json:"state"`
+	Schedules  []string `json:"schedules"`
+	Expected   int      `json:"expected"`
+	Ran        int      `json:"ran"`
+	Missed     int      `json:"missed"`
+	// Pending counts fire times too recent to tell whether they were missed.
+	Pending int `json:"pending"`
+	// MissRate is the share of settled fire times without a run.
+	MissRate float64 `json:"miss_rate"`
+	// Delay is measured from the expected fire time to the run's creation.
+	DelayP50  time.Duration `json:"delay_p50"`
+	DelayP90  time.Duration `json:"delay_p90"`
+	MaxDelay  time.Duration `json:"max_delay"`
+	LastRunAt time.Time     `json:"last_run_at,omitempty"`
+}
+
+// MissedRun is a fire time for which no run was created.
+type MissedRun struct {
+	Workflow   string    `json:"workflow"`
+	Schedule   string    `json:"schedule"`
+	ExpectedAt time.Time `json:"expected_at"`
+}
+
+// ScheduleReport holds the drift of scheduled workflows and their missed
+// executions, newest first.
+type ScheduleReport struct {
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Workflows []ScheduleRow `json:"workflows"`
+	Missed    []MissedRun   `json:"missed"`
+}
+
+type scheduleFire struct {
+	at       time.Time
+	schedule string
+}
+
+// ScheduleDrift matches the fire times of each scheduled workflow within
+// [from, to] to its runs triggered by the schedule event. A fire time is
+// matched to the first unused run created at or after it and before both the
+// next fire time and maxDelay has passed; fire times without a run are missed,
+// or pending when maxDelay has not yet passed by to. Fire times shared by
+// several schedules of a workflow are counted once. Rows are ordered by missed
+// executions.
+func ScheduleDrift(records []RunRecord, workflows []ScheduledWorkflow, from, to time.Time, maxDelay time.Duration) ScheduleReport {
+	runs := make(map[int64][]time.Time)
+	for _, rec := range records {
+		if rec.Run.Event != "schedule" {
+			continue
+		}
+		runs[rec.Workflow.ID] = append(runs[rec.Workflow.ID], rec.Run.CreatedAt)
+	}
+
+	report := ScheduleReport{From: from, To: to}
+	for _, sw := range workflows {
+		if len(sw.Schedules) == 0 {
+			continue
+		}
+
+		row := ScheduleRow{
+			Workflow:   sw.Workflow.Name,
+			WorkflowID: sw.Workflow.ID,
+			State:      sw.Workflow.State,
+		}
+
+		seen := make(map[time.Time]struct{})
+		var fires []scheduleFire
+		for _, s := range sw.Schedules {
+			row.Schedules = append(row.Schedules, s.String())
+			for _, at := range s.Between(from, to) {
+				if _, ok := seen[at]; ok {
+					continue
+				}
+				seen[at] = struct{}{}
+				fires = append(fires, scheduleFire{at: at, schedule: s.String()})
+			}
+		}
+		sort.Slice(fires, func(i, j int) bool { return fires[i].at.Before(fires[j].at) })
+
+		created := runs[sw.Workflow.ID]
+		sort.Slice(created, func(i, j int) bool { return created[i].Before(created[j]) })
+		for _, at := range created {
+			if at.After(row.LastRunAt) {
+				row.LastRunAt = at
+			}
+		}
+
+		var delays []time.Duration
+		next := 0
+		for i, fire := range fires {
+			deadline := fire.at.Add(maxDelay)
+			if i+1 < len(fires) && fires[i+1].at.Before(deadline) {
+				deadline = fires[i+1].at
+			}
+
+			for next < len(created) && created[next].Before(fire.at) {
+				next++
+			}
+			row.Expected++
+			if next < len(created) && created[next].Before(deadline) {
+				delays = append(delays, created[next].Sub(fire.at))
+				row.Ran++
+				next++
+				continue
+			}
+			if fire.at.Add(maxDelay).After(to) {
+				row.Pending++
+				continue
+			}
+			row.Missed++
+			report.Missed = append(report.Missed, MissedRun{Workflow: row.Workflow, Schedule: fire.schedule, ExpectedAt: fire.at})
+		}
+
+		if settled := row.Ran + row.Missed; settled > 0 {
+			row.MissRate = float64(row.Missed) / float64(settled)
+		}
+		sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+		row.DelayP50 = Percentile(delays, 0.5)
+		row.DelayP90 = Percentile(delays, 0.9)
+		if len(delays) > 0 {
+			row.MaxDelay = delays[len(delays)-1]
+		}
+		report.Workflows = append(report.Workflows, row)
+	}
+
+	sort.SliceStable(report.Workflows, func(i, j int) bool {
+		a, b := report.Workflows[i], report.Workflows[j]
+		if a.Missed != b.Missed {
+			return a.Missed > b.Missed
+		}
+		return a.Workflow < b.Workflow
+	})
+	sort.SliceStable(report.Missed, func(i, j int) bool {
+		return report.Missed[i].ExpectedAt.After(report.Missed[j].ExpectedAt)
+	})
+	return report
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/cron"
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestScheduleDrift(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	nightly := githubapi.Workflow{ID: 1, Name: "nightly", State: "active"}
+	record := func(event string, created time.Duration) RunRecord {
+		return RunRecord{Workflow: nightly, Run: githubapi.WorkflowRun{Event: event, CreatedAt: base.Add(created)}}
+	}
+	mustParse := func(expr string) *cron.Schedule {
+		s, err := cron.Parse(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	records := []RunRecord{
+		record("schedule", 3*time.Hour+5*time.Minute),
+		// Day 2 is skipped; a manual run does not count.
+		record("workflow_dispatch", 24*time.Hour+3*time.Hour+time.Minute),
+		record("schedule", 48*time.Hour+3*time.Hour+25*time.Minute),
+		// Too late to belong to the day 4 fire time.
+		record("schedule", 72*time.Hour+5*time.Hour),
+	}
+	workflows := []ScheduledWorkflow{
+		{Workflow: nightly, Schedules: []*cron.Schedule{mustParse("0 3 * * *"), mustParse("0 3 * * 0-6")}},
+		{Workflow: githubapi.Workflow{ID: 2, Name: "ci"}},
+	}
+
+	// The window ends 30 minutes after the day 5 fire time.
+	to := base.Add(4*24*time.Hour + 3*time.Hour + 30*time.Minute)
+	report := ScheduleDrift(records, workflows, base, to, time.Hour)
+	if len(report.Workflows) != 1 {
+		t.Fatalf("expected only scheduled workflows, got %+v", report.Workflows)
+	}
+
+	row := report.Workflows[0]
+	if row.Expected != 5 || row.Ran != 2 || row.Missed != 2 || row.Pending != 1 || row.MissRate != 0.5 {
+		t.Fatalf("unexpected counts %+v", row)
+	}
+	if row.DelayP50 != 5*time.Minute || row.MaxDelay != 25*time.Minute {
+		t.Fatalf("unexpected delays %+v", row)
+	}
+	if !row.LastRunAt.Equal(base.Add(72*time.Hour + 5*time.Hour)) {
+		t.Fatalf("unexpected last run %s", row.LastRunAt)
+	}
+	if len(report.Missed) != 2 || !report.Missed[0].ExpectedAt.Equal(base.Add(72*time.Hour+3*time.Hour)) || report.Missed[0].Schedule != "0 3 * * *" {
+		t.Fatalf("unexpected missed runs %+v", report.Missed)
+	}
+}
@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+// CacheEvictionAge is how long GitHub keeps an Actions cache entry that has
+// not been accessed.
+const CacheEvictionAge = 7 * 24 * time.Hour
+
+// UnknownWorkflow labels artifacts whose producing run could not be resolved,
+// for example because the run was deleted.
+const UnknownWorkflow = "(unknown)"
+
+// StorageGroup aggregates artifacts or cache entries sharing a workflow or
+// key prefix.
+type StorageGroup struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Bytes int64  `json:"bytes"`
+	// Share is the fraction of the artifact or cache total in this group.
+	Share      float64   `json:"share"`
+	OldestAt   time.Time `json:"oldest_at"`
+	NextExpiry time.Time `json:"next_expiry"`
+}
+
+// ArtifactRow describes one unexpired artifact.
+type ArtifactRow struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Workflow  string        `json:"workflow"`
+	Branch    string        `json:"branch"`
+	RunID     int64         `json:"run_id"`
+	Bytes     int64         `json:"bytes"`
+	CreatedAt time.Time     `json:"created_at"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Age       time.Duration `json:"age"`
+}
+
+// CacheRow describes one Actions cache entry.
+type CacheRow struct {
+	ID             int64     `json:"id"`
+	Key            string    `json:"key"`
+	Prefix         string    `json:"prefix"`
+	Ref            string    `json:"ref"`
+	Bytes          int64     `json:"bytes"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastAccessedAt time.Time `json:"last_accessed_at"`
+	// ExpiresAt is when the entry is evicted unless it is used again.
+	ExpiresAt time.Time     `json:"expires_at"`
+	Age       time.Duration `json:"age"`
+}
+
+// StorageReport summarizes the artifact and Actions cache storage of a
+// repository. Rows are ordered largest first.
+type StorageReport struct {
+	Artifacts        int            `json:"artifacts"`
+	ArtifactBytes    int64          `json:"artifact_bytes"`
+	ExpiredArtifacts int            `json:"expired_artifacts"`
+	Caches           int            `json:"caches"`
+	CacheBytes       int64          `json:"cache_bytes"`
+	ByWorkflow       []StorageGroup `json:"by_workflow"`
+	ByKeyPrefix      []StorageGroup `json:"by_key_prefix"`
+	ArtifactRows     []ArtifactRow  `json:"artifact_rows"`
+	CacheRows        []CacheRow     `json:"cache_rows"`
+}
+
+// Storage builds a storage report as of now. runWorkflows maps the run IDs of
+// artifacts to the names of the workflows that produced them. Expired
+// artifacts no longer count towards storage and are only counted.
+func Storage(artifacts []githubapi.Artifact, runWorkflows map[int64]string, caches []githubapi.ActionsCache, now time.Time) StorageReport {
+	var report StorageReport
+	byWorkflow := make(map[string]*StorageGroup)
+	byPrefix := make(map[string]*StorageGroup)
+
+	for _, artifact := range artifacts {
+		if artifact.Expired {
+			report.ExpiredArtifacts++
+			continue
+		}
+		workflow, ok := runWorkflows[artifact.RunID]
+		if !ok || workflow == "" {
+			workflow = UnknownWorkflow
+		}
+		row := ArtifactRow{
+			ID:        artifact.ID,
+			Name:      artifact.Name,
+			Workflow:  workflow,
+			Branch:    artifact.Branch,
+			RunID:     artifact.RunID,
+			Bytes:     artifact.Size,
+			CreatedAt: artifact.CreatedAt,
+			ExpiresAt: artifact.ExpiresAt,
+			Age:       storageAge(artifact.CreatedAt, now),
+		}
+		report.Artifacts++
+		report.ArtifactBytes += row.Bytes
+		report.ArtifactRows = append(report.ArtifactRows, row)
+		addToStorageGroup(byWorkflow, workflow, row.Bytes, row.CreatedAt, row.ExpiresAt)
+	}
+
+	for _, entry := range caches {
+		lastAccessed := entry.LastAccessedAt
+		if lastAccessed.IsZero() {
+			lastAccessed = entry.CreatedAt
+		}
+		row := CacheRow{
+			ID:             entry.ID,
+			Key:            entry.Key,
+			Prefix:         CacheKeyPrefix(entry.Key),
+			Ref:            entry.Ref,
+			Bytes:          entry.Size,
+			CreatedAt:      entry.CreatedAt,
+			LastAccessedAt: entry.LastAccessedAt,
+			Age:            storageAge(entry.CreatedAt, now),
+		}
+		if !lastAccessed.IsZero() {
+			row.ExpiresAt = lastAccessed.Add(CacheEvictionAge)
+		}
+		report.Caches++
+		report.CacheBytes += row.Bytes
+		report.CacheRows = append(report.CacheRows, row)
+		addToStorageGroup(byPrefix, row.Prefix, row.Bytes, row.CreatedAt, row.ExpiresAt)
+	}
+
+	report.ByWorkflow = sortedStorageGroups(byWorkflow, report.ArtifactBytes)
+	report.ByKeyPrefix = sortedStorageGroups(byPrefix, report.CacheBytes)
+
+	sort.SliceStable(report.ArtifactRows, func(i, j int) bool {
+		if report.ArtifactRows[i].Bytes != report.ArtifactRows[j].Bytes {
+			return report.ArtifactRows[i].Bytes > report.ArtifactRows[j].Bytes
+		}
+		return report.ArtifactRows[i].ID < report.ArtifactRows[j].ID
+	})
+	sort.SliceStable(report.CacheRows, func(i, j int) bool {
+		if report.CacheRows[i].Bytes != report.CacheRows[j].Bytes {
+			return report.CacheRows[i].Bytes > report.CacheRows[j].Bytes
+		}
+		return report.CacheRows[i].ID < report.CacheRows[j].ID
+	})
+	return report
+}
+
+// CacheKeyPrefix strips the volatile trailing parts of a cache key, such as
+// lockfile hashes and run numbers, so entries written by the same cache step
+// group together. "Linux-go-build-3f2a…" becomes "Linux-go-build".
+func CacheKeyPrefix(key string) string {
+	segments := strings.Split(key, "-")
+	end := len(segments)
+	for end > 1 && volatileKeySegment(segments[end-1]) {
+		end--
+	}
+	return strings.Join(segments[:end], "-")
+}
+
+// volatileKeySegment reports whether a key segment looks like a hash or a
+// number rather than a name.
+func volatileKeySegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	digits, hex := 0, true
+	for _, r := range segment {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			hex = false
+		}
+	}
+	if digits == len(segment) {
+		return true
+	}
+	return hex && digits > 0 && len(segment) >= 16
+}
+
+func storageAge(created, now time.Time) time.Duration {
+	if created.IsZero() || now.Before(created) {
+		return 0
+	}
+	return now.Sub(created)
+}
+
+func addToStorageGroup(groups map[string]*StorageGroup, name string, bytes int64, created, expires time.Time) {
+	group, ok := groups[name]
+	if !ok {
+		group = &StorageGroup{Name: name}
+		groups[name] = group
+	}
+	group.Count++
+	group.Bytes += bytes
+	if !created.IsZero() && (group.OldestAt.IsZero() || created.Before(group.OldestAt)) {
+		group.OldestAt = created
+	}
+	if !expires.IsZero() && (group.NextExpiry.IsZero() || expires.Before(group.NextExpiry)) {
+		group.NextExpiry = expires
+	}
+}
+
+func sortedStorageGroups(groups map[string]*StorageGroup, total int64) []StorageGroup {
+	out := make([]StorageGroup, 0, len(groups))
+	for _, group := range groups {
+		if total > 0 {
+			group.Share = float64(group.Bytes) / float64(total)
+		}
+		out = append(out, *group)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestCacheKeyPrefix(t *testing.T) {
+	cases := map[string]string{
+		"Linux-go-build-3f2a9c1d8e7b6a5f4e3d2c1b":             "Linux-go-build",
+		"node-cache-Linux-npm-1234":                           "node-cache-Linux-npm",
+		"setup-go-Linux-x64-go-1.22.1-abcdef0123456789abcdef": "setup-go-Linux-x64-go-1.22.1",
+		"cargo-registry":                                      "cargo-registry",
+		"12345":                                               "12345",
+	}
+	for key, want := range cases {
+		if got := CacheKeyPrefix(key); got != want {
+			t.Errorf("CacheKeyPrefix(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestStorage(t *testing.T) {
+	now := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	artifacts := []githubapi.Artifact{
+		{ID: 1, Name: "coverage", Size: 300, CreatedAt: now.Add(-2 * day), ExpiresAt: now.Add(88 * day), RunID: 10},
+		{ID: 2, Name: "binaries", Size: 700, CreatedAt: now.Add(-day), ExpiresAt: now.Add(5 * day), RunID: 11},
+		{ID: 3, Name: "old", Size: 5000, Expired: true, RunID: 12},
+		{ID: 4, Name: "orphan", Size: 100, CreatedAt: now.Add(-3 * day), RunID: 13},
+	}
+	workflows := map[int64]string{10: "ci", 11: "ci", 12: "release"}
+	caches := []githubapi.ActionsCache{
+		{ID: 5, Key: "Linux-go-aaaaaaaaaaaaaaaa1", Size: 50, CreatedAt: now.Add(-4 * day), LastAccessedAt: now.Add(-day)},
+		{ID: 6, Key: "Linux-go-bbbbbbbbbbbbbbbb2", Size: 150, CreatedAt: now.Add(-2 * day)},
+	}
+
+	report := Storage(artifacts, workflows, caches, now)
+	if report.Artifacts != 3 || report.ArtifactBytes != 1100 || report.ExpiredArtifacts != 1 {
+		t.Fatalf("unexpected artifact totals %+v", report)
+	}
+	if len(report.ByWorkflow) != 2 || report.ByWorkflow[0].Name != "ci" || report.ByWorkflow[0].Bytes != 1000 || report.ByWorkflow[0].Count != 2 {
+		t.Fatalf("unexpected workflow groups %+v", report.ByWorkflow)
+	}
+	if ci := report.ByWorkflow[0]; !ci.OldestAt.Equal(now.Add(-2*day)) || !ci.NextExpiry.Equal(now.Add(5*day)) {
+		t.Fatalf("unexpected ci group dates %+v", ci)
+	}
+	if report.ByWorkflow[1].Name != UnknownWorkflow {
+		t.Fatalf("expected unresolved run to be grouped as unknown, got %+v", report.ByWorkflow[1])
+	}
+	if report.ArtifactRows[0].Name != "binaries" || report.ArtifactRows[0].Age != day {
+		t.Fatalf("expected largest artifact first, got %+v", report.ArtifactRows[0])
+	}
+
+	if report.Caches != 2 || report.CacheBytes != 200 || len(report.ByKeyPrefix) != 1 {
+		t.Fatalf("unexpected cache totals %+v", report)
+	}
+	if group := report.ByKeyPrefix[0]; group.Name != "Linux-go" || group.Share != 1 || !group.NextExpiry.Equal(now.Add(5*day)) {
+		t.Fatalf("unexpected cache group %+v", group)
+	}
+	if row := report.CacheRows[0]; row.ID != 6 || !row.ExpiresAt.Equal(now.Add(5*day)) {
+		t.Fatalf("expected eviction to fall back to creation time, got %+v", row)
+	}
+}
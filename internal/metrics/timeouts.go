@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeoutOptions tunes timeout and hang detection.
+type TimeoutOptions struct {
+	// NearTimeout is the fraction of the timeout after which a cancelled job
+	// is considered hung rather than cancelled by a person or a newer run.
+	NearTimeout float64
+	// Headroom multiplies the p99 duration of successful runs to recommend a
+	// timeout.
+	Headroom float64
+	// MinSamples is the number of successful runs needed for a
+	// recommendation.
+	MinSamples int
+}
+
+// DefaultTimeoutOptions returns the defaults used by the timeouts report.
+func DefaultTimeoutOptions() TimeoutOptions {
+	return TimeoutOptions{NearTimeout: 0.9, Headroom: 1.5, MinSamples: 5}
+}
+
+// TimeoutLookup returns the configured timeout of a job and whether it was
+// declared explicitly.
+type TimeoutLookup func(workflowID int64, job string) (time.Duration, bool)
+
+// TimeoutRow reports timeouts and hung jobs for one job of a workflow.
+type TimeoutRow struct {
+	Workflow        string        `json:"workflow"`
+	WorkflowID      int64         `json:"workflow_id"`
+	Job             string        `json:"job"`
+	Runs            int           `json:"runs"`
+	TimedOut        int           `json:"timed_out"`
+	HungCancelled   int           `json:"hung_cancelled"`
+	Wasted          time.Duration `json:"wasted"`
+	Timeout         time.Duration `json:"timeout"`
+	TimeoutDeclared bool          `json:"timeout_declared"`
+	SuccessP99      time.Duration `json:"success_p99"`
+	// Recommended is a tighter timeout derived from successful runs, or zero
+	// when the configured timeout is already tight or there is too little
+	// data.
+	Recommended time.Duration `json:"recommended,omitempty"`
+}
+
+// Timeouts finds jobs that timed out or were cancelled after running close to
+// their timeout, totals the runner time they burned, and recommends tighter
+// timeouts. Matrix cells are reported under their base job. Only jobs with a
+// timeout, a hang or a recommendation are returned, most wasted time first.
+// Cancellations of superseded runs are never considered hung.
+func Timeouts(records []RunRecord, from, to time.Time, lookup TimeoutLookup, opts TimeoutOptions) []TimeoutRow {
+	type key struct {
+		workflowID int64
+		job        string
+	}
+	type timeoutStat struct {
+		row       TimeoutRow
+		successes []time.Duration
+	}
+	stats := make(map[key]*timeoutStat)
+
+	for _, rec := range records {
+		runTime := rec.Run.RunStartedAt
+		if runTime.IsZero() {
+			runTime = rec.Run.CreatedAt
+		}
+		if runTime.Before(from) || runTime.After(to) {
+			continue
+		}
+
+		for _, job := range rec.Jobs {
+			if job.StartedAt.IsZero() || job.CompletedAt.IsZero() {
+				continue
+			}
+			base, _ := SplitMatrixJobName(strings.TrimSpace(job.Name))
+			k := key{workflowID: rec.Workflow.ID, job: base}
+			st, ok := stats[k]
+			if !ok {
+				timeout, declared := lookup(rec.Workflow.ID, base)
+				st = &timeoutStat{row: TimeoutRow{
+					Workflow:        rec.Workflow.Name,
+					WorkflowID:      rec.Workflow.ID,
+					Job:             base,
+					Timeout:         timeout,
+					TimeoutDeclared: declared,
+				}}
+				stats[k] = st
+			}
+
+			st.row.Runs++
+			duration := job.Duration()
+			switch job.Conclusion {
+			case "success":
+				st.successes = append(st.successes, duration)
+			case "timed_out":
+				st.row.TimedOut++
+				st.row.Wasted += duration
+			case "cancelled":
+				if rec.Superseded {
+					continue
+				}
+				if st.row.Timeout > 0 && float64(duration) >= opts.NearTimeout*float64(st.row.Timeout) {
+					st.row.HungCancelled++
+					st.row.Wasted += duration
+				}
+			}
+		}
+	}
+
+	rows := make([]TimeoutRow, 0, len(stats))
+	for _, st := range stats {
+		row := st.row
+		if len(st.successes) > 0 {
+			sort.Slice(st.successes, func(i, j int) bool { return st.successes[i] < st.successes[j] })
+			row.SuccessP99 = Percentile(st.successes, 0.99)
+		}
+		if len(st.successes) >= opts.MinSamples && row.SuccessP99 > 0 {
+			minutes := math.Ceil((float64(row.SuccessP99) * opts.Headroom) / float64(time.Minute))
+			if recommended := time.Duration(minutes) * time.Minute; recommended < row.Timeout {
+				row.Recommended = recommended
+			}
+		}
+		if row.TimedOut == 0 && row.HungCancelled == 0 && row.Recommended == 0 {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Wasted != rows[j].Wasted {
+			return rows[i].Wasted > rows[j].Wasted
+		}
+		if rows[i].Workflow != rows[j].Workflow {
+			return rows[i].Workflow < rows[j].Workflow
+		}
+		return rows[i].Job < rows[j].Job
+	})
+	return rows
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+)
+
+func TestTimeouts(t *testing.T) {
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	workflow := githubapi.Workflow{ID: 1, Name: "ci"}
+	job := func(name, conclusion string, minutes int) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			Name:        name,
+			Status:      "completed",
+			Conclusion:  conclusion,
+			StartedAt:   base,
+			CompletedAt: base.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+
+	var records []RunRecord
+	for i := 0; i < 6; i++ {
+		records = append(records, RunRecord{
+			Workflow: workflow,
+			Run:      githubapi.WorkflowRun{ID: int64(i), CreatedAt: base},
+			Jobs: []githubapi.WorkflowJob{
+				job("test (ubuntu)", "success", 8+i),
+				job("lint", "success", 1),
+			},
+		})
+	}
+	records = append(records, RunRecord{
+		Workflow: workflow,
+		Run:      githubapi.WorkflowRun{ID: 10, CreatedAt: base},
+		Jobs: []githubapi.WorkflowJob{
+			job("test (windows)", "timed_out", 30),
+			job("lint", "cancelled", 58),
+		},
+	}, RunRecord{
+		Workflow: workflow,
+		Run:      githubapi.WorkflowRun{ID: 11, CreatedAt: base},
+		Jobs: []githubapi.WorkflowJob{
+			// Cancelled early, e.g. by a newer push: not a hang.
+			job("test (ubuntu)", "cancelled", 3),
+		},
+	})
+
+	lookup := func(_ int64, job string) (time.Duration, bool) {
+		if job == "test" {
+			return 30 * time.Minute, true
+		}
+		return 60 * time.Minute, false
+	}
+
+	rows := Timeouts(records, base.Add(-time.Hour), base.Add(time.Hour), lookup, DefaultTimeoutOptions())
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %+v", rows)
+	}
+
+	lint, test := rows[0], rows[1]
+	if lint.Job != "lint" || lint.HungCancelled != 1 || lint.Wasted != 58*time.Minute || lint.TimeoutDeclared {
+		t.Fatalf("unexpected lint row %+v", lint)
+	}
+	if lint.Recommended != 2*time.Minute {
+		t.Fatalf("expected 2m recommendation for lint, got %v", lint.Recommended)
+	}
+
+	if test.Job != "test" || test.Runs != 8 || test.TimedOut != 1 || test.HungCancelled != 0 || test.Wasted != 30*time.Minute {
+		t.Fatalf("unexpected test row %+v", test)
+	}
+	if test.SuccessP99 != 13*time.Minute || test.Recommended != 20*time.Minute {
+		t.Fatalf("unexpected test recommendation %+v", test)
+	}
+
+	// A cancellation caused by a newer run is not a hang, whatever its length.
+	records[6].Superseded = true
+	rows = Timeouts(records, base.Add(-time.Hour), base.Add(time.Hour), lookup, DefaultTimeoutOptions())
+	for _, row := range rows {
+		if row.HungCancelled != 0 {
+			t.Fatalf("expected no hung jobs, got %+v", row)
+		}
+	}
+}
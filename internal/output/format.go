@@ -32,6 +32,35 @@ func formatFailureRate(rate float64) string {
 	return FormatFailureRate(rate)
 }
 
+// FormatBytes formats a byte count with binary units
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+// FormatAge formats a long duration in its largest whole unit (days, hours or
+// minutes)
+func FormatAge(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+}
+
 // FormatRunnerSummary formats runner usage summary
 func FormatRunnerSummary(usages []metrics.RunnerUsage, limit int) string {
 	if len(usages) == 0 {
@@ -55,3 +84,23 @@ func FormatRunnerSummary(usages []metrics.RunnerUsage, limit int) string {
 func formatRunnerSummary(usages []metrics.RunnerUsage, limit int) string {
 	return FormatRunnerSummary(usages, limit)
 }
+
+// FormatAlertChange describes how an alerted metric moved from its baseline
+func FormatAlertChange(alert metrics.Alert) string {
+	switch alert.Metric {
+	case metrics.AlertDuration:
+		change := 0.0
+		if alert.Baseline > 0 {
+			change = (alert.Recent - alert.Baseline) / alert.Baseline
+		}
+		return fmt.Sprintf("median %s → %s (%+.0f%%)",
+			FormatDuration(time.Duration(alert.Baseline).Truncate(time.Second)),
+			FormatDuration(time.Duration(alert.Recent).Truncate(time.Second)),
+			change*100,
+		)
+	case metrics.AlertFailureRate:
+		return fmt.Sprintf("failure rate %s → %s", FormatFailureRate(alert.Baseline), FormatFailureRate(alert.Recent))
+	default:
+		return fmt.Sprintf("%s %.2f → %.2f", alert.Metric, alert.Baseline, alert.Recent)
+	}
+}
@@ -53,3 +53,30 @@ func TestWriteSummaryTable(t *testing.T) {
 		t.Fatalf("expected table output to contain workflow name, got %s", out)
 	}
 }
+
+func TestFormatBytesAndAge(t *testing.T) {
+	bytesCases := map[int64]string{
+		512:                    "512 B",
+		2048:                   "2.0 KiB",
+		5*1024*1024 + 512*1024: "5.5 MiB",
+		3 * 1024 * 1024 * 1024: "3.0 GiB",
+	}
+	for n, want := range bytesCases {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	ageCases := map[time.Duration]string{
+		0:                   "-",
+		30 * time.Minute:    "30m",
+		5 * time.Hour:       "5h",
+		50 * time.Hour:      "2d",
+		90 * 24 * time.Hour: "90d",
+	}
+	for d, want := range ageCases {
+		if got := FormatAge(d); got != want {
+			t.Errorf("FormatAge(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// GanttQueued fills the part of a bar spent waiting for a runner.
+	GanttQueued = '░'
+	// GanttRunning fills the part of a bar spent running.
+	GanttRunning = '█'
+)
+
+// GanttBar is one row of a Gantt chart. Queued is the time the bar started
+// waiting and may be zero when there is no waiting phase.
+type GanttBar struct {
+	Label  string
+	Queued time.Time
+	Start  time.Time
+	End    time.Time
+	Fill   rune
+}
+
+// RenderGantt draws bars on a shared timeline from origin to end, scaled to
+// width columns. Each line is the label padded to labelWidth followed by the
+// bar; a time axis is appended as the last line.
+func RenderGantt(bars []GanttBar, origin, end time.Time, labelWidth, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	span := end.Sub(origin)
+	if span <= 0 {
+		span = time.Second
+	}
+
+	column := func(t time.Time) int {
+		if t.IsZero() || t.Before(origin) {
+			return 0
+		}
+		c := int(float64(t.Sub(origin)) / float64(span) * float64(width))
+		if c > width {
+			c = width
+		}
+		return c
+	}
+
+	lines := make([]string, 0, len(bars)+1)
+	for _, bar := range bars {
+		cells := []rune(strings.Repeat(" ", width))
+		fill := bar.Fill
+		if fill == 0 {
+			fill = GanttRunning
+		}
+
+		start, stop := column(bar.Start), column(bar.End)
+		if !bar.Start.IsZero() && !bar.End.IsZero() && stop <= start && start < width {
+			// Always show at least one cell for very short bars.
+			stop = start + 1
+		}
+		if !bar.Queued.IsZero() && !bar.Start.IsZero() {
+			for i := column(bar.Queued); i < start; i++ {
+				cells[i] = GanttQueued
+			}
+		}
+		if !bar.Start.IsZero() && !bar.End.IsZero() {
+			for i := start; i < stop && i < width; i++ {
+				cells[i] = fill
+			}
+		}
+
+		lines = append(lines, strings.TrimRight(padLabel(bar.Label, labelWidth)+" "+string(cells), " "))
+	}
+
+	lines = append(lines, padLabel("", labelWidth)+" "+ganttAxis(span, width))
+	return lines
+}
+
+func ganttAxis(span time.Duration, width int) string {
+	left := "0s"
+	mid := FormatDuration((span / 2).Truncate(time.Second))
+	right := FormatDuration(span.Truncate(time.Second))
+
+	axis := []rune(strings.Repeat("─", width))
+	place := func(label string, at int) {
+		runes := []rune(label)
+		if at < 0 {
+			at = 0
+		}
+		if at+len(runes) > width {
+			at = width - len(runes)
+		}
+		copy(axis[at:], runes)
+	}
+	place(left, 0)
+	place(right, width-utf8.RuneCountInString(right))
+	// The midpoint label is dropped when it would collide with either end.
+	midAt := width/2 - utf8.RuneCountInString(mid)/2
+	if midAt > len(left) && midAt+utf8.RuneCountInString(mid) < width-utf8.RuneCountInString(right) {
+		place(mid, midAt)
+	}
+	return string(axis)
+}
+
+func padLabel(label string, width int) string {
+	n := utf8.RuneCountInString(label)
+	if n > width {
+		runes := []rune(label)
+		return string(runes[:width-1]) + "…"
+	}
+	return label + strings.Repeat(" ", width-n)
+}
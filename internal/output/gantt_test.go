@@ -0,0 +1,36 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderGantt(t *testing.T) {
+	origin := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := origin.Add(100 * time.Second)
+	bars := []GanttBar{
+		{Label: "build", Queued: origin, Start: origin.Add(20 * time.Second), End: origin.Add(50 * time.Second)},
+		{Label: "a-very-long-job-name", Start: origin.Add(50 * time.Second), End: origin.Add(100 * time.Second), Fill: '▒'},
+		{Label: "pending"},
+	}
+
+	lines := RenderGantt(bars, origin, end, 8, 10)
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 bars plus axis, got %d lines", len(lines))
+	}
+
+	want := []string{
+		"build    ░░███",
+		"a-very-… " + strings.Repeat(" ", 5) + "▒▒▒▒▒",
+		"pending",
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Fatalf("line %d mismatch:\nwant: %q\ngot:  %q", i, w, lines[i])
+		}
+	}
+	if !strings.HasPrefix(lines[3], "         0s") || !strings.HasSuffix(lines[3], "1m40s") {
+		t.Fatalf("unexpected axis line: %q", lines[3])
+	}
+}
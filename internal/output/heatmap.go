@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HeatmapShades are the block characters used for heatmap cells, from the
+// lowest non-zero quarter of the scale to the highest.
+var HeatmapShades = []rune{'░', '▒', '▓', '█'}
+
+// heatmapEmpty marks cells without a value.
+const heatmapEmpty = '·'
+
+// RenderHeatmap draws a row of 24 hourly cells for each label, shading each
+// cell by its value relative to the largest value. Cells are two columns
+// wide and an hour axis is prepended as the first line.
+func RenderHeatmap(labels []string, values [][]float64) []string {
+	labelWidth := 0
+	for _, label := range labels {
+		if len(label) > labelWidth {
+			labelWidth = len(label)
+		}
+	}
+
+	maxValue := 0.0
+	for _, row := range values {
+		for _, v := range row {
+			if v > maxValue {
+				maxValue = v
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(labels)+1)
+	var axis strings.Builder
+	axis.WriteString(strings.Repeat(" ", labelWidth+1))
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&axis, "%02d    ", hour)
+	}
+	lines = append(lines, strings.TrimRight(axis.String(), " "))
+
+	for i, label := range labels {
+		var line strings.Builder
+		line.WriteString(label)
+		line.WriteString(strings.Repeat(" ", labelWidth-len(label)+1))
+		for _, v := range values[i] {
+			shade := heatmapEmpty
+			if v > 0 && maxValue > 0 {
+				level := int(v / maxValue * float64(len(HeatmapShades)))
+				if level >= len(HeatmapShades) {
+					level = len(HeatmapShades) - 1
+				}
+				shade = HeatmapShades[level]
+			}
+			line.WriteRune(shade)
+			line.WriteRune(shade)
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHeatmap(t *testing.T) {
+	values := [][]float64{make([]float64, 24), make([]float64, 24)}
+	values[0][0] = 4
+	values[0][1] = 1
+	values[1][23] = 2.5
+
+	lines := RenderHeatmap([]string{"Mon", "Tuesday"}, values)
+	if len(lines) != 3 {
+		t.Fatalf("expected axis plus 2 rows, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "        00    03    06") || !strings.HasSuffix(lines[0], "21") {
+		t.Fatalf("unexpected axis line: %q", lines[0])
+	}
+	if want := "Mon     ██▒▒" + strings.Repeat("·", 44); lines[1] != want {
+		t.Fatalf("row mismatch:\nwant: %q\ngot:  %q", want, lines[1])
+	}
+	if want := "Tuesday " + strings.Repeat("·", 46) + "▓▓"; lines[2] != want {
+		t.Fatalf("row mismatch:\nwant: %q\ngot:  %q", want, lines[2])
+	}
+}
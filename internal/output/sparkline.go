@@ -0,0 +1,75 @@
+package output
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of bar heights scaled between the smallest
+// and largest value. NaN values, such as days without runs, are drawn as
+// blanks. When there are more values than width, adjacent values are
+// averaged so the line fits.
+func Sparkline(values []float64, width int) string {
+	values = downsample(values, width)
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[len(sparkBlocks)/2])
+		default:
+			level := int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[level])
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// downsample averages adjacent values, skipping NaN, to at most width values.
+func downsample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for i := range out {
+		start, end := i*len(values)/width, (i+1)*len(values)/width
+		sum, n := 0.0, 0
+		for _, v := range values[start:end] {
+			if !math.IsNaN(v) {
+				sum += v
+				n++
+			}
+		}
+		out[i] = math.NaN()
+		if n > 0 {
+			out[i] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// FailureBar draws a failure rate as a bar of width cells.
+func FailureBar(rate float64, width int) string {
+	filled := int(math.Round(rate * float64(width)))
+	if rate > 0 && filled == 0 {
+		filled = 1
+	}
+	if filled > width {
+		filled = width
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
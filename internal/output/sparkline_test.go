@@ -0,0 +1,33 @@
+package output
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+	if got := Sparkline([]float64{1, 8, nan, 4.5, nan}, 0); got != "▁█ ▄" {
+		t.Fatalf("unexpected sparkline %q", got)
+	}
+	if got := Sparkline([]float64{3, 3}, 0); got != "▅▅" {
+		t.Fatalf("expected flat sparkline, got %q", got)
+	}
+	if got := Sparkline([]float64{1, 3, nan, nan, 8, 8}, 3); got != "▁ █" {
+		t.Fatalf("unexpected downsampled sparkline %q", got)
+	}
+}
+
+func TestFailureBar(t *testing.T) {
+	cases := map[float64]string{
+		0:    "░░░░░",
+		0.01: "█░░░░",
+		0.5:  "███░░",
+		1:    "█████",
+	}
+	for rate, want := range cases {
+		if got := FailureBar(rate, 5); got != want {
+			t.Fatalf("FailureBar(%v) = %q, want %q", rate, got, want)
+		}
+	}
+}
@@ -0,0 +1,585 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	"github.com/JohnTitor/gh-actrics/internal/output"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Data is the dataset browsed by the TUI.
+type Data struct {
+	Records []metrics.RunRecord
+	From    time.Time
+	To      time.Time
+	Policy  metrics.FailurePolicy
+}
+
+// Loader fetches a fresh dataset. It is called again when the user refreshes.
+type Loader func(ctx context.Context) (Data, error)
+
+type level int
+
+const (
+	levelWorkflows level = iota
+	levelJobs
+	levelRuns
+	levelSteps
+)
+
+// column describes a table column. Numeric columns are right-aligned and
+// sorted by value rather than text.
+type column struct {
+	title   string
+	numeric bool
+}
+
+type tableRow struct {
+	cells  []string
+	values []float64
+	// key identifies the entity behind the row so the next level can be built.
+	key string
+	// failed highlights runs and steps that failed under the failure policy.
+	failed bool
+	// parent is the key of the row a matrix cell row belongs to. Cells are
+	// kept directly under their parent when sorting.
+	parent string
+}
+
+// screen is one entry of the navigation stack.
+type screen struct {
+	level    level
+	title    string
+	workflow int64
+	job      string
+	runID    int64
+	// jobID selects a single job, so the steps of matrix cells that share a
+	// base name are not merged.
+	jobID int64
+
+	columns  []column
+	rows     []tableRow
+	cursor   int
+	offset   int
+	sortCol  int
+	sortDesc bool
+	filter   string
+}
+
+type loadedMsg struct {
+	data Data
+	err  error
+}
+
+// Model is the bubbletea model of the metrics browser.
+type Model struct {
+	ctx    context.Context
+	repo   string
+	load   Loader
+	data   Data
+	stack  []*screen
+	width  int
+	height int
+
+	filtering  bool
+	refreshing bool
+	err        error
+}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	selectedStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+	failStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// New creates a model showing data for repo. load is used for refreshes.
+func New(ctx context.Context, repo string, data Data, load Loader) Model {
+	m := Model{
+		ctx:    ctx,
+		repo:   repo,
+		load:   load,
+		data:   data,
+		height: 24,
+		width:  120,
+	}
+	m.stack = []*screen{{level: levelWorkflows, title: "Workflows"}}
+	m.rebuild(m.stack[0])
+	return m
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.current().clamp(m.visibleRows())
+		return m, nil
+	case loadedMsg:
+		m.refreshing = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.data = msg.data
+			for _, s := range m.stack {
+				m.rebuild(s)
+			}
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.current()
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyEsc:
+		m.filtering = false
+		s.filter = ""
+	case tea.KeyBackspace:
+		if r := []rune(s.filter); len(r) > 0 {
+			s.filter = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		s.filter += string(msg.Runes)
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	}
+	s.cursor, s.offset = 0, 0
+	return m, nil
+}
+
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.current()
+	rows := s.visible()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(rows)-1 {
+			s.cursor++
+		}
+	case "pgup":
+		s.cursor -= m.visibleRows()
+	case "pgdown":
+		s.cursor += m.visibleRows()
+	case "home", "g":
+		s.cursor = 0
+	case "end", "G":
+		s.cursor = len(rows) - 1
+	case "enter", "right", "l":
+		if len(rows) > 0 {
+			if next := m.drillDown(s, rows[s.cursor]); next != nil {
+				m.stack = append(m.stack, next)
+			}
+		}
+	case "esc", "backspace", "left", "h":
+		if s.filter != "" {
+			s.filter = ""
+		} else if len(m.stack) > 1 {
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+	case "s":
+		s.sortCol = (s.sortCol + 1) % len(s.columns)
+		s.sortDesc = s.columns[s.sortCol].numeric
+		s.sort()
+	case "S":
+		s.sortDesc = !s.sortDesc
+		s.sort()
+	case "/":
+		m.filtering = true
+	case "r":
+		if m.load != nil && !m.refreshing {
+			m.refreshing = true
+			return m, m.refresh()
+		}
+	}
+	m.current().clamp(m.visibleRows())
+	return m, nil
+}
+
+func (m Model) refresh() tea.Cmd {
+	load, ctx := m.load, m.ctx
+	return func() tea.Msg {
+		data, err := load(ctx)
+		return loadedMsg{data: data, err: err}
+	}
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	s := m.current()
+	var b strings.Builder
+
+	crumbs := make([]string, 0, len(m.stack))
+	for _, entry := range m.stack {
+		crumbs = append(crumbs, entry.title)
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("📊 %s › %s", m.repo, strings.Join(crumbs, " › "))))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s → %s", m.data.From.Format(time.RFC3339), m.data.To.Format(time.RFC3339))))
+	b.WriteString("\n\n")
+
+	rows := s.visible()
+	widths := columnWidths(s.columns, rows)
+	header := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		title := col.title
+		if i == s.sortCol {
+			if s.sortDesc {
+				title += " ↓"
+			} else {
+				title += " ↑"
+			}
+		}
+		header[i] = pad(title, widths[i], col.numeric)
+	}
+	b.WriteString(headerStyle.Render(strings.Join(header, "  ")))
+	b.WriteString("\n")
+
+	if len(rows) == 0 {
+		b.WriteString(helpStyle.Render("(no data)"))
+		b.WriteString("\n")
+	}
+	end := s.offset + m.visibleRows()
+	if end > len(rows) {
+		end = len(rows)
+	}
+	for i := s.offset; i < end; i++ {
+		cells := make([]string, len(s.columns))
+		for j, col := range s.columns {
+			cells[j] = pad(rows[i].cells[j], widths[j], col.numeric)
+		}
+		line := strings.Join(cells, "  ")
+		switch {
+		case i == s.cursor:
+			line = selectedStyle.Render(line)
+		case rows[i].failed:
+			line = failStyle.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.filtering:
+		b.WriteString(fmt.Sprintf("/%s█", s.filter))
+	case m.refreshing:
+		b.WriteString(helpStyle.Render("Refreshing..."))
+	case m.err != nil:
+		b.WriteString(failStyle.Render(fmt.Sprintf("Refresh failed: %v", m.err)))
+	default:
+		status := fmt.Sprintf("%d/%d", min(s.cursor+1, len(rows)), len(rows))
+		if s.filter != "" {
+			status += fmt.Sprintf(" · filter %q", s.filter)
+		}
+		b.WriteString(helpStyle.Render(status + " · ↑/↓ move · enter open · esc back · s/S sort · / filter · r refresh · q quit"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m Model) current() *screen {
+	return m.stack[len(m.stack)-1]
+}
+
+// visibleRows is the number of table rows that fit between the header and
+// the status line.
+func (m Model) visibleRows() int {
+	if n := m.height - 7; n > 1 {
+		return n
+	}
+	return 1
+}
+
+func (m Model) drillDown(s *screen, row tableRow) *screen {
+	var next *screen
+	switch s.level {
+	case levelWorkflows:
+		id, err := strconv.ParseInt(row.key, 10, 64)
+		if err != nil {
+			return nil
+		}
+		next = &screen{level: levelJobs, title: row.cells[0], workflow: id}
+	case levelJobs:
+		// Most recent runs first.
+		next = &screen{level: levelRuns, title: row.key, workflow: s.workflow, job: row.key, sortCol: 5, sortDesc: true}
+	case levelRuns:
+		runID, err := strconv.ParseInt(row.cells[0], 10, 64)
+		if err != nil {
+			return nil
+		}
+		jobID, err := strconv.ParseInt(row.key, 10, 64)
+		if err != nil {
+			return nil
+		}
+		title := "Run " + row.cells[0]
+		if name := row.cells[2]; name != s.job {
+			title += " · " + name
+		}
+		next = &screen{level: levelSteps, title: title, workflow: s.workflow, job: s.job, runID: runID, jobID: jobID}
+	default:
+		return nil
+	}
+	m.rebuild(next)
+	return next
+}
+
+// rebuild recomputes the rows of a screen from the current dataset while
+// keeping its sort order and filter.
+func (m Model) rebuild(s *screen) {
+	switch s.level {
+	case levelWorkflows:
+		s.columns = summaryColumns("Workflow")
+		s.rows = nil
+		for _, row := range metrics.AggregateWithPolicy(m.data.Records, m.data.From, m.data.To, m.data.Policy) {
+			s.rows = append(s.rows, summaryRow(row.Workflow, strconv.FormatInt(row.WorkflowID, 10), row.Runs, row.Failed, row.FailureRate, row.AvgDuration, row.DurationPercentiles.P95, row.TotalDuration))
+		}
+	case levelJobs:
+		s.columns = summaryColumns("Job")
+		s.rows = nil
+		for _, row := range metrics.AggregateWithPolicy(m.workflowRecords(s.workflow), m.data.From, m.data.To, m.data.Policy) {
+			for _, job := range row.Jobs {
+				s.rows = append(s.rows, summaryRow(job.Job, job.Job, job.Runs, job.Failed, job.FailureRate, job.AvgDuration, job.DurationPercentiles.P95, job.TotalDuration))
+				for _, cell := range job.Matrix {
+					label := "  ↳ " + strings.Join(cell.MatrixValues, ", ")
+					row := summaryRow(label, cell.Job, cell.Runs, cell.Failed, cell.FailureRate, cell.AvgDuration, cell.DurationPercentiles.P95, cell.TotalDuration)
+					row.parent = job.Job
+					s.rows = append(s.rows, row)
+				}
+			}
+		}
+	case levelRuns:
+		s.columns = []column{{title: "Run"}, {title: "Run#", numeric: true}, {title: "Job"}, {title: "Branch"}, {title: "Conclusion"}, {title: "Started"}, {title: "Duration", numeric: true}, {title: "Queue", numeric: true}, {title: "Runner"}}
+		s.rows = nil
+		for _, rec := range m.workflowRecords(s.workflow) {
+			runTime := rec.Run.RunStartedAt
+			if runTime.IsZero() {
+				runTime = rec.Run.CreatedAt
+			}
+			if runTime.Before(m.data.From) || runTime.After(m.data.To) {
+				continue
+			}
+			for _, job := range rec.Jobs {
+				if !matchesJob(job, s.job) {
+					continue
+				}
+				s.rows = append(s.rows, tableRow{
+					key:    strconv.FormatInt(job.ID, 10),
+					failed: m.data.Policy.Failed(rec, job.Conclusion, job.Status),
+					cells:  []string{strconv.FormatInt(rec.Run.ID, 10), strconv.Itoa(rec.Run.RunNumber), jobName(job), rec.Run.HeadBranch, job.Conclusion, formatTime(job.StartedAt), output.FormatDuration(job.Duration()), output.FormatDuration(job.QueueTime()), job.RunnerName},
+					values: []float64{float64(rec.Run.ID), float64(rec.Run.RunNumber), 0, 0, 0, float64(job.StartedAt.Unix()), float64(job.Duration()), float64(job.QueueTime()), 0},
+				})
+			}
+		}
+	case levelSteps:
+		s.columns = []column{{title: "#", numeric: true}, {title: "Step"}, {title: "Conclusion"}, {title: "Started"}, {title: "Duration", numeric: true}}
+		s.rows = nil
+		for _, rec := range m.workflowRecords(s.workflow) {
+			if rec.Run.ID != s.runID {
+				continue
+			}
+			for _, job := range rec.Jobs {
+				if job.ID != s.jobID {
+					continue
+				}
+				for _, step := range job.Steps {
+					s.rows = append(s.rows, tableRow{
+						key:    step.Name,
+						failed: m.data.Policy.Failed(rec, step.Conclusion, step.Status),
+						cells:  []string{strconv.Itoa(step.Number), step.Name, step.Conclusion, formatTime(step.StartedAt), output.FormatDuration(step.Duration())},
+						values: []float64{float64(step.Number), 0, 0, float64(step.StartedAt.Unix()), float64(step.Duration())},
+					})
+				}
+			}
+		}
+	}
+	s.sort()
+	s.clamp(0)
+}
+
+func (m Model) workflowRecords(workflowID int64) []metrics.RunRecord {
+	var out []metrics.RunRecord
+	for _, rec := range m.data.Records {
+		if rec.Workflow.ID == workflowID {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func summaryColumns(name string) []column {
+	return []column{{title: name}, {title: "Runs", numeric: true}, {title: "Failed", numeric: true}, {title: "Failure Rate", numeric: true}, {title: "Avg", numeric: true}, {title: "P95", numeric: true}, {title: "Total", numeric: true}}
+}
+
+func summaryRow(name, key string, runs, failed int, rate float64, avg, p95, total time.Duration) tableRow {
+	return tableRow{
+		key:    key,
+		cells:  []string{name, strconv.Itoa(runs), strconv.Itoa(failed), output.FormatFailureRate(rate), output.FormatDuration(avg), output.FormatDuration(p95), output.FormatDuration(total)},
+		values: []float64{0, float64(runs), float64(failed), rate, float64(avg), float64(p95), float64(total)},
+	}
+}
+
+func (s *screen) visible() []tableRow {
+	if s.filter == "" {
+		return s.rows
+	}
+	needle := strings.ToLower(s.filter)
+	var out []tableRow
+	for _, row := range s.rows {
+		for _, cell := range row.cells {
+			if strings.Contains(strings.ToLower(cell), needle) {
+				out = append(out, row)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// sort orders the rows by the sort column. Matrix cell rows are sorted among
+// themselves and placed directly under their parent row.
+func (s *screen) sort() {
+	col := s.sortCol
+	if col >= len(s.columns) {
+		return
+	}
+
+	var top []tableRow
+	children := make(map[string][]tableRow)
+	for _, row := range s.rows {
+		if row.parent == "" {
+			top = append(top, row)
+			continue
+		}
+		children[row.parent] = append(children[row.parent], row)
+	}
+
+	s.sortRows(top, col)
+	rows := make([]tableRow, 0, len(s.rows))
+	for _, row := range top {
+		rows = append(rows, row)
+		cells := children[row.key]
+		s.sortRows(cells, col)
+		rows = append(rows, cells...)
+	}
+	s.rows = rows
+}
+
+func (s *screen) sortRows(rows []tableRow, col int) {
+	numeric := s.columns[col].numeric
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		var less, equal bool
+		if numeric {
+			less, equal = a.values[col] < b.values[col], a.values[col] == b.values[col]
+		} else {
+			less, equal = a.cells[col] < b.cells[col], a.cells[col] == b.cells[col]
+		}
+		if equal {
+			return false
+		}
+		if s.sortDesc {
+			return !less
+		}
+		return less
+	})
+}
+
+// clamp keeps the cursor within the visible rows and scrolls so the cursor
+// stays on screen. A page size of zero only clamps the cursor.
+func (s *screen) clamp(page int) {
+	n := len(s.visible())
+	if s.cursor >= n {
+		s.cursor = n - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+	if page <= 0 {
+		return
+	}
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+page {
+		s.offset = s.cursor - page + 1
+	}
+}
+
+func columnWidths(columns []column, rows []tableRow) []int {
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = lipgloss.Width(col.title) + 2
+	}
+	for _, row := range rows {
+		for i, cell := range row.cells {
+			if w := lipgloss.Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	return widths
+}
+
+func pad(s string, width int, right bool) string {
+	gap := width - lipgloss.Width(s)
+	if gap <= 0 {
+		return s
+	}
+	if right {
+		return strings.Repeat(" ", gap) + s
+	}
+	return s + strings.Repeat(" ", gap)
+}
+
+// matchesJob reports whether job is the selected job or, when a matrix job
+// was selected by its base name, one of its cells.
+func matchesJob(job githubapi.WorkflowJob, selected string) bool {
+	name := jobName(job)
+	if name == selected {
+		return true
+	}
+	base, values := metrics.SplitMatrixJobName(name)
+	return len(values) > 0 && base == selected
+}
+
+func jobName(job githubapi.WorkflowJob) string {
+	name := strings.TrimSpace(job.Name)
+	if name == "" {
+		return "(unnamed)"
+	}
+	return name
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/gh-actrics/internal/githubapi"
+	"github.com/JohnTitor/gh-actrics/internal/metrics"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testData() Data {
+	base := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	build := githubapi.Workflow{ID: 1, Name: "build"}
+	docs := githubapi.Workflow{ID: 2, Name: "docs"}
+
+	job := func(id int64, name, conclusion string, start time.Time, d time.Duration) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			ID:          id,
+			Name:        name,
+			Conclusion:  conclusion,
+			StartedAt:   start,
+			CompletedAt: start.Add(d),
+			Steps: []githubapi.WorkflowStep{
+				{Name: "checkout", Number: 1, Conclusion: "success", StartedAt: start, CompletedAt: start.Add(time.Second)},
+				{Name: "run", Number: 2, Conclusion: conclusion, StartedAt: start.Add(time.Second), CompletedAt: start.Add(d)},
+			},
+		}
+	}
+
+	return Data{
+		From: base,
+		To:   base.Add(48 * time.Hour),
+		Records: []metrics.RunRecord{
+			{Workflow: build, Run: githubapi.WorkflowRun{ID: 10, RunNumber: 1, Conclusion: "success", CreatedAt: base.Add(time.Hour), Duration: 10 * time.Minute},
+				Jobs: []githubapi.WorkflowJob{job(100, "test", "success", base.Add(time.Hour), 8*time.Minute), job(101, "lint", "success", base.Add(time.Hour), time.Minute)}},
+			{Workflow: build, Run: githubapi.WorkflowRun{ID: 11, RunNumber: 2, Conclusion: "failure", CreatedAt: base.Add(2 * time.Hour), Duration: 12 * time.Minute},
+				Jobs: []githubapi.WorkflowJob{job(110, "test", "failure", base.Add(2*time.Hour), 11*time.Minute)}},
+			{Workflow: docs, Run: githubapi.WorkflowRun{ID: 20, RunNumber: 1, Conclusion: "success", CreatedAt: base.Add(3 * time.Hour), Duration: 2 * time.Minute}},
+		},
+	}
+}
+
+func press(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+func TestModelDrillDown(t *testing.T) {
+	m := New(context.Background(), "owner/repo", testData(), nil)
+
+	if got := m.current().rows[0].cells[0]; got != "build" {
+		t.Fatalf("expected workflows sorted by name, got %s first", got)
+	}
+
+	m = press(t, m, "enter")
+	if s := m.current(); s.level != levelJobs || len(s.rows) != 2 {
+		t.Fatalf("expected jobs of build, got level %d with %d rows", s.level, len(s.rows))
+	}
+
+	m = press(t, m, "s", "enter")
+	s := m.current()
+	if s.level != levelRuns || s.job != "test" {
+		t.Fatalf("expected runs of the longest-running job, got %#v", s)
+	}
+	if len(s.rows) != 2 || s.rows[0].cells[0] != "11" {
+		t.Fatalf("expected most recent run first, got %#v", s.rows)
+	}
+
+	m = press(t, m, "enter")
+	if s := m.current(); s.level != levelSteps || len(s.rows) != 2 || s.runID != 11 || s.jobID != 110 {
+		t.Fatalf("expected steps of run 11, got %#v", s)
+	}
+	if view := m.View(); !strings.Contains(view, "build › test › Run 11") {
+		t.Fatalf("expected breadcrumb in view, got:\n%s", view)
+	}
+
+	m = press(t, m, "esc", "esc", "esc")
+	if m.current().level != levelWorkflows {
+		t.Fatalf("expected to return to workflows")
+	}
+}
+
+func TestModelMatrixSteps(t *testing.T) {
+	base := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	build := githubapi.Workflow{ID: 1, Name: "build"}
+	cell := func(id int64, name, step string) githubapi.WorkflowJob {
+		return githubapi.WorkflowJob{
+			ID: id, Name: name, Conclusion: "success", StartedAt: base.Add(time.Hour), CompletedAt: base.Add(2 * time.Hour),
+			Steps: []githubapi.WorkflowStep{{Name: step, Number: 1, Conclusion: "success", StartedAt: base.Add(time.Hour), CompletedAt: base.Add(2 * time.Hour)}},
+		}
+	}
+	data := Data{
+		From: base,
+		To:   base.Add(48 * time.Hour),
+		Records: []metrics.RunRecord{
+			{Workflow: build, Run: githubapi.WorkflowRun{ID: 10, RunNumber: 1, Conclusion: "success", CreatedAt: base.Add(time.Hour)},
+				Jobs: []githubapi.WorkflowJob{cell(100, "test (linux)", "apt-get"), cell(101, "test (windows)", "choco")}},
+			// Outside the window.
+			{Workflow: build, Run: githubapi.WorkflowRun{ID: 9, RunNumber: 0, Conclusion: "success", CreatedAt: base.Add(-time.Hour)},
+				Jobs: []githubapi.WorkflowJob{cell(90, "test (linux)", "apt-get")}},
+		},
+	}
+
+	m := New(context.Background(), "owner/repo", data, nil)
+	m = press(t, m, "enter")
+	jobs := m.current()
+	if len(jobs.rows) != 3 || jobs.rows[0].cells[0] != "test" || jobs.rows[1].parent != "test" || jobs.rows[2].parent != "test" {
+		t.Fatalf("expected matrix cells under their job, got %#v", jobs.rows)
+	}
+	// Sorting by another column keeps the cells under their job.
+	m = press(t, m, "s", "S")
+	if rows := m.current().rows; rows[0].cells[0] != "test" {
+		t.Fatalf("expected the job before its cells after sorting, got %#v", rows)
+	}
+
+	m = press(t, m, "enter")
+	s := m.current()
+	if s.level != levelRuns || s.job != "test" || len(s.rows) != 2 {
+		t.Fatalf("expected one row per matrix cell inside the window, got %#v", s.rows)
+	}
+
+	m = press(t, m, "enter")
+	s = m.current()
+	if s.level != levelSteps || len(s.rows) != 1 || !strings.HasPrefix(s.title, "Run 10 · test (") {
+		t.Fatalf("expected the steps of a single matrix cell, got %q %#v", s.title, s.rows)
+	}
+}
+
+func TestModelFilterAndSort(t *testing.T) {
+	m := New(context.Background(), "owner/repo", testData(), nil)
+
+	m = press(t, m, "/", "d", "o", "enter")
+	rows := m.current().visible()
+	if len(rows) != 1 || rows[0].cells[0] != "docs" {
+		t.Fatalf("expected filter to keep docs only, got %#v", rows)
+	}
+	m = press(t, m, "esc")
+	if len(m.current().visible()) != 2 {
+		t.Fatalf("expected esc to clear filter")
+	}
+
+	// Sort by run count (descending), then reverse it.
+	m = press(t, m, "s")
+	if got := m.current().rows[0].cells[0]; got != "build" {
+		t.Fatalf("expected build first by runs, got %s", got)
+	}
+	m = press(t, m, "S")
+	if got := m.current().rows[0].cells[0]; got != "docs" {
+		t.Fatalf("expected docs first after reversing, got %s", got)
+	}
+}
+
+func TestModelRefresh(t *testing.T) {
+	calls := 0
+	load := func(context.Context) (Data, error) {
+		calls++
+		data := testData()
+		data.Records = data.Records[:1]
+		return data, nil
+	}
+	m := New(context.Background(), "owner/repo", testData(), load)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(Model)
+	if cmd == nil || !m.refreshing {
+		t.Fatalf("expected refresh command")
+	}
+
+	next, _ = m.Update(cmd())
+	m = next.(Model)
+	if calls != 1 || m.refreshing {
+		t.Fatalf("expected refresh to complete once, calls=%d", calls)
+	}
+	if len(m.current().rows) != 1 {
+		t.Fatalf("expected refreshed data to be shown, got %d rows", len(m.current().rows))
+	}
+}
@@ -0,0 +1,354 @@
+// Package workflowfile parses GitHub Actions workflow definitions so observed
+// run data can be compared with what the workflow declares.
+package workflowfile
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTimeoutMinutes is the job timeout GitHub applies when
+// timeout-minutes is not set.
+const DefaultTimeoutMinutes = 360
+
+// Workflow is the subset of a workflow file relevant to metrics.
+type Workflow struct {
+	Name        string       `json:"name,omitempty"`
+	Events      []string     `json:"events,omitempty"`
+	Schedules   []string     `json:"schedules,omitempty"`
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
+	Jobs        []Job        `json:"jobs"`
+}
+
+// Job is a job declared in a workflow file.
+type Job struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Needs lists the IDs of the jobs this job depends on.
+	Needs  []string `json:"needs,omitempty"`
+	RunsOn []string `json:"runs_on,omitempty"`
+	// TimeoutMinutes is zero when timeout-minutes is unset or an expression.
+	TimeoutMinutes int          `json:"timeout_minutes,omitempty"`
+	Matrix         *Matrix      `json:"matrix,omitempty"`
+	Concurrency    *Concurrency `json:"concurrency,omitempty"`
+	// Uses is set for jobs that call a reusable workflow.
+	Uses string `json:"uses,omitempty"`
+
+	// pattern is the compiled name pattern, set by Parse. wildcard reports
+	// that the name is made of expressions only, so the pattern matches any
+	// job name.
+	pattern  *regexp.Regexp
+	wildcard bool
+}
+
+// Matrix describes a job's strategy matrix.
+type Matrix struct {
+	Axes    []Axis `json:"axes,omitempty"`
+	Include int    `json:"include,omitempty"`
+	Exclude int    `json:"exclude,omitempty"`
+	// Expression is set when the whole matrix is computed at runtime.
+	Expression string `json:"expression,omitempty"`
+}
+
+// Axis is one dimension of a matrix.
+type Axis struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Concurrency is a workflow- or job-level concurrency setting.
+type Concurrency struct {
+	Group            string `json:"group"`
+	CancelInProgress bool   `json:"cancel_in_progress,omitempty"`
+}
+
+type rawWorkflow struct {
+	Name        string    `yaml:"name"`
+	On          yaml.Node `yaml:"on"`
+	Concurrency yaml.Node `yaml:"concurrency"`
+	Jobs        yaml.Node `yaml:"jobs"`
+}
+
+type rawJob struct {
+	Name           string    `yaml:"name"`
+	Needs          yaml.Node `yaml:"needs"`
+	RunsOn         yaml.Node `yaml:"runs-on"`
+	TimeoutMinutes yaml.Node `yaml:"timeout-minutes"`
+	Strategy       struct {
+		Matrix yaml.Node `yaml:"matrix"`
+	} `yaml:"strategy"`
+	Concurrency yaml.Node `yaml:"concurrency"`
+	Uses        string    `yaml:"uses"`
+}
+
+// Parse decodes a workflow file.
+func Parse(data []byte) (*Workflow, error) {
+	var raw rawWorkflow
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid workflow file: %w", err)
+	}
+
+	wf := &Workflow{
+		Name:        raw.Name,
+		Concurrency: parseConcurrency(&raw.Concurrency),
+	}
+	wf.Events, wf.Schedules = parseTriggers(&raw.On)
+
+	if raw.Jobs.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid workflow file: jobs must be a mapping")
+	}
+	for i := 0; i+1 < len(raw.Jobs.Content); i += 2 {
+		id := raw.Jobs.Content[i].Value
+		var rj rawJob
+		if err := raw.Jobs.Content[i+1].Decode(&rj); err != nil {
+			return nil, fmt.Errorf("invalid job %q: %w", id, err)
+		}
+
+		job := Job{
+			ID:          id,
+			Name:        rj.Name,
+			Needs:       scalarList(&rj.Needs),
+			RunsOn:      parseRunsOn(&rj.RunsOn),
+			Matrix:      parseMatrix(&rj.Strategy.Matrix),
+			Concurrency: parseConcurrency(&rj.Concurrency),
+			Uses:        rj.Uses,
+		}
+		job.pattern, job.wildcard = job.compileNamePattern()
+		if rj.TimeoutMinutes.Kind == yaml.ScalarNode {
+			if minutes, err := strconv.ParseFloat(rj.TimeoutMinutes.Value, 64); err == nil && minutes > 0 {
+				job.TimeoutMinutes = int(minutes)
+			}
+		}
+		wf.Jobs = append(wf.Jobs, job)
+	}
+
+	return wf, nil
+}
+
+// ParseFile reads and decodes the workflow file at path.
+func ParseFile(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return wf, nil
+}
+
+// DisplayName is the name GitHub shows for the job before expressions and
+// matrix values are applied.
+func (j Job) DisplayName() string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return j.ID
+}
+
+// Timeout is the effective job timeout, falling back to GitHub's default.
+func (j Job) Timeout() time.Duration {
+	if j.TimeoutMinutes > 0 {
+		return time.Duration(j.TimeoutMinutes) * time.Minute
+	}
+	return DefaultTimeoutMinutes * time.Minute
+}
+
+var expressionPattern = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// compileNamePattern builds a pattern matching the names GitHub reports for
+// runs of the job: the display name with expressions evaluated, optionally
+// followed by matrix values in parentheses or, for reusable workflows,
+// " / <called job>". It also reports whether the name has no literal text.
+func (j Job) compileNamePattern() (*regexp.Regexp, bool) {
+	name := j.DisplayName()
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	literal := false
+	for _, loc := range expressionPattern.FindAllStringIndex(name, -1) {
+		literal = literal || strings.TrimSpace(name[last:loc[0]]) != ""
+		b.WriteString(regexp.QuoteMeta(name[last:loc[0]]))
+		b.WriteString(".+?")
+		last = loc[1]
+	}
+	literal = literal || strings.TrimSpace(name[last:]) != ""
+	b.WriteString(regexp.QuoteMeta(name[last:]))
+	b.WriteString(`( \(.*\))?( / .*)?$`)
+	return regexp.MustCompile(b.String()), !literal
+}
+
+// namePattern returns the job's name pattern, compiling it for jobs that
+// were not produced by Parse.
+func (j *Job) namePattern() (*regexp.Regexp, bool) {
+	if j.pattern != nil {
+		return j.pattern, j.wildcard
+	}
+	return j.compileNamePattern()
+}
+
+// MatchJob returns the declared job that produced a job run with the given
+// name, or nil when none matches. Exact display names are preferred, then
+// job IDs, then names with expressions. A name made only of expressions, such
+// as "${{ matrix.os }}", matches any job run, so it is tried last.
+func (w *Workflow) MatchJob(name string) *Job {
+	for i := range w.Jobs {
+		if w.Jobs[i].DisplayName() == name {
+			return &w.Jobs[i]
+		}
+	}
+	for i := range w.Jobs {
+		if w.Jobs[i].ID == name {
+			return &w.Jobs[i]
+		}
+	}
+
+	var fallback *Job
+	for i := range w.Jobs {
+		pattern, wildcard := w.Jobs[i].namePattern()
+		if !pattern.MatchString(name) {
+			continue
+		}
+		if !wildcard {
+			return &w.Jobs[i]
+		}
+		if fallback == nil {
+			fallback = &w.Jobs[i]
+		}
+	}
+	return fallback
+}
+
+func parseTriggers(node *yaml.Node) ([]string, []string) {
+	switch node.Kind {
+	case yaml.ScalarNode, yaml.SequenceNode:
+		return scalarList(node), nil
+	case yaml.MappingNode:
+		var events, schedules []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			event := node.Content[i].Value
+			events = append(events, event)
+			if event != "schedule" || node.Content[i+1].Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, entry := range node.Content[i+1].Content {
+				var schedule struct {
+					Cron string `yaml:"cron"`
+				}
+				if err := entry.Decode(&schedule); err == nil && schedule.Cron != "" {
+					schedules = append(schedules, schedule.Cron)
+				}
+			}
+		}
+		return events, schedules
+	}
+	return nil, nil
+}
+
+func parseRunsOn(node *yaml.Node) []string {
+	if node.Kind != yaml.MappingNode {
+		return scalarList(node)
+	}
+	// runs-on: {group: ..., labels: ...}
+	var labels []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "group":
+			labels = append(labels, "group:"+node.Content[i+1].Value)
+		case "labels":
+			labels = append(labels, scalarList(node.Content[i+1])...)
+		}
+	}
+	return labels
+}
+
+func parseMatrix(node *yaml.Node) *Matrix {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return &Matrix{Expression: node.Value}
+	case yaml.MappingNode:
+	default:
+		return nil
+	}
+
+	matrix := &Matrix{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "include":
+			matrix.Include = len(value.Content)
+		case "exclude":
+			matrix.Exclude = len(value.Content)
+		default:
+			axis := Axis{Name: key}
+			if value.Kind == yaml.SequenceNode {
+				for _, v := range value.Content {
+					axis.Values = append(axis.Values, nodeString(v))
+				}
+			} else {
+				axis.Values = []string{nodeString(value)}
+			}
+			matrix.Axes = append(matrix.Axes, axis)
+		}
+	}
+	return matrix
+}
+
+func parseConcurrency(node *yaml.Node) *Concurrency {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return &Concurrency{Group: node.Value}
+	case yaml.MappingNode:
+		var c struct {
+			Group            string `yaml:"group"`
+			CancelInProgress string `yaml:"cancel-in-progress"`
+		}
+		if err := node.Decode(&c); err != nil {
+			return nil
+		}
+		return &Concurrency{Group: c.Group, CancelInProgress: c.CancelInProgress == "true"}
+	}
+	return nil
+}
+
+// scalarList accepts either a single scalar or a sequence of scalars.
+func scalarList(node *yaml.Node) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value == "" {
+			return nil
+		}
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				values = append(values, item.Value)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// nodeString renders a matrix value; non-scalar values (objects used as
+// matrix entries) are rendered as flow-style YAML.
+func nodeString(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	copied := *node
+	copied.Style = yaml.FlowStyle
+	data, err := yaml.Marshal(&copied)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
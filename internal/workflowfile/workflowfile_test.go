@@ -0,0 +1,179 @@
+package workflowfile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sampleWorkflow = `
+name: CI
+on:
+  push:
+    branches: [main]
+  pull_request:
+  schedule:
+    - cron: "0 3 * * 1"
+concurrency:
+  group: ci-${{ github.ref }}
+  cancel-in-progress: true
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    timeout-minutes: 20
+    steps:
+      - run: make
+  test:
+    name: Test ${{ matrix.os }}
+    needs: build
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+        go: ["1.24", "1.25"]
+        include:
+          - os: windows-latest
+            go: "1.25"
+  deploy:
+    needs: [build, test]
+    runs-on:
+      group: production
+      labels: [self-hosted, linux]
+    concurrency: deploy
+  release:
+    uses: ./.github/workflows/release.yml
+    strategy:
+      matrix: ${{ fromJSON(needs.build.outputs.targets) }}
+`
+
+func TestParse(t *testing.T) {
+	wf, err := Parse([]byte(sampleWorkflow))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if wf.Name != "CI" {
+		t.Fatalf("unexpected name %q", wf.Name)
+	}
+	if !reflect.DeepEqual(wf.Events, []string{"push", "pull_request", "schedule"}) {
+		t.Fatalf("unexpected events %v", wf.Events)
+	}
+	if !reflect.DeepEqual(wf.Schedules, []string{"0 3 * * 1"}) {
+		t.Fatalf("unexpected schedules %v", wf.Schedules)
+	}
+	if wf.Concurrency == nil || wf.Concurrency.Group != "ci-${{ github.ref }}" || !wf.Concurrency.CancelInProgress {
+		t.Fatalf("unexpected concurrency %+v", wf.Concurrency)
+	}
+	if len(wf.Jobs) != 4 {
+		t.Fatalf("expected 4 jobs, got %d", len(wf.Jobs))
+	}
+
+	build, test, deploy, release := wf.Jobs[0], wf.Jobs[1], wf.Jobs[2], wf.Jobs[3]
+	if build.ID != "build" || build.TimeoutMinutes != 20 || build.Timeout() != 20*time.Minute {
+		t.Fatalf("unexpected build job %+v", build)
+	}
+	if !reflect.DeepEqual(build.RunsOn, []string{"ubuntu-latest"}) {
+		t.Fatalf("unexpected build runs-on %v", build.RunsOn)
+	}
+
+	if test.Timeout() != DefaultTimeoutMinutes*time.Minute {
+		t.Fatalf("expected default timeout, got %v", test.Timeout())
+	}
+	if !reflect.DeepEqual(test.Needs, []string{"build"}) {
+		t.Fatalf("unexpected test needs %v", test.Needs)
+	}
+	wantMatrix := &Matrix{
+		Axes: []Axis{
+			{Name: "os", Values: []string{"ubuntu-latest", "macos-latest"}},
+			{Name: "go", Values: []string{"1.24", "1.25"}},
+		},
+		Include: 1,
+	}
+	if !reflect.DeepEqual(test.Matrix, wantMatrix) {
+		t.Fatalf("unexpected matrix %+v", test.Matrix)
+	}
+
+	if !reflect.DeepEqual(deploy.Needs, []string{"build", "test"}) {
+		t.Fatalf("unexpected deploy needs %v", deploy.Needs)
+	}
+	if !reflect.DeepEqual(deploy.RunsOn, []string{"group:production", "self-hosted", "linux"}) {
+		t.Fatalf("unexpected deploy runs-on %v", deploy.RunsOn)
+	}
+	if deploy.Concurrency == nil || deploy.Concurrency.Group != "deploy" || deploy.Concurrency.CancelInProgress {
+		t.Fatalf("unexpected deploy concurrency %+v", deploy.Concurrency)
+	}
+
+	if release.Uses != "./.github/workflows/release.yml" || release.Matrix == nil || release.Matrix.Expression == "" {
+		t.Fatalf("unexpected release job %+v", release)
+	}
+}
+
+func TestParseRejectsMissingJobs(t *testing.T) {
+	if _, err := Parse([]byte("name: empty\non: push\n")); err == nil {
+		t.Fatal("expected error for workflow without jobs")
+	}
+}
+
+func TestMatchJob(t *testing.T) {
+	wf, err := Parse([]byte(sampleWorkflow))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	cases := map[string]string{
+		"build":                           "build",
+		"Test ubuntu-latest":              "test",
+		"Test macos-latest (macos, 1.25)": "test",
+		"deploy":                          "deploy",
+		"release / publish (linux-amd64)": "release",
+		"build-docs":                      "",
+		"Unit tests":                      "",
+	}
+	for name, want := range cases {
+		job := wf.MatchJob(name)
+		got := ""
+		if job != nil {
+			got = job.ID
+		}
+		if got != want {
+			t.Errorf("MatchJob(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMatchJobExpressionOnlyName(t *testing.T) {
+	wf, err := Parse([]byte(`
+on: push
+jobs:
+  matrix:
+    name: ${{ matrix.os }}
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+  lint:
+    name: Lint
+    runs-on: ubuntu-latest
+  test:
+    runs-on: ubuntu-latest
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	cases := map[string]string{
+		"Lint":          "lint",
+		"test":          "test",
+		"ubuntu-latest": "matrix",
+	}
+	for name, want := range cases {
+		job := wf.MatchJob(name)
+		got := ""
+		if job != nil {
+			got = job.ID
+		}
+		if got != want {
+			t.Errorf("MatchJob(%q) = %q, want %q", name, got, want)
+		}
+	}
+}